@@ -72,6 +72,44 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last epoch to write. If the file ends with .gz, the output will
 be gzipped
+`,
+			},
+			{
+				Name:      "cheaters",
+				Usage:     "Export double-sign evidences",
+				ArgsUsage: "<filename> [<epochFrom> <epochTo>]",
+				Action:    utils.MigrateFlags(exportCheaters),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    skyhigh export cheaters
+
+Requires a first argument of the JSON file to write to.
+Optional second and third arguments control the first and
+last epoch to scan. For each validator which double-signed,
+the pairs of conflicting events are written along with their
+signatures and serialized headers.
+`,
+			},
+			{
+				Name:      "dag",
+				Usage:     "Export DAG of an epoch for visualisation",
+				ArgsUsage: "<filename> <epoch> [<lamportFrom> <lamportTo>]",
+				Action:    utils.MigrateFlags(exportDag),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    skyhigh export dag
+
+Requires a first argument of the file to write to, and a second
+argument of the epoch. Optional third and fourth arguments
+control the first and last Lamport time of the exported events.
+Events are written with parents, creator, frame, root/Atropos flags
+and the confirmation block. If the file name ends with .dot or .gv,
+the graph is written in Graphviz DOT format, otherwise
+in JSON Graph Format.
 `,
 			},
 		},
@@ -2,7 +2,6 @@ package launcher
 
 import (
 	"bytes"
-	"path"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
@@ -16,7 +15,6 @@ import (
 	"github.com/skyhighblockchain/push-base/utils/simplewlru"
 	"gopkg.in/urfave/cli.v1"
 
-	"github.com/skyhighblockchain/skyhigh/integration"
 	"github.com/skyhighblockchain/skyhigh/inter"
 )
 
@@ -32,7 +30,7 @@ func checkEvm(ctx *cli.Context) error {
 
 	cfg := makeAllConfigs(ctx)
 
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, cfg)
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
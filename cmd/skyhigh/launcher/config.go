@@ -21,6 +21,8 @@ import (
 	"github.com/naoina/toml"
 	"github.com/skyhighblockchain/push-base/abft"
 	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb"
 	"github.com/skyhighblockchain/push-base/utils/cachescale"
 	"gopkg.in/urfave/cli.v1"
 
@@ -73,6 +75,29 @@ var (
 		Value: DefaultCacheSize,
 	}
 
+	AncientEpochsFlag = cli.Uint64Flag{
+		Name:  "ancient.epochs",
+		Usage: "Number of recent epochs, which blocks, events, txs and receipts are kept in the key-value DB. Older data is moved into the ancient store (0 = disabled)",
+	}
+
+	HistoryBlocksFlag = cli.Uint64Flag{
+		Name:  "history.blocks",
+		Usage: "Number of recent blocks, which receipts, logs and txs index are kept. Older history is pruned (0 = keep all)",
+	}
+	HistoryEpochsFlag = cli.Uint64Flag{
+		Name:  "history.epochs",
+		Usage: "Number of recent epochs, which receipts, logs, txs index and events are kept. Older history is pruned (0 = keep all)",
+	}
+	DBBackendFlag = cli.StringFlag{
+		Name:  "db.backend",
+		Usage: "Key-value backend of new databases: leveldb or pebble. Existing databases are opened with their own backend",
+		Value: integration.DefaultDBsConfig().Backend,
+	}
+	AddressIndexFlag = cli.BoolFlag{
+		Name:  "index.addresses",
+		Usage: "Enables indexing of transactions by address, which is required for skh_getTransactionsByAddress",
+	}
+
 	// GenesisFlag specifies network genesis configuration
 	GenesisFlag = cli.StringFlag{
 		Name:  "genesis",
@@ -89,6 +114,16 @@ var (
 		Usage: "Sets a cap on transaction fee (in SKH) that can be sent via the RPC APIs (0 = no cap)",
 		Value: gossip.DefaultConfig(cachescale.Identity).RPCTxFeeCap,
 	}
+	RPCLogsBlockRangeFlag = cli.Uint64Flag{
+		Name:  "rpc.logs.blockrange",
+		Usage: "Sets a max blocks range of indexed logs search",
+		Value: uint64(gossip.DefaultConfig(cachescale.Identity).FilterAPI.IndexedLogsBlockRangeLimit),
+	}
+	RPCLogsMaxResultsFlag = cli.IntFlag{
+		Name:  "rpc.logs.maxresults",
+		Usage: "Sets a max number of logs returned by a logs search, and a max size of a logs page (0 = no limit)",
+		Value: gossip.DefaultConfig(cachescale.Identity).FilterAPI.LogsResultsLimit,
+	}
 
 	AllowedSkyhighGenesisHashes = map[uint64]hash.Hash{
 		skyhigh.MainNetworkID: hash.HexToHash("0x8895b98d25c653773a31be420a6a29d322a10107e76dff37dc694ad02ebacd01"),
@@ -123,6 +158,7 @@ type config struct {
 	Push         abft.Config
 	PushStore    abft.StoreConfig
 	VectorClock  vecmt.IndexConfig
+	DBs          integration.DBsConfig
 }
 
 func (c *config) AppConfigs() integration.Configs {
@@ -160,26 +196,14 @@ func getSkyhighGenesis(ctx *cli.Context) integration.InputGenesis {
 
 	var genesis integration.InputGenesis
 	switch {
+	case ctx.GlobalBool(DevFlag.Name):
+		genesis = memGenesis(makeDevGenesisStore(ctx))
 	case ctx.GlobalIsSet(FakeNetFlag.Name):
 		_, num, err := parseFakeGen(ctx.GlobalString(FakeNetFlag.Name))
 		if err != nil {
 			log.Crit("Invalid flag", "flag", FakeNetFlag.Name, "err", err)
 		}
-		fakeGenesisStore := makegenesis.FakeGenesisStore(num, futils.ToSkh(1000000000), futils.ToSkh(5000000))
-		genesis = integration.InputGenesis{
-			Hash: fakeGenesisStore.Hash(),
-			Read: func(store *genesisstore.Store) error {
-				buf := bytes.NewBuffer(nil)
-				err = fakeGenesisStore.Export(buf)
-				if err != nil {
-					return err
-				}
-				return store.Import(buf)
-			},
-			Close: func() error {
-				return nil
-			},
-		}
+		genesis = memGenesis(makegenesis.FakeGenesisStore(num, futils.ToSkh(1000000000), futils.ToSkh(5000000)))
 	case ctx.GlobalIsSet(GenesisFlag.Name):
 		genesisPath := ctx.GlobalString(GenesisFlag.Name)
 
@@ -203,6 +227,24 @@ func getSkyhighGenesis(ctx *cli.Context) integration.InputGenesis {
 	return genesis
 }
 
+// memGenesis returns an input genesis, which is read from the in-memory genesis store
+func memGenesis(genStore *genesisstore.Store) integration.InputGenesis {
+	return integration.InputGenesis{
+		Hash: genStore.Hash(),
+		Read: func(store *genesisstore.Store) error {
+			buf := bytes.NewBuffer(nil)
+			err := genStore.Export(buf)
+			if err != nil {
+				return err
+			}
+			return store.Import(buf)
+		},
+		Close: func() error {
+			return nil
+		},
+	}
+}
+
 func setBootnodes(ctx *cli.Context, urls []string, cfg *node.Config) {
 	cfg.P2P.BootstrapNodesV5 = []*enode.Node{}
 	for _, url := range urls {
@@ -224,6 +266,8 @@ func setDataDir(ctx *cli.Context, cfg *node.Config) {
 	switch {
 	case ctx.GlobalIsSet(utils.DataDirFlag.Name):
 		cfg.DataDir = ctx.GlobalString(utils.DataDirFlag.Name)
+	case ctx.GlobalBool(DevFlag.Name):
+		cfg.DataDir = filepath.Join(defaultDataDir, "dev")
 	case ctx.GlobalIsSet(FakeNetFlag.Name):
 		_, num, err := parseFakeGen(ctx.GlobalString(FakeNetFlag.Name))
 		if err != nil {
@@ -296,6 +340,15 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config,
 	if ctx.GlobalIsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.GlobalFloat64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCLogsBlockRangeFlag.Name) {
+		cfg.FilterAPI.IndexedLogsBlockRangeLimit = idx.Block(ctx.GlobalUint64(RPCLogsBlockRangeFlag.Name))
+	}
+	if ctx.GlobalIsSet(RPCLogsMaxResultsFlag.Name) {
+		cfg.FilterAPI.LogsResultsLimit = ctx.GlobalInt(RPCLogsMaxResultsFlag.Name)
+	}
+	if ctx.GlobalIsSet(AddressIndexFlag.Name) {
+		cfg.AddressIndex = ctx.GlobalBool(AddressIndexFlag.Name)
+	}
 
 	err := setValidator(ctx, &cfg.Emitter)
 	if err != nil {
@@ -310,19 +363,36 @@ func gossipStoreConfigWithFlags(ctx *cli.Context, src gossip.StoreConfig) (gossi
 	if !ctx.GlobalBool(utils.SnapshotFlag.Name) {
 		cfg.EVM.EnableSnapshots = false
 	}
+	if ctx.GlobalIsSet(AncientEpochsFlag.Name) {
+		cfg.Ancient.Epochs = idx.Epoch(ctx.GlobalUint64(AncientEpochsFlag.Name))
+	}
+	if ctx.GlobalIsSet(HistoryBlocksFlag.Name) {
+		cfg.History.Blocks = idx.Block(ctx.GlobalUint64(HistoryBlocksFlag.Name))
+	}
+	if ctx.GlobalIsSet(HistoryEpochsFlag.Name) {
+		cfg.History.Epochs = idx.Epoch(ctx.GlobalUint64(HistoryEpochsFlag.Name))
+	}
 	return cfg, nil
 }
 
 func nodeConfigWithFlags(ctx *cli.Context, cfg node.Config) node.Config {
 	utils.SetNodeConfig(ctx, &cfg)
 
-	if !ctx.GlobalIsSet(FakeNetFlag.Name) {
+	if !ctx.GlobalIsSet(FakeNetFlag.Name) && !ctx.GlobalBool(DevFlag.Name) {
 		setBootnodes(ctx, Bootnodes, &cfg)
 	}
+	if ctx.GlobalBool(DevFlag.Name) {
+		setDevNodeConfig(&cfg)
+	}
 	setDataDir(ctx, &cfg)
 	return cfg
 }
 
+// makeDBProducer returns producer of the node databases
+func makeDBProducer(ctx *cli.Context, cfg *config) kvdb.IterableDBProducer {
+	return integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx), cfg.DBs)
+}
+
 func cacheScaler(ctx *cli.Context) cachescale.Func {
 	if !ctx.GlobalIsSet(CacheFlag.Name) {
 		return cachescale.Identity
@@ -347,12 +417,17 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		Push:         abft.DefaultConfig(),
 		PushStore:    abft.DefaultStoreConfig(cacheRatio),
 		VectorClock:  vecmt.DefaultConfig(cacheRatio),
+		DBs:          integration.DefaultDBsConfig(),
 	}
 
 	if ctx.GlobalIsSet(FakeNetFlag.Name) {
 		_, num, _ := parseFakeGen(ctx.GlobalString(FakeNetFlag.Name))
 		cfg.Skyhigh = gossip.FakeConfig(num, cacheRatio)
 	}
+	if ctx.GlobalBool(DevFlag.Name) {
+		cfg.Skyhigh = gossip.DevConfig(devEmitterValidators(ctx), cacheRatio)
+		cfg.DBs.Backend = integration.MemoryBackend
+	}
 
 	// Load config file (medium priority)
 	if file := ctx.GlobalString(configFileFlag.Name); file != "" {
@@ -372,13 +447,28 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		return nil, err
 	}
 	cfg.Node = nodeConfigWithFlags(ctx, cfg.Node)
+	if ctx.GlobalIsSet(DBBackendFlag.Name) {
+		cfg.DBs.Backend = ctx.GlobalString(DBBackendFlag.Name)
+	}
+	if len(cfg.SkyhighStore.Ancient.Dir) == 0 {
+		cfg.SkyhighStore.Ancient.Dir = path.Join(cfg.Node.DataDir, "ancient")
+	}
 	if cfg.Skyhigh.Emitter.Validator.ID != 0 && len(cfg.Skyhigh.Emitter.PrevEmittedEventFile.Path) == 0 {
 		cfg.Skyhigh.Emitter.PrevEmittedEventFile.Path = cfg.Node.ResolvePath(path.Join("emitter", fmt.Sprintf("last-%d", cfg.Skyhigh.Emitter.Validator.ID)))
 	}
+	if cfg.Skyhigh.Emitter.Validator.ID != 0 && len(cfg.Skyhigh.Emitter.MaintenanceFile) == 0 {
+		cfg.Skyhigh.Emitter.MaintenanceFile = cfg.Node.ResolvePath(path.Join("emitter", fmt.Sprintf("maintenance-%d", cfg.Skyhigh.Emitter.Validator.ID)))
+	}
+	if cfg.Skyhigh.Emitter.Validator.ID != 0 && len(cfg.Skyhigh.Emitter.KeyRotationFile) == 0 {
+		cfg.Skyhigh.Emitter.KeyRotationFile = cfg.Node.ResolvePath(path.Join("emitter", fmt.Sprintf("keyrotation-%d", cfg.Skyhigh.Emitter.Validator.ID)))
+	}
 
 	if err := cfg.Skyhigh.Validate(); err != nil {
 		return nil, err
 	}
+	if err := cfg.DBs.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -0,0 +1,122 @@
+package launcher
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/gossip"
+	"github.com/skyhighblockchain/skyhigh/utils"
+)
+
+// configReloader re-reads the config file of a running node, and applies the hot-reloadable fields
+type configReloader struct {
+	ctx *cli.Context
+	// running is the config which the node was started with
+	running *config
+	svc     *gossip.Service
+	mu      sync.Mutex
+}
+
+// Reload reloads the config file. The changed fields are prefixed with the config section, e.g. Skyhigh.GPO.MaxPrice
+func (r *configReloader) Reload() (gossip.ConfigReload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := gossip.ConfigReload{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+	if r.ctx.GlobalString(configFileFlag.Name) == "" {
+		return res, errors.New("node is started without a config file, see --" + configFileFlag.Name)
+	}
+	cfg, err := mayMakeAllConfigs(r.ctx)
+	if err != nil {
+		return res, err
+	}
+
+	// only subsets of the gossip config are hot-reloadable
+	for _, section := range []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"Node", r.running.Node, cfg.Node},
+		{"SkyhighStore", r.running.SkyhighStore, cfg.SkyhighStore},
+		{"Push", r.running.Push, cfg.Push},
+		{"PushStore", r.running.PushStore, cfg.PushStore},
+		{"VectorClock", r.running.VectorClock, cfg.VectorClock},
+		{"DBs", r.running.DBs, cfg.DBs},
+	} {
+		for _, field := range utils.ConfigDiff(section.old, section.new) {
+			res.RestartRequired = append(res.RestartRequired, section.name+"."+field)
+		}
+	}
+	gossipRes, err := r.svc.ReloadConfig(cfg.Skyhigh)
+	if err != nil {
+		return res, err
+	}
+	for _, field := range gossipRes.Applied {
+		res.Applied = append(res.Applied, "Skyhigh."+field)
+	}
+	for _, field := range gossipRes.RestartRequired {
+		res.RestartRequired = append(res.RestartRequired, "Skyhigh."+field)
+	}
+
+	log.Info("Config reloaded", "applied", strings.Join(res.Applied, ","))
+	if len(res.RestartRequired) != 0 {
+		log.Warn("Config changes require a restart", "fields", strings.Join(res.RestartRequired, ","))
+	}
+	return res, nil
+}
+
+// watchSIGHUP reloads the config on every SIGHUP, until the returned stop function is called
+func (r *configReloader) watchSIGHUP() (stop func()) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigc:
+				log.Info("Got SIGHUP, reloading the config")
+				if _, err := r.Reload(); err != nil {
+					log.Error("Failed to reload the config", "err", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigc)
+		close(done)
+	}
+}
+
+// PrivateConfigAPI provides an API to reload the config file of a running node.
+type PrivateConfigAPI struct {
+	r *configReloader
+}
+
+// ReloadConfig re-reads the config file, and applies the changes of the emitter, txpool and GPO configs.
+// It returns the applied changed fields, and the changed fields which take effect only after a restart.
+func (api *PrivateConfigAPI) ReloadConfig() (gossip.ConfigReload, error) {
+	return api.r.Reload()
+}
+
+func (r *configReloader) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   &PrivateConfigAPI{r},
+		},
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"syscall"
 
@@ -166,6 +167,39 @@ func dialRPC(endpoint string) (*rpc.Client, error) {
 	return rpc.Dial(endpoint)
 }
 
+// globalDataDir returns the data directory without making the node config
+func globalDataDir(ctx *cli.Context) string {
+	if ctx.GlobalIsSet(DataDirFlag.Name) {
+		return ctx.GlobalString(DataDirFlag.Name)
+	}
+	return DefaultDataDir()
+}
+
+// keystoreDir returns the accounts keystore directory, which may be overridden by --keystore
+func keystoreDir(ctx *cli.Context) string {
+	if dir := ctx.GlobalString(utils.KeyStoreDirFlag.Name); dir != "" {
+		return dir
+	}
+	return filepath.Join(globalDataDir(ctx), "keystore")
+}
+
+// dialNode connects to the endpoint given as the first argument, or to the IPC endpoint of the local node.
+func dialNode(ctx *cli.Context) (*rpc.Client, error) {
+	return dialEndpoint(ctx, ctx.Args().First())
+}
+
+// dialEndpoint connects to the endpoint, or to the IPC endpoint of the local node if the endpoint is empty.
+func dialEndpoint(ctx *cli.Context, endpoint string) (*rpc.Client, error) {
+	if endpoint == "" {
+		endpoint = filepath.Join(globalDataDir(ctx), "skyhigh.ipc")
+	}
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to attach to %s: %v", endpoint, err)
+	}
+	return client, nil
+}
+
 // ephemeralConsole starts a new skyhigh node, attaches an ephemeral JavaScript
 // console to it, executes each of the files specified as arguments and tears
 // everything down.
@@ -0,0 +1,272 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/gossip"
+	"github.com/skyhighblockchain/skyhigh/integration"
+)
+
+// reindexCheckpointPeriod is a number of blocks between the reindexing checkpoints
+const reindexCheckpointPeriod = 1000
+
+var (
+	migrateDryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Only report the pending migrations, without applying them",
+	}
+	migrateBackupFlag = cli.BoolFlag{
+		Name:  "backup",
+		Usage: "Copy the tables, which are modified by the pending migrations, before applying them",
+	}
+	migrateRestoreFlag = cli.BoolFlag{
+		Name:  "restore",
+		Usage: "Restore the tables from the backup made before the last migrations",
+	}
+)
+
+var dbCommand = cli.Command{
+	Name:     "db",
+	Usage:    "Low-level database operations",
+	Category: "MISCELLANEOUS COMMANDS",
+
+	Subcommands: []cli.Command{
+		{
+			Name:  "reindex",
+			Usage: "Rebuild indexes from the stored blocks",
+			Subcommands: []cli.Command{
+				{
+					Name:      "txs",
+					Usage:     "Rebuild transactions index",
+					ArgsUsage: "[<blockFrom> <blockTo>]",
+					Action:    utils.MigrateFlags(reindexTxs),
+					Flags: []cli.Flag{
+						DataDirFlag,
+					},
+					Description: `
+    skyhigh db reindex txs
+
+Rebuilds positions of transactions from the stored blocks and events.
+Optional first and second arguments control the first and last block
+to reindex. If no arguments are given, an interrupted reindexing is
+resumed from the last checkpoint. The node has to be stopped.
+`,
+				},
+				{
+					Name:      "logs",
+					Usage:     "Rebuild logs index",
+					ArgsUsage: "[<blockFrom> <blockTo>]",
+					Action:    utils.MigrateFlags(reindexLogs),
+					Flags: []cli.Flag{
+						DataDirFlag,
+					},
+					Description: `
+    skyhigh db reindex logs
+
+Rebuilds the logs index from the stored receipts. Missing receipts are
+restored by re-executing the block, which requires the EVM state of the
+previous block. Optional first and second arguments control the first
+and last block to reindex. If no arguments are given, an interrupted
+reindexing is resumed from the last checkpoint. The node has to be stopped.
+`,
+				},
+			},
+		},
+		{
+			Name:   "migrate",
+			Usage:  "Apply the pending migrations of the database",
+			Action: utils.MigrateFlags(migrateDB),
+			Flags: []cli.Flag{
+				DataDirFlag,
+				migrateDryRunFlag,
+				migrateBackupFlag,
+				migrateRestoreFlag,
+			},
+			Description: `
+    skyhigh db migrate [--dry-run] [--backup]
+    skyhigh db migrate --restore
+
+Applies the migrations, which are pending after an upgrade of the node,
+with progress logging. Otherwise, the migrations are applied on the node
+startup. An interrupted migration is resumed from the last checkpoint.
+With --dry-run, the pending migrations are only reported, along with the
+estimated work. With --backup, the tables modified by the pending
+migrations are copied beforehand, and may be restored with --restore,
+if the node wasn't started after the migration. The node has to be stopped.
+`,
+		},
+		{
+			Name:      "convert",
+			Usage:     "Convert databases into another key-value backend",
+			ArgsUsage: "<backend>",
+			Action:    utils.MigrateFlags(convertDBs),
+			Flags: []cli.Flag{
+				DataDirFlag,
+			},
+			Description: `
+    skyhigh db convert pebble
+
+Converts all the databases into the given backend (leveldb or pebble).
+Each database is copied and verified by comparing the keys count and
+the hash of all the key-value pairs. The original databases are replaced
+only after all the copies are verified. The node has to be stopped.
+Use --db.backend flag or DBs config section to create new databases
+with the same backend.
+`,
+		},
+	},
+}
+
+func blockRangeArgs(ctx *cli.Context) (from, to idx.Block, err error) {
+	if len(ctx.Args()) > 0 {
+		n, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = idx.Block(n)
+	}
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = idx.Block(n)
+	}
+	return from, to, nil
+}
+
+func reindexTxs(ctx *cli.Context) error {
+	return reindex(ctx, "txs", (*gossip.Store).ReindexTxs)
+}
+
+func reindexLogs(ctx *cli.Context) error {
+	return reindex(ctx, "logs", (*gossip.Store).ReindexLogs)
+}
+
+func reindex(ctx *cli.Context, kind string, reindexBlock func(*gossip.Store, idx.Block) error) error {
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := makeDBProducer(ctx, cfg)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	from, to, err := blockRangeArgs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ctx.Args()) == 0 {
+		if checkpoint, ok := gdb.GetReindexCheckpoint(kind); ok {
+			log.Info("Resuming from checkpoint", "block", checkpoint)
+			from = checkpoint
+		}
+	}
+	if genesis := gdb.GetGenesisBlockIndex(); genesis != nil && from < *genesis {
+		from = *genesis
+	}
+	if start := gdb.GetHistoryStart(); from < start {
+		log.Warn("History of the first blocks is pruned", "first", start)
+		from = start
+	}
+	if latest := gdb.GetLatestBlockIndex(); to == 0 || to > latest {
+		to = latest
+	}
+
+	log.Info("Reindexing", "index", kind, "from", from, "to", to)
+	start, reported := time.Now(), time.Now()
+	for n := from; n <= to; n++ {
+		if err := reindexBlock(gdb, n); err != nil {
+			gdb.SetReindexCheckpoint(kind, n)
+			return err
+		}
+		if n%reindexCheckpointPeriod == 0 || n == to {
+			gdb.SetReindexCheckpoint(kind, n+1)
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Reindexing", "index", kind, "last", n, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	gdb.DelReindexCheckpoint(kind)
+	log.Info("Reindexed", "index", kind, "blocks", to+1-from, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+func convertDBs(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("backend argument is required")
+	}
+	cfg := makeAllConfigs(ctx)
+
+	backend := ctx.Args().Get(0)
+	start := time.Now()
+	if err := integration.ConvertDBs(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx), backend); err != nil {
+		return err
+	}
+	log.Info("Converted databases", "backend", backend, "elapsed", common.PrettyDuration(time.Since(start)))
+	if cfg.DBs.Backend != backend {
+		log.Warn("New databases will be created with another backend, set --db.backend flag", "backend", cfg.DBs.Backend)
+	}
+	return nil
+}
+
+func migrateDB(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := makeDBProducer(ctx, cfg)
+	if err := checkStateInitialized(rawProducer); err != nil {
+		return err
+	}
+	gdb := gossip.NewUnmigratedStore(&integration.DummyFlushableProducer{DBProducer: rawProducer}, cfg.SkyhighStore)
+	gdb.SetName("gossip-db")
+	defer gdb.Close()
+
+	if ctx.Bool(migrateRestoreFlag.Name) {
+		if err := gdb.RestoreMigrationBackup(); err != nil {
+			return err
+		}
+		log.Info("Restored the tables from the migration backup")
+		return nil
+	}
+
+	pending, err := gdb.PendingMigrations()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		log.Info("No pending migrations")
+		return nil
+	}
+	for i, m := range pending {
+		estimate := "unknown"
+		if m.Estimate != 0 {
+			estimate = fmt.Sprintf("%d blocks", m.Estimate)
+		}
+		log.Info("Pending migration", "step", fmt.Sprintf("%d/%d", i+1, len(pending)), "name", m.Name,
+			"work", estimate, "interrupted", m.Interrupted, "tables", strings.Join(m.Tables, ","))
+	}
+	if ctx.Bool(migrateDryRunFlag.Name) {
+		return nil
+	}
+
+	start := time.Now()
+	if err := gdb.Migrate(ctx.Bool(migrateBackupFlag.Name)); err != nil {
+		return err
+	}
+	log.Info("Applied migrations", "count", len(pending), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
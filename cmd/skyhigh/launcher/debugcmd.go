@@ -0,0 +1,89 @@
+package launcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	cli "gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/integration"
+)
+
+var (
+	debugCommand = cli.Command{
+		Name:     "debug",
+		Usage:    "A set of commands for consensus debugging",
+		Category: "MISCELLANEOUS COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:      "replay-epoch",
+				Usage:     "Re-run consensus on stored events of an epoch and dump the decisions",
+				ArgsUsage: "<epoch> [<filename>]",
+				Action:    utils.MigrateFlags(replayEpoch),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    skyhigh debug replay-epoch 100 replay.json
+
+Feeds stored events of the epoch into a fresh in-memory consensus engine,
+and dumps re-derived frames, roots and Atropos decisions as JSON
+(to stdout if no filename is specified). Every decision is compared against
+the stored block with the same Atropos, mismatches are reported in "diff" fields.
+Only epochs sealed by a node with the epoch states history are available.`,
+			},
+		},
+	}
+)
+
+func replayEpoch(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	epoch, err := strconv.ParseUint(ctx.Args().First(), 10, 32)
+	if err != nil {
+		return err
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := makeDBProducer(ctx, cfg)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	log.Info("Replaying epoch", "epoch", epoch)
+	res, err := integration.ReplayEpoch(gdb, idx.Epoch(epoch), cfg.AppConfigs())
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	if len(ctx.Args()) > 1 {
+		err = ioutil.WriteFile(ctx.Args().Get(1), out, os.ModePerm)
+	} else {
+		_, err = os.Stdout.Write(append(out, '\n'))
+	}
+	if err != nil {
+		return err
+	}
+
+	if res.Match {
+		log.Info("Replayed epoch matches stored blocks", "epoch", epoch, "events", res.Events, "decisions", len(res.Decisions))
+	} else {
+		log.Warn("Replayed epoch mismatches stored blocks", "epoch", epoch, "events", res.Events, "decisions", len(res.Decisions),
+			"eventDiffs", len(res.EventDiffs), "missingBlocks", len(res.MissingBlocks), "err", res.Error)
+	}
+	return nil
+}
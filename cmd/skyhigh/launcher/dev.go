@@ -0,0 +1,204 @@
+package launcher
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	cli "gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+	"github.com/skyhighblockchain/skyhigh/integration"
+	"github.com/skyhighblockchain/skyhigh/integration/makegenesis"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesisstore"
+	futils "github.com/skyhighblockchain/skyhigh/utils"
+	"github.com/skyhighblockchain/skyhigh/valkeystore"
+)
+
+var (
+	// DevFlag enables development network, where all the validators run in this process
+	DevFlag = cli.BoolFlag{
+		Name:  "dev",
+		Usage: "Runs a single-process development network with in-memory databases, instant blocks and evm_increaseTime/evm_mine RPC methods",
+	}
+	DevValidatorsFlag = cli.IntFlag{
+		Name:  "dev.validators",
+		Usage: "Number of validators of the development network",
+		Value: 3,
+	}
+	DevAccountsFlag = cli.IntFlag{
+		Name:  "dev.accounts",
+		Usage: "Number of pre-funded and unlocked accounts of the development network",
+		Value: 10,
+	}
+	DevBalanceFlag = cli.Uint64Flag{
+		Name:  "dev.balance",
+		Usage: "Balance of every pre-funded account of the development network, in SKH",
+		Value: 1000000,
+	}
+	DevFundFlag = cli.StringFlag{
+		Name:  "dev.fund",
+		Usage: "Comma separated list of additional addresses to pre-fund in the development network",
+	}
+)
+
+const (
+	devValidatorStake   = 5000000
+	devValidatorBalance = 1000000
+)
+
+// devKey derives a deterministic key, so the development accounts are the same across runs
+func devKey(kind string, n int) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("skyhigh dev %s %d", kind, n))))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func devValidatorKey(id idx.ValidatorID) *ecdsa.PrivateKey {
+	return devKey("validator", int(id))
+}
+
+func devAccountKey(n int) *ecdsa.PrivateKey {
+	return devKey("account", n)
+}
+
+func devValidatorPubKey(key *ecdsa.PrivateKey) validatorpk.PubKey {
+	return validatorpk.PubKey{
+		Raw:  crypto.FromECDSAPub(&key.PublicKey),
+		Type: validatorpk.Types.Secp256k1,
+	}
+}
+
+func devValidatorsNum(ctx *cli.Context) int {
+	num := ctx.GlobalInt(DevValidatorsFlag.Name)
+	if num < 1 {
+		log.Crit("Invalid flag", "flag", DevValidatorsFlag.Name, "err", "at least 1 validator is required")
+	}
+	return num
+}
+
+// devEmitterValidators returns the validators, which emit events in this process
+func devEmitterValidators(ctx *cli.Context) []emitter.ValidatorConfig {
+	num := devValidatorsNum(ctx)
+	validators := make([]emitter.ValidatorConfig, num)
+	for i := range validators {
+		id := idx.ValidatorID(i + 1)
+		validators[i] = emitter.ValidatorConfig{
+			ID:     id,
+			PubKey: devValidatorPubKey(devValidatorKey(id)),
+		}
+	}
+	return validators
+}
+
+func parseDevFund(ctx *cli.Context) []common.Address {
+	var addrs []common.Address
+	for _, s := range strings.Split(ctx.GlobalString(DevFundFlag.Name), ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+		if !common.IsHexAddress(s) {
+			log.Crit("Invalid flag", "flag", DevFundFlag.Name, "err", fmt.Sprintf("invalid address %s", s))
+		}
+		addrs = append(addrs, common.HexToAddress(s))
+	}
+	return addrs
+}
+
+// DevRules returns the rules of the development network, where empty blocks aren't skipped to make evm_mine instant.
+func DevRules() skyhigh.Rules {
+	rules := skyhigh.FakeNetRules()
+	rules.Name = "dev"
+	rules.Blocks.MaxEmptyBlockSkipPeriod = 0
+	return rules
+}
+
+func makeDevGenesisStore(ctx *cli.Context) *genesisstore.Store {
+	spec := &makegenesis.Spec{
+		Rules:     DevRules(),
+		Time:      uint64(makegenesis.FakeGenesisTime.Unix()),
+		ExtraData: "dev",
+	}
+	funded := make(map[common.Address]bool)
+	fund := func(addr common.Address, balance *big.Int) {
+		if funded[addr] {
+			return
+		}
+		funded[addr] = true
+		spec.Accounts = append(spec.Accounts, makegenesis.AccountSpec{
+			Address: addr,
+			Balance: balance,
+		})
+	}
+
+	for _, v := range devEmitterValidators(ctx) {
+		addr := crypto.PubkeyToAddress(devValidatorKey(v.ID).PublicKey)
+		spec.Validators = append(spec.Validators, makegenesis.ValidatorSpec{
+			ID:      v.ID,
+			Address: addr,
+			PubKey:  v.PubKey,
+			Stake:   futils.ToSkh(devValidatorStake),
+		})
+		fund(addr, futils.ToSkh(devValidatorBalance))
+	}
+	balance := futils.ToSkh(ctx.GlobalUint64(DevBalanceFlag.Name))
+	for i := 0; i < ctx.GlobalInt(DevAccountsFlag.Name); i++ {
+		fund(crypto.PubkeyToAddress(devAccountKey(i).PublicKey), balance)
+	}
+	for _, addr := range parseDevFund(ctx) {
+		fund(addr, balance)
+	}
+
+	genStore, err := makegenesis.BuildGenesisStore(spec)
+	if err != nil {
+		log.Crit("Failed to build dev genesis", "err", err)
+	}
+	return genStore
+}
+
+// setDevNodeConfig isolates the development node from the network
+func setDevNodeConfig(cfg *node.Config) {
+	cfg.P2P.MaxPeers = 0
+	cfg.P2P.NoDiscovery = true
+	cfg.P2P.DiscoveryV5 = false
+	cfg.P2P.BootstrapNodes = nil
+	cfg.P2P.BootstrapNodesV5 = nil
+	cfg.UseLightweightKDF = true
+}
+
+// makeDevValidatorsKeystore makes an in-memory keystore with unlocked keys of all the dev validators
+func makeDevValidatorsKeystore(validators []emitter.ValidatorConfig) valkeystore.KeystoreI {
+	valKeystore := valkeystore.NewDefaultMemKeystore()
+	for _, v := range validators {
+		err := valKeystore.Add(v.PubKey, crypto.FromECDSA(devValidatorKey(v.ID)), validatorpk.FakePassword)
+		if err != nil {
+			log.Crit("Failed to add dev validator key", "err", err)
+		}
+		err = valKeystore.Unlock(v.PubKey, validatorpk.FakePassword)
+		if err != nil {
+			log.Crit("Failed to unlock dev validator key", "err", err)
+		}
+	}
+	return valKeystore
+}
+
+// unlockDevAccounts imports the pre-funded dev accounts into the node keystore and unlocks them
+func unlockDevAccounts(ctx *cli.Context, stack *node.Node) {
+	for i := 0; i < ctx.GlobalInt(DevAccountsFlag.Name); i++ {
+		key := devAccountKey(i)
+		acc := integration.SetAccountKey(stack.AccountManager(), key, "fakepassword")
+		log.Info("Unlocked dev account", "address", acc.Address.Hex(), "key", hexutil.Encode(crypto.FromECDSA(key)))
+	}
+}
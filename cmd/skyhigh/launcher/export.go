@@ -2,16 +2,18 @@ package launcher
 
 import (
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"io"
+	"io/ioutil"
 	"os"
-	"path"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/skyhighblockchain/push-base/hash"
@@ -20,8 +22,10 @@ import (
 	"github.com/status-im/keycard-go/hexutils"
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/skyhighblockchain/skyhigh/ethapi"
 	"github.com/skyhighblockchain/skyhigh/gossip"
 	"github.com/skyhighblockchain/skyhigh/integration"
+	"github.com/skyhighblockchain/skyhigh/inter/dagexport"
 )
 
 var (
@@ -40,7 +44,7 @@ func exportEvents(ctx *cli.Context) error {
 
 	cfg := makeAllConfigs(ctx)
 
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, cfg)
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -62,33 +66,150 @@ func exportEvents(ctx *cli.Context) error {
 		defer writer.(*gzip.Writer).Close()
 	}
 
-	from := idx.Epoch(1)
+	from, to, err := epochRangeArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Exporting events to file", "file", fn)
+	// Write header and version
+	_, err = writer.Write(append(eventsFileHeader, eventsFileVersion...))
+	if err != nil {
+		return err
+	}
+	err = exportTo(writer, gdb, from, to)
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+
+	return nil
+}
+
+// epochRangeArgs parses optional second and third arguments as the first and last epoch.
+// Last epoch is zero if not specified.
+func epochRangeArgs(ctx *cli.Context) (from, to idx.Epoch, err error) {
+	from = idx.Epoch(1)
 	if len(ctx.Args()) > 1 {
 		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
 		if err != nil {
-			return err
+			return 0, 0, err
 		}
 		from = idx.Epoch(n)
 	}
-	to := idx.Epoch(0)
 	if len(ctx.Args()) > 2 {
 		n, err := strconv.ParseUint(ctx.Args().Get(2), 10, 32)
 		if err != nil {
-			return err
+			return 0, 0, err
 		}
 		to = idx.Epoch(n)
 	}
+	return from, to, nil
+}
 
-	log.Info("Exporting events to file", "file", fn)
-	// Write header and version
-	_, err = writer.Write(append(eventsFileHeader, eventsFileVersion...))
+func exportCheaters(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := makeDBProducer(ctx, cfg)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	from, to, err := epochRangeArgs(ctx)
 	if err != nil {
 		return err
 	}
-	err = exportTo(writer, gdb, from, to)
+	if to == 0 || to > gdb.GetEpoch() {
+		to = gdb.GetEpoch()
+	}
+
+	log.Info("Exporting double-sign evidences to file", "file", fn, "from", from, "to", to)
+	start := time.Now()
+	evidences := make([]map[string]interface{}, 0)
+	for epoch := from; epoch <= to; epoch++ {
+		cheaters, err := ethapi.RPCMarshalDoubleSigns(gdb.FindDoubleSigns(epoch))
+		if err != nil {
+			return err
+		}
+		if len(cheaters) == 0 {
+			continue
+		}
+		evidences = append(evidences, map[string]interface{}{
+			"epoch":    hexutil.Uint64(epoch),
+			"cheaters": cheaters,
+		})
+		log.Info("Found double-signs", "epoch", epoch, "cheaters", len(cheaters))
+	}
+
+	b, err := json.MarshalIndent(evidences, "", "  ")
 	if err != nil {
-		utils.Fatalf("Export error: %v\n", err)
+		return err
+	}
+	if err := ioutil.WriteFile(fn, b, 0644); err != nil {
+		return err
+	}
+	log.Info("Exported double-sign evidences", "epochs", len(evidences), "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
+func exportDag(ctx *cli.Context) error {
+	if len(ctx.Args()) < 2 {
+		utils.Fatalf("This command requires 2 arguments.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawProducer := makeDBProducer(ctx, cfg)
+	gdb, err := makeRawGossipStore(rawProducer, cfg)
+	if err != nil {
+		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
+	}
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	args := make([]uint64, 3)
+	for i := range args {
+		if len(ctx.Args()) <= i+1 {
+			break
+		}
+		args[i], err = strconv.ParseUint(ctx.Args().Get(i+1), 10, 32)
+		if err != nil {
+			return err
+		}
+	}
+	epoch, from, to := idx.Epoch(args[0]), idx.Lamport(args[1]), idx.Lamport(args[2])
+
+	log.Info("Exporting DAG to file", "file", fn, "epoch", epoch, "from", from, "to", to)
+	start := time.Now()
+	graph, err := gdb.GetDagGraph(epoch, from, to, 0)
+	if err != nil {
+		return err
+	}
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	if strings.HasSuffix(fn, ".dot") || strings.HasSuffix(fn, ".gv") {
+		err = dagexport.WriteDOT(fh, graph)
+	} else {
+		err = dagexport.WriteJSON(fh, graph)
+	}
+	if err != nil {
+		return err
 	}
+	log.Info("Exported DAG", "events", len(graph.Nodes), "elapsed", common.PrettyDuration(time.Since(start)))
 
 	return nil
 }
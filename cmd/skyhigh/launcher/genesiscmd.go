@@ -0,0 +1,80 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/integration/makegenesis"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesisstore"
+)
+
+var genesisCommand = cli.Command{
+	Name:     "genesis",
+	Usage:    "Manage genesis files",
+	Category: "MISCELLANEOUS COMMANDS",
+
+	Subcommands: []cli.Command{
+		{
+			Name:      "build",
+			Usage:     "Build a genesis file from a JSON or TOML spec",
+			ArgsUsage: "<spec> <output>",
+			Action:    utils.MigrateFlags(buildGenesis),
+			Description: `
+    skyhigh genesis build spec.toml genesis.g
+
+Builds a genesis file from a declarative spec and prints its hash.
+The spec format is chosen by the file extension (.json or .toml).
+
+The spec contains:
+  - BaseRules: rules to start from (main, test or fake)
+  - Rules: overrides of the base rules, including Name and NetworkID
+  - Time: genesis time in unix seconds
+  - FirstEpoch, ExtraData, DriverOwner (optional)
+  - Accounts: Address, Balance, Code, Nonce and Storage
+  - Validators: ID, Address, PubKey and self-Stake
+  - Delegations: Address, ValidatorID, Stake, and optional lockup of
+    LockedStake for LockupDuration seconds
+
+Run the network with --genesis flag pointing to the built file.
+`,
+		},
+	},
+}
+
+func buildGenesis(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		return errors.New("spec and output arguments are required")
+	}
+
+	spec, err := makegenesis.LoadSpec(ctx.Args().Get(0))
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %v", err)
+	}
+	genStore, err := makegenesis.BuildGenesisStore(spec)
+	if err != nil {
+		return fmt.Errorf("invalid spec: %v", err)
+	}
+	defer genStore.Close()
+
+	file, err := os.OpenFile(ctx.Args().Get(1), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := genesisstore.WriteGenesisStore(file, genStore); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	rules := genStore.GetRules()
+	fmt.Printf("Network name: %s\n", rules.Name)
+	fmt.Printf("Network ID:   %d\n", rules.NetworkID)
+	fmt.Printf("Genesis hash: %s\n", genStore.Hash().String())
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/skyhighblockchain/skyhigh/debug"
 	"github.com/skyhighblockchain/skyhigh/flags"
 	"github.com/skyhighblockchain/skyhigh/gossip"
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
 	"github.com/skyhighblockchain/skyhigh/integration"
 	"github.com/skyhighblockchain/skyhigh/utils/errlock"
 	"github.com/skyhighblockchain/skyhigh/valkeystore"
@@ -59,6 +60,11 @@ func initFlags() {
 	// Flags for testing purpose.
 	testFlags = []cli.Flag{
 		FakeNetFlag,
+		DevFlag,
+		DevValidatorsFlag,
+		DevAccountsFlag,
+		DevBalanceFlag,
+		DevFundFlag,
 	}
 
 	// Flags that configure the node.
@@ -74,6 +80,11 @@ func initFlags() {
 	performanceFlags = []cli.Flag{
 		CacheFlag,
 		utils.SnapshotFlag,
+		AncientEpochsFlag,
+		HistoryBlocksFlag,
+		HistoryEpochsFlag,
+		AddressIndexFlag,
+		DBBackendFlag,
 	}
 	networkingFlags = []cli.Flag{
 		utils.BootnodesFlag,
@@ -146,6 +157,8 @@ func initFlags() {
 		utils.IPCPathFlag,
 		RPCGlobalGasCapFlag,
 		RPCGlobalTxFeeCapFlag,
+		RPCLogsBlockRangeFlag,
+		RPCLogsMaxResultsFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -203,8 +216,18 @@ func init() {
 		importCommand,
 		exportCommand,
 		checkCommand,
+		// See dbcmd.go
+		dbCommand,
+		// See genesiscmd.go
+		genesisCommand,
 		// See snapshot.go
 		snapshotCommand,
+		// See debugcmd.go
+		debugCommand,
+		// See loadgencmd.go
+		loadgenCommand,
+		// See stakecmd.go
+		stakeCommand,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
@@ -274,11 +297,15 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 	if err := os.MkdirAll(chaindataDir, 0700); err != nil {
 		utils.Fatalf("Failed to create chaindata directory: %v", err)
 	}
-	engine, dagIndex, gdb, cdb, genesisStore, blockProc := integration.MakeEngine(integration.DBProducer(chaindataDir, cacheScaler(ctx)), genesis, cfg.AppConfigs())
+	engine, dagIndex, gdb, cdb, genesisStore, blockProc := integration.MakeEngine(integration.DBProducer(chaindataDir, cacheScaler(ctx), cfg.DBs), genesis, cfg.AppConfigs())
 	_ = genesis.Close()
 	metrics.SetDataDir(cfg.Node.DataDir)
 
-	valKeystore := valkeystore.NewDefaultFileKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
+	var valKeystore valkeystore.KeystoreI = valkeystore.NewDefaultFileKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
+	if len(cfg.Skyhigh.DevValidators) != 0 {
+		valKeystore = makeDevValidatorsKeystore(cfg.Skyhigh.DevValidators)
+		unlockDevAccounts(ctx, stack)
+	}
 	valPubkey := cfg.Skyhigh.Emitter.Validator.PubKey
 	if key := getFakeValidatorKey(ctx); key != nil && cfg.Skyhigh.Emitter.Validator.ID != 0 {
 		addFakeValidatorKey(ctx, key, valPubkey, valKeystore)
@@ -292,6 +319,17 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 		if err != nil {
 			utils.Fatalf("Failed to unlock validator key: %v", err)
 		}
+		// unlock the key, which the validator switches to after a key rotation
+		rotation, err := emitter.ReadKeyRotationFile(cfg.Skyhigh.Emitter.KeyRotationFile)
+		if err != nil {
+			utils.Fatalf("Failed to read key rotation file: %v", err)
+		}
+		if rotation != nil && rotation.Next != nil && !valKeystore.Unlocked(*rotation.Next) {
+			err := unlockValidatorKey(ctx, *rotation.Next, valKeystore)
+			if err != nil {
+				utils.Fatalf("Failed to unlock scheduled validator key: %v", err)
+			}
+		}
 	}
 	signer := valkeystore.NewSigner(valKeystore)
 
@@ -306,11 +344,17 @@ func makeNode(ctx *cli.Context, cfg *config, genesis integration.InputGenesis) (
 		utils.Fatalf("Failed to bootstrap the engine: %v", err)
 	}
 
+	reloader := &configReloader{ctx: ctx, running: cfg, svc: svc}
+	stopSIGHUP := reloader.watchSIGHUP()
+
 	stack.RegisterAPIs(svc.APIs())
+	stack.RegisterAPIs(reloader.APIs())
+	stack.RegisterAPIs(validatorAPIs(valKeystore, svc))
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
 	return stack, svc, func() {
+		stopSIGHUP()
 		_ = stack.Close()
 		gdb.Close()
 		_ = cdb.Close()
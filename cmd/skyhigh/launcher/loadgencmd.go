@@ -0,0 +1,207 @@
+package launcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/integration/loadgen"
+	"github.com/skyhighblockchain/skyhigh/integration/makegenesis"
+)
+
+var (
+	loadgenTPSFlag = cli.Float64Flag{
+		Name:  "tps",
+		Usage: "Target rate of the transactions submission",
+		Value: loadgen.DefaultConfig().TPS,
+	}
+	loadgenDurationFlag = cli.DurationFlag{
+		Name:  "duration",
+		Usage: "Duration of the load, 0 to run until interrupted",
+		Value: loadgen.DefaultConfig().Duration,
+	}
+	loadgenMixFlag = cli.StringFlag{
+		Name:  "mix",
+		Usage: "Weights of the transaction kinds: transfer, erc20 (token transfers), storage (contract calls with storage writes)",
+		Value: loadgen.DefaultConfig().Mix.String(),
+	}
+	loadgenFakeKeysFlag = cli.IntFlag{
+		Name:  "fakekeys",
+		Usage: "Send transactions from N fake keys, which are the validators of the fakenet genesis, instead of the keystore",
+	}
+	loadgenFromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Comma separated accounts of the keystore to send transactions from (default = all the accounts)",
+	}
+	loadgenGasPriceFlag = cli.StringFlag{
+		Name:  "gasprice",
+		Usage: "Gas price of the transactions in wei (default = suggested by the node)",
+	}
+	loadgenWaitFlag = cli.DurationFlag{
+		Name:  "wait",
+		Usage: "How long to wait for the confirmations after the load is stopped",
+		Value: loadgen.DefaultConfig().ConfirmationTimeout,
+	}
+)
+
+var loadgenCommand = cli.Command{
+	Action:    utils.MigrateFlags(loadgenCmd),
+	Name:      "loadgen",
+	Usage:     "Generate transactions load for benchmarking a network",
+	ArgsUsage: "[endpoint]",
+	Category:  "MISCELLANEOUS COMMANDS",
+	Flags: []cli.Flag{
+		DataDirFlag,
+		utils.KeyStoreDirFlag,
+		utils.PasswordFileFlag,
+		loadgenTPSFlag,
+		loadgenDurationFlag,
+		loadgenMixFlag,
+		loadgenFakeKeysFlag,
+		loadgenFromFlag,
+		loadgenGasPriceFlag,
+		loadgenWaitFlag,
+	},
+	Description: `
+    skyhigh loadgen --fakekeys 3 --tps 100 --mix transfer=5,erc20=3,storage=2 /path/to/skyhigh.ipc
+
+Submits a mix of transactions at the target rate to the node (IPC of the local node by default),
+and reports the submitted and confirmed TPS, and percentiles of the confirmation latency.
+Only RPC endpoints (IPC, HTTP or WebSocket) are supported, the transactions are submitted with
+eth_sendRawTransaction, so the measured latency includes the RPC overhead.
+The latency is a time from a transaction submission till the block with it is observed.
+Transactions are sent from the fake keys, or from the keystore accounts unlocked with --password.
+For erc20 transactions, every account deploys its own token before the load is started,
+for storage transactions the first account deploys the storage contract.`,
+}
+
+func loadgenCmd(ctx *cli.Context) error {
+	cfg := loadgen.DefaultConfig()
+	cfg.TPS = ctx.Float64(loadgenTPSFlag.Name)
+	cfg.Duration = ctx.Duration(loadgenDurationFlag.Name)
+	cfg.ConfirmationTimeout = ctx.Duration(loadgenWaitFlag.Name)
+	mix, err := loadgen.ParseMix(ctx.String(loadgenMixFlag.Name))
+	if err != nil {
+		return err
+	}
+	cfg.Mix = mix
+	if s := ctx.String(loadgenGasPriceFlag.Name); s != "" {
+		gasPrice, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("invalid gas price %s", s)
+		}
+		cfg.GasPrice = gasPrice
+	}
+
+	keys, err := loadgenKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	client, err := dialNode(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	go func() {
+		select {
+		case <-sigc:
+			log.Info("Got interrupt, stopping the load")
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	r, err := loadgen.New(loadgen.NewRPCBackend(client), keys, cfg).Run(runCtx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Duration:      %v\n", r.Duration.Round(time.Millisecond))
+	fmt.Printf("Submitted:     %d (%.1f TPS)\n", r.Submitted, r.SubmittedTPS)
+	fmt.Printf("Confirmed:     %d (%.1f TPS)\n", r.Confirmed, r.ConfirmedTPS)
+	fmt.Printf("Failed:        %d\n", r.Failed)
+	fmt.Printf("Unconfirmed:   %d\n", r.Submitted-r.Failed-r.Confirmed)
+	fmt.Printf("Latency p50:   %v\n", r.LatencyP50.Round(time.Millisecond))
+	fmt.Printf("Latency p90:   %v\n", r.LatencyP90.Round(time.Millisecond))
+	fmt.Printf("Latency p99:   %v\n", r.LatencyP99.Round(time.Millisecond))
+	fmt.Printf("Latency max:   %v\n", r.LatencyMax.Round(time.Millisecond))
+	return nil
+}
+
+// loadgenKeys returns the fake keys, or decrypts the keys of the keystore accounts
+func loadgenKeys(ctx *cli.Context) ([]*ecdsa.PrivateKey, error) {
+	if n := ctx.Int(loadgenFakeKeysFlag.Name); n > 0 {
+		keys := make([]*ecdsa.PrivateKey, n)
+		for i := range keys {
+			keys[i] = makegenesis.FakeKey(i + 1)
+		}
+		return keys, nil
+	}
+
+	dir := keystoreDir(ctx)
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	accs := ks.Accounts()
+	if from := ctx.String(loadgenFromFlag.Name); from != "" {
+		var filtered []accounts.Account
+		for _, s := range strings.Split(from, ",") {
+			addr := common.HexToAddress(strings.TrimSpace(s))
+			if !ks.HasAddress(addr) {
+				return nil, fmt.Errorf("account %s isn't found in %s", addr.String(), dir)
+			}
+			for _, acc := range accs {
+				if acc.Address == addr {
+					filtered = append(filtered, acc)
+					break
+				}
+			}
+		}
+		accs = filtered
+	}
+	if len(accs) == 0 {
+		return nil, errors.New("no accounts to send transactions from, specify --fakekeys or a keystore")
+	}
+
+	passwords := utils.MakePasswordList(ctx)
+	if len(passwords) == 0 {
+		return nil, errors.New("keystore accounts require --password")
+	}
+	keys := make([]*ecdsa.PrivateKey, 0, len(accs))
+	for i, acc := range accs {
+		// same as for --unlock, the last password is used for the rest of accounts
+		password := passwords[len(passwords)-1]
+		if i < len(passwords) {
+			password = passwords[i]
+		}
+		keyjson, err := ioutil.ReadFile(acc.URL.Path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keyjson, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %v", acc.Address.String(), err)
+		}
+		keys = append(keys, key.PrivateKey)
+	}
+	return keys, nil
+}
@@ -0,0 +1,92 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+)
+
+var (
+	drainFlag = cli.BoolFlag{
+		Name:  "drain",
+		Usage: "Wait until the last emitted event is confirmed",
+	}
+	drainTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Max time to wait for the last emitted event to be confirmed",
+		Value: 5 * time.Minute,
+	}
+	acknowledgeFlag = cli.BoolFlag{
+		Name:  "acknowledge",
+		Usage: "Acknowledge that no other instance of the validator is running",
+	}
+)
+
+func pauseEmitter(ctx *cli.Context) error {
+	client, err := dialNode(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	callCtx := context.Background()
+	drain := ctx.Bool(drainFlag.Name)
+	if drain {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, ctx.Duration(drainTimeoutFlag.Name))
+		defer cancel()
+	}
+	var status emitter.MaintenanceStatus
+	err = client.CallContext(callCtx, &status, "admin_pauseEmitter", drain)
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("emission is paused, but the last emitted event isn't confirmed within %v", ctx.Duration(drainTimeoutFlag.Name))
+	}
+	if err != nil {
+		return err
+	}
+	return printMaintenanceStatus(status)
+}
+
+func resumeEmitter(ctx *cli.Context) error {
+	client, err := dialNode(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var status emitter.MaintenanceStatus
+	err = client.Call(&status, "admin_resumeEmitter", ctx.Bool(acknowledgeFlag.Name))
+	if err != nil {
+		return err
+	}
+	return printMaintenanceStatus(status)
+}
+
+func emitterStatus(ctx *cli.Context) error {
+	client, err := dialNode(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var status emitter.MaintenanceStatus
+	err = client.Call(&status, "admin_emitterStatus")
+	if err != nil {
+		return err
+	}
+	return printMaintenanceStatus(status)
+}
+
+func printMaintenanceStatus(status emitter.MaintenanceStatus) error {
+	out, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,113 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/gossip"
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/sfc"
+	"github.com/skyhighblockchain/skyhigh/valkeystore"
+)
+
+// sfcUpdatePubkeyABI is the SFC method which updates the pubkey of the sender's validator
+const sfcUpdatePubkeyABI = `[{"inputs":[{"internalType":"bytes","name":"pubkey","type":"bytes"}],"name":"updateValidatorPubkey","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// validatorRotate creates a new validator key, schedules the switch of the running node to it,
+// and prints the SFC call which activates the new key.
+// Only the IPC endpoint of the local node is allowed, as the password of the new key is sent to the node.
+func validatorRotate(ctx *cli.Context) error {
+	if ctx.NArg() != 0 {
+		return errors.New("the key rotation is allowed only over the IPC endpoint of the local node, endpoint argument isn't accepted")
+	}
+	cfg := makeAllConfigs(ctx)
+	utils.SetNodeConfig(ctx, &cfg.Node)
+
+	client, err := dialEndpoint(ctx, "")
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	password := getPassPhrase("Your new validator key is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
+	valKeystore := valkeystore.NewDefaultFileRawKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
+	pubkey := newValidatorKey(valKeystore, password)
+	printNewValidatorKey(valKeystore, pubkey)
+
+	var status emitter.KeyRotationStatus
+	err = client.Call(&status, "admin_rotateValidatorKey", pubkey.String(), password)
+	if err != nil {
+		return fmt.Errorf("failed to schedule the key rotation: %v", err)
+	}
+
+	sfcAbi, err := abi.JSON(strings.NewReader(sfcUpdatePubkeyABI))
+	if err != nil {
+		return err
+	}
+	data, err := sfcAbi.Pack("updateValidatorPubkey", pubkey.Bytes())
+	if err != nil {
+		return err
+	}
+	var code hexutil.Bytes
+	err = client.Call(&code, "eth_getCode", sfc.ContractAddress, "latest")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("The node signs events with %s until the new key becomes active.\n", status.PubKey.String())
+	fmt.Printf("To activate the new key, send the following transaction from the validator's address:\n\n")
+	fmt.Printf("To:   %s\n", sfc.ContractAddress.Hex())
+	fmt.Printf("Data: %s\n\n", hexutil.Encode(data))
+	// PUSH4 of the method selector is in the dispatcher of the contract
+	if !bytes.Contains(code, append([]byte{0x63}, sfcAbi.Methods["updateValidatorPubkey"].ID...)) {
+		fmt.Printf("WARNING: the SFC contract doesn't support pubkey updates, the transaction will revert until the SFC is upgraded.\n\n")
+	}
+	fmt.Printf("The node switches to the new key at the first epoch in which the new pubkey is active.\n")
+	fmt.Printf("If the node is restarted before the switch, it unlocks the new key on startup as well.\n")
+	fmt.Printf("After the switch, use --%s=%s when restarting the node.\n", validatorPubkeyFlag.Name, pubkey.String())
+	return nil
+}
+
+// PrivateValidatorAPI provides an API to rotate the key of a running validator.
+// It receives the password of the key, so the admin namespace must not be exposed over HTTP or WebSocket.
+type PrivateValidatorAPI struct {
+	valKeystore valkeystore.KeystoreI
+	svc         *gossip.Service
+}
+
+// RotateValidatorKey unlocks the new key in the validator keystore of the node, and schedules the switch
+// of the signing key at the first epoch in which the new key is the validator's pubkey.
+func (api *PrivateValidatorAPI) RotateValidatorKey(pubkey validatorpk.PubKey, password string) (emitter.KeyRotationStatus, error) {
+	if !api.valKeystore.Unlocked(pubkey) {
+		err := api.valKeystore.Unlock(pubkey, password)
+		if err != nil {
+			return emitter.KeyRotationStatus{}, err
+		}
+	}
+	return api.svc.RotateValidatorKey(pubkey)
+}
+
+// ValidatorKeyRotation returns the signing key of the validator, and the scheduled one.
+func (api *PrivateValidatorAPI) ValidatorKeyRotation() (emitter.KeyRotationStatus, error) {
+	return api.svc.ValidatorKeyRotation()
+}
+
+func validatorAPIs(valKeystore valkeystore.KeystoreI, svc *gossip.Service) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   &PrivateValidatorAPI{valKeystore, svc},
+		},
+	}
+}
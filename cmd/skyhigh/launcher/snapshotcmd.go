@@ -35,7 +35,6 @@ import (
 	cli "gopkg.in/urfave/cli.v1"
 
 	"github.com/skyhighblockchain/skyhigh/gossip/evmstore/evmpruner"
-	"github.com/skyhighblockchain/skyhigh/integration"
 )
 
 var (
@@ -154,7 +153,7 @@ It's also usable without snapshot enabled.
 
 func pruneState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, cfg)
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -197,7 +196,7 @@ func pruneState(ctx *cli.Context) error {
 
 func verifyState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, cfg)
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -240,7 +239,7 @@ func verifyState(ctx *cli.Context) error {
 // contract codes are present.
 func traverseState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, cfg)
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -333,7 +332,7 @@ func traverseState(ctx *cli.Context) error {
 // but it will check each trie node.
 func traverseRawState(ctx *cli.Context) error {
 	cfg := makeAllConfigs(ctx)
-	rawProducer := integration.DBProducer(path.Join(cfg.Node.DataDir, "chaindata"), cacheScaler(ctx))
+	rawProducer := makeDBProducer(ctx, cfg)
 	gdb, err := makeRawGossipStore(rawProducer, cfg)
 	if err != nil {
 		log.Crit("DB opening error", "datadir", cfg.Node.DataDir, "err", err)
@@ -0,0 +1,572 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/sfc100"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/sfc"
+	skyutils "github.com/skyhighblockchain/skyhigh/utils"
+)
+
+// stakeCallGas is a gas limit of the dry run, the actual gas limit is estimated after it
+const stakeCallGas = 10000000
+
+var (
+	stakeFromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Account of the keystore to stake from (default = the first account)",
+	}
+	stakeEndpointFlag = cli.StringFlag{
+		Name:  "endpoint",
+		Usage: "RPC endpoint or IPC path of the node (default = IPC of the local node)",
+	}
+	stakeDryRunFlag = cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Only check the transaction with eth_call, without sending it",
+	}
+	stakeWaitFlag = cli.DurationFlag{
+		Name:  "wait",
+		Usage: "How long to wait for the transaction to be confirmed, 0 to not wait",
+		Value: time.Minute,
+	}
+	stakeWrIDFlag = cli.StringFlag{
+		Name:  "wrid",
+		Usage: "ID of the withdrawal request (default = the first unused ID)",
+	}
+)
+
+func stakeFlags(extra ...cli.Flag) []cli.Flag {
+	return append([]cli.Flag{
+		DataDirFlag,
+		utils.KeyStoreDirFlag,
+		stakeFromFlag,
+		stakeEndpointFlag,
+	}, extra...)
+}
+
+// stakeTxFlags are the flags of the subcommands, which send transactions
+func stakeTxFlags(extra ...cli.Flag) []cli.Flag {
+	return stakeFlags(append([]cli.Flag{
+		utils.PasswordFileFlag,
+		stakeDryRunFlag,
+		stakeWaitFlag,
+	}, extra...)...)
+}
+
+var stakeCommand = cli.Command{
+	Name:     "stake",
+	Usage:    "Manage delegations, rewards and withdrawals",
+	Category: "ACCOUNT COMMANDS",
+	Description: `
+
+Sends transactions to the SFC contract from an account of the keystore, through the node
+(IPC of the local node by default, or --endpoint). Amounts are in SKH, e.g. 1.5.
+
+Every transaction is checked with eth_call before it's sent, and --dry-run stops after the check.
+Transactions are signed locally, the account is unlocked with --password or interactively.`,
+	Subcommands: []cli.Command{
+		{
+			Name:      "delegate",
+			Usage:     "Delegate stake to a validator",
+			Action:    utils.MigrateFlags(stakeDelegate),
+			ArgsUsage: "<validatorID> <amount>",
+			Flags:     stakeTxFlags(),
+		},
+		{
+			Name:      "undelegate",
+			Usage:     "Create a withdrawal request of a delegated stake",
+			Action:    utils.MigrateFlags(stakeUndelegate),
+			ArgsUsage: "<validatorID> <amount>",
+			Flags:     stakeTxFlags(stakeWrIDFlag),
+			Description: `
+    skyhigh stake undelegate 1 100
+
+Creates a withdrawal request of the stake, which may be withdrawn after the withdrawal period.
+Prints the ID of the withdrawal request, which is required by "stake withdraw".`,
+		},
+		{
+			Name:      "withdraw",
+			Usage:     "Withdraw an undelegated stake after the withdrawal period",
+			Action:    utils.MigrateFlags(stakeWithdraw),
+			ArgsUsage: "<validatorID> <wrID>",
+			Flags:     stakeTxFlags(),
+		},
+		{
+			Name:      "claim-rewards",
+			Usage:     "Claim the pending rewards of a delegation",
+			Action:    utils.MigrateFlags(stakeClaimRewards),
+			ArgsUsage: "<validatorID>",
+			Flags:     stakeTxFlags(),
+		},
+		{
+			Name:      "restake",
+			Usage:     "Delegate the pending rewards of a delegation to the same validator",
+			Action:    utils.MigrateFlags(stakeRestake),
+			ArgsUsage: "<validatorID>",
+			Flags:     stakeTxFlags(),
+		},
+		{
+			Name:      "lock",
+			Usage:     "Lock up a delegated stake for extra rewards",
+			Action:    utils.MigrateFlags(stakeLock),
+			ArgsUsage: "<validatorID> <duration> <amount>",
+			Flags:     stakeTxFlags(),
+			Description: `
+    skyhigh stake lock 1 336h 100
+
+Locks up the stake for the duration, e.g. 336h for 14 days.`,
+		},
+		{
+			Name:      "unlock",
+			Usage:     "Unlock a locked up stake, before the lockup end it's penalized",
+			Action:    utils.MigrateFlags(stakeUnlock),
+			ArgsUsage: "<validatorID> <amount>",
+			Flags:     stakeTxFlags(),
+		},
+		{
+			Name:      "rewards",
+			Usage:     "Show the delegations and pending rewards",
+			Action:    utils.MigrateFlags(stakeRewards),
+			ArgsUsage: "[validatorID...]",
+			Flags:     stakeFlags(),
+			Description: `
+    skyhigh stake rewards --from 0x...
+
+Shows the stake, the locked up stake and the pending rewards of the delegations to the validators,
+or of all the non-empty delegations if validators aren't specified. The account doesn't need to be in the keystore.`,
+		},
+	},
+}
+
+// stakeSession is a connection to the SFC on behalf of a delegator
+type stakeSession struct {
+	client *ethclient.Client
+	sfc    *sfc100.Contract
+	ks     *keystore.KeyStore
+	from   common.Address
+}
+
+func openStakeSession(ctx *cli.Context) (*stakeSession, error) {
+	ks := keystore.NewKeyStore(keystoreDir(ctx), keystore.StandardScryptN, keystore.StandardScryptP)
+	var from common.Address
+	if s := ctx.String(stakeFromFlag.Name); s != "" {
+		if !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address %s", s)
+		}
+		from = common.HexToAddress(s)
+	} else if accs := ks.Accounts(); len(accs) != 0 {
+		from = accs[0].Address
+	} else {
+		return nil, errors.New("no accounts in the keystore, specify --" + stakeFromFlag.Name)
+	}
+
+	rpcClient, err := dialEndpoint(ctx, ctx.String(stakeEndpointFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	client := ethclient.NewClient(rpcClient)
+	contract, err := sfc100.NewContract(sfc.ContractAddress, client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &stakeSession{
+		client: client,
+		sfc:    contract,
+		ks:     ks,
+		from:   from,
+	}, nil
+}
+
+func (s *stakeSession) Close() {
+	s.client.Close()
+}
+
+// transactOpts unlocks the account, or returns a non-signing transactor for a dry run
+func (s *stakeSession) transactOpts(ctx *cli.Context) (*bind.TransactOpts, error) {
+	if ctx.Bool(stakeDryRunFlag.Name) {
+		return &bind.TransactOpts{
+			From: s.from,
+			Signer: func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+				return tx, nil
+			},
+		}, nil
+	}
+	acc := accounts.Account{Address: s.from}
+	if !s.ks.HasAddress(s.from) {
+		return nil, fmt.Errorf("account %s isn't found in the keystore", s.from.String())
+	}
+	prompt := fmt.Sprintf("Unlocking account %s", s.from.String())
+	password := getPassPhrase(prompt, false, 0, utils.MakePasswordList(ctx))
+	if err := s.ks.Unlock(acc, password); err != nil {
+		return nil, err
+	}
+	chainID, err := s.client.ChainID(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewKeyStoreTransactorWithChainID(s.ks, acc, chainID)
+}
+
+// sendTx checks the transaction with eth_call, and sends it unless it's a dry run
+func (s *stakeSession) sendTx(ctx *cli.Context, name string, value *big.Int, build func(opts *bind.TransactOpts) (*types.Transaction, error)) error {
+	opts, err := s.transactOpts(ctx)
+	if err != nil {
+		return err
+	}
+	opts.Value = value
+	opts.NoSend = true
+	opts.GasLimit = stakeCallGas
+	tx, err := build(opts)
+	if err != nil {
+		return err
+	}
+	// the gas price is omitted, so only the value is checked against the balance
+	msg := ethereum.CallMsg{
+		From:  s.from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if _, err := s.client.CallContract(context.Background(), msg, nil); err != nil {
+		return fmt.Errorf("%s would fail: %v", name, err)
+	}
+	msg.Gas = 0
+	gas, err := s.client.EstimateGas(context.Background(), msg)
+	if err != nil {
+		return fmt.Errorf("%s would fail: %v", name, err)
+	}
+	if ctx.Bool(stakeDryRunFlag.Name) {
+		fmt.Printf("Dry run of %s from %s succeeded, gas: %d\n", name, s.from.String(), gas)
+		return nil
+	}
+
+	opts.NoSend = false
+	// the SFC state may change until the transaction is included, e.g. the rewards of a new block
+	opts.GasLimit = gas + gas/5
+	tx, err = build(opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Sent %s from %s, transaction: %s\n", name, s.from.String(), tx.Hash().String())
+	wait := ctx.Duration(stakeWaitFlag.Name)
+	if wait == 0 {
+		return nil
+	}
+	waitCtx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	receipt, err := bind.WaitMined(waitCtx, s.client, tx)
+	if err != nil {
+		return fmt.Errorf("transaction isn't confirmed: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction is reverted in block %d", receipt.BlockNumber.Uint64())
+	}
+	fmt.Printf("Confirmed in block %d, gas used: %d\n", receipt.BlockNumber.Uint64(), receipt.GasUsed)
+	return nil
+}
+
+func parseStakeArgs(ctx *cli.Context, names ...string) error {
+	if len(ctx.Args()) != len(names) {
+		return fmt.Errorf("expected %d arguments: %v", len(names), names)
+	}
+	return nil
+}
+
+func parseValidatorID(s string) (*big.Int, error) {
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || id == 0 {
+		return nil, fmt.Errorf("invalid validator ID %s", s)
+	}
+	return new(big.Int).SetUint64(id), nil
+}
+
+func parseStakeAmount(s string) (*big.Int, error) {
+	amount, err := skyutils.ParseSkh(s)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() == 0 {
+		return nil, errors.New("zero amount")
+	}
+	return amount, nil
+}
+
+func stakeDelegate(ctx *cli.Context) error {
+	if err := parseStakeArgs(ctx, "validatorID", "amount"); err != nil {
+		return err
+	}
+	validatorID, err := parseValidatorID(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	amount, err := parseStakeAmount(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	name := fmt.Sprintf("delegation of %s SKH to validator %s", skyutils.FormatSkh(amount), validatorID)
+	return s.sendTx(ctx, name, amount, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.sfc.Delegate(opts, validatorID)
+	})
+}
+
+func stakeUndelegate(ctx *cli.Context) error {
+	if err := parseStakeArgs(ctx, "validatorID", "amount"); err != nil {
+		return err
+	}
+	validatorID, err := parseValidatorID(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	amount, err := parseStakeAmount(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	var wrID *big.Int
+	if str := ctx.String(stakeWrIDFlag.Name); str != "" {
+		id, ok := new(big.Int).SetString(str, 10)
+		if !ok {
+			return fmt.Errorf("invalid withdrawal request ID %s", str)
+		}
+		wrID = id
+	} else if wrID, err = s.unusedWrID(validatorID); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("undelegation of %s SKH from validator %s, withdrawal request %s", skyutils.FormatSkh(amount), validatorID, wrID)
+	err = s.sendTx(ctx, name, nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.sfc.Undelegate(opts, validatorID, wrID, amount)
+	})
+	if err != nil || ctx.Bool(stakeDryRunFlag.Name) {
+		return err
+	}
+	periodEpochs, err := s.sfc.WithdrawalPeriodEpochs(nil)
+	if err != nil {
+		return err
+	}
+	periodTime, err := s.sfc.WithdrawalPeriodTime(nil)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Withdraw it with \"stake withdraw %s %s\" after %s epochs and %v\n",
+		validatorID, wrID, periodEpochs, time.Duration(periodTime.Uint64())*time.Second)
+	return nil
+}
+
+// unusedWrID returns the first ID which isn't used by pending withdrawal requests of the delegation
+func (s *stakeSession) unusedWrID(validatorID *big.Int) (*big.Int, error) {
+	for id := int64(0); ; id++ {
+		wr, err := s.sfc.GetWithdrawalRequest(nil, s.from, validatorID, big.NewInt(id))
+		if err != nil {
+			return nil, err
+		}
+		if wr.Amount.Sign() == 0 {
+			return big.NewInt(id), nil
+		}
+	}
+}
+
+func stakeWithdraw(ctx *cli.Context) error {
+	if err := parseStakeArgs(ctx, "validatorID", "wrID"); err != nil {
+		return err
+	}
+	validatorID, err := parseValidatorID(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	wrID, ok := new(big.Int).SetString(ctx.Args().Get(1), 10)
+	if !ok {
+		return fmt.Errorf("invalid withdrawal request ID %s", ctx.Args().Get(1))
+	}
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	wr, err := s.sfc.GetWithdrawalRequest(nil, s.from, validatorID, wrID)
+	if err != nil {
+		return err
+	}
+	if wr.Amount.Sign() == 0 {
+		return fmt.Errorf("withdrawal request %s to validator %s doesn't exist", wrID, validatorID)
+	}
+	name := fmt.Sprintf("withdrawal of %s SKH from validator %s", skyutils.FormatSkh(wr.Amount), validatorID)
+	return s.sendTx(ctx, name, nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.sfc.Withdraw(opts, validatorID, wrID)
+	})
+}
+
+func stakeClaimRewards(ctx *cli.Context) error {
+	return stakeRewardsTx(ctx, "claiming", func(s *stakeSession, opts *bind.TransactOpts, validatorID *big.Int) (*types.Transaction, error) {
+		return s.sfc.ClaimRewards(opts, validatorID)
+	})
+}
+
+func stakeRestake(ctx *cli.Context) error {
+	return stakeRewardsTx(ctx, "restaking", func(s *stakeSession, opts *bind.TransactOpts, validatorID *big.Int) (*types.Transaction, error) {
+		return s.sfc.RestakeRewards(opts, validatorID)
+	})
+}
+
+func stakeRewardsTx(ctx *cli.Context, action string, build func(s *stakeSession, opts *bind.TransactOpts, validatorID *big.Int) (*types.Transaction, error)) error {
+	if err := parseStakeArgs(ctx, "validatorID"); err != nil {
+		return err
+	}
+	validatorID, err := parseValidatorID(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	rewards, err := s.sfc.PendingRewards(nil, s.from, validatorID)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s of %s SKH rewards from validator %s", action, skyutils.FormatSkh(rewards), validatorID)
+	return s.sendTx(ctx, name, nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return build(s, opts, validatorID)
+	})
+}
+
+func stakeLock(ctx *cli.Context) error {
+	if err := parseStakeArgs(ctx, "validatorID", "duration", "amount"); err != nil {
+		return err
+	}
+	validatorID, err := parseValidatorID(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	amount, err := parseStakeAmount(ctx.Args().Get(2))
+	if err != nil {
+		return err
+	}
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	seconds := big.NewInt(int64(duration / time.Second))
+	name := fmt.Sprintf("lockup of %s SKH delegated to validator %s for %v", skyutils.FormatSkh(amount), validatorID, duration)
+	return s.sendTx(ctx, name, nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.sfc.LockStake(opts, validatorID, seconds, amount)
+	})
+}
+
+func stakeUnlock(ctx *cli.Context) error {
+	if err := parseStakeArgs(ctx, "validatorID", "amount"); err != nil {
+		return err
+	}
+	validatorID, err := parseValidatorID(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	amount, err := parseStakeAmount(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	name := fmt.Sprintf("unlock of %s SKH delegated to validator %s", skyutils.FormatSkh(amount), validatorID)
+	return s.sendTx(ctx, name, nil, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return s.sfc.UnlockStake(opts, validatorID, amount)
+	})
+}
+
+func stakeRewards(ctx *cli.Context) error {
+	s, err := openStakeSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	var validatorIDs []*big.Int
+	for _, arg := range ctx.Args() {
+		validatorID, err := parseValidatorID(arg)
+		if err != nil {
+			return err
+		}
+		validatorIDs = append(validatorIDs, validatorID)
+	}
+	all := len(validatorIDs) == 0
+	if all {
+		last, err := s.sfc.LastValidatorID(nil)
+		if err != nil {
+			return err
+		}
+		for id := uint64(1); id <= last.Uint64(); id++ {
+			validatorIDs = append(validatorIDs, new(big.Int).SetUint64(id))
+		}
+	}
+
+	fmt.Printf("Delegator: %s\n\n", s.from.String())
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VALIDATOR\tSTAKE\tLOCKED\tLOCKUP END\tPENDING REWARDS")
+	for _, validatorID := range validatorIDs {
+		stake, err := s.sfc.GetStake(nil, s.from, validatorID)
+		if err != nil {
+			return err
+		}
+		rewards, err := s.sfc.PendingRewards(nil, s.from, validatorID)
+		if err != nil {
+			return err
+		}
+		if all && stake.Sign() == 0 && rewards.Sign() == 0 {
+			continue
+		}
+		lockup, err := s.sfc.GetLockupInfo(nil, s.from, validatorID)
+		if err != nil {
+			return err
+		}
+		lockupEnd := "-"
+		if lockup.LockedStake.Sign() != 0 {
+			lockupEnd = time.Unix(lockup.EndTime.Int64(), 0).UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", validatorID, skyutils.FormatSkh(stake),
+			skyutils.FormatSkh(lockup.LockedStake), lockupEnd, skyutils.FormatSkh(rewards))
+	}
+	return w.Flush()
+}
@@ -24,7 +24,7 @@ var (
 		Category: "VALIDATOR COMMANDS",
 		Description: `
 
-Create a new validator private key.
+Create a new validator private key, rotate the key of a running validator, or pause and resume the events emission of a running validator.
 
 It supports interactive mode, when you are prompted for password as well as
 non-interactive mode where passwords are supplied via a given password file.
@@ -81,6 +81,73 @@ password to file or expose in any other way.
 Converts an account private key to a validator private key and saves in the validator keystore.
 `,
 			},
+			{
+				Name:   "rotate",
+				Usage:  "Rotate the key of a running validator",
+				Action: utils.MigrateFlags(validatorRotate),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.KeyStoreDirFlag,
+					utils.PasswordFileFlag,
+				},
+				Description: `
+    skyhigh validator rotate
+
+Creates a new validator key in the keystore of the node, and schedules the switch of the running validator
+to the new key. Prints the SFC call which updates the validator pubkey.
+
+Events are signed with the current key until the epoch in which the new pubkey becomes active,
+starting from this epoch they are signed with the new key, without a restart.
+The command must be run on the host of the validator, with the same datadir and keystore.
+It connects only to the IPC endpoint of the local node, as the password of the new key is sent to the node.
+The admin namespace must not be exposed over HTTP or WebSocket.
+`,
+			},
+			{
+				Name:      "pause",
+				Usage:     "Pause the events emission of a running validator for a maintenance",
+				Action:    utils.MigrateFlags(pauseEmitter),
+				ArgsUsage: "[endpoint]",
+				Flags: []cli.Flag{
+					DataDirFlag,
+					drainFlag,
+					drainTimeoutFlag,
+				},
+				Description: `
+    skyhigh validator pause --drain
+
+Pauses the events emission of the validator (IPC of the local node by default), e.g. before an upgrade
+or a migration to another host. The paused state survives restarts until the emission is resumed.
+With --drain, it waits until the last emitted event is confirmed.
+`,
+			},
+			{
+				Name:      "resume",
+				Usage:     "Resume the events emission of a paused validator",
+				Action:    utils.MigrateFlags(resumeEmitter),
+				ArgsUsage: "[endpoint]",
+				Flags: []cli.Flag{
+					DataDirFlag,
+					acknowledgeFlag,
+				},
+				Description: `
+    skyhigh validator resume
+
+Resumes the events emission of the paused validator.
+If the emission was paused on another host, or events of the validator were emitted by another instance,
+then resuming requires --acknowledge, which confirms that the other instance is stopped.
+After the acknowledgement, the emission starts only after the doublesign protection interval.
+`,
+			},
+			{
+				Name:      "status",
+				Usage:     "Print the maintenance state of a running validator",
+				Action:    utils.MigrateFlags(emitterStatus),
+				ArgsUsage: "[endpoint]",
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+			},
 		},
 	}
 )
@@ -92,6 +159,14 @@ func validatorKeyCreate(ctx *cli.Context) error {
 
 	password := getPassPhrase("Your new validator key is locked with a password. Please give a password. Do not forget this password.", true, 0, utils.MakePasswordList(ctx))
 
+	valKeystore := valkeystore.NewDefaultFileRawKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
+	publicKey := newValidatorKey(valKeystore, password)
+	printNewValidatorKey(valKeystore, publicKey)
+	return nil
+}
+
+// newValidatorKey generates a new validator key and saves it into the keystore.
+func newValidatorKey(valKeystore *valkeystore.FileKeystore, password string) validatorpk.PubKey {
 	privateKeyECDSA, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
 	if err != nil {
 		utils.Fatalf("Failed to create account: %v", err)
@@ -102,7 +177,6 @@ func validatorKeyCreate(ctx *cli.Context) error {
 		Type: validatorpk.Types.Secp256k1,
 	}
 
-	valKeystore := valkeystore.NewDefaultFileRawKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
 	err = valKeystore.Add(publicKey, privateKey, password)
 	if err != nil {
 		utils.Fatalf("Failed to create account: %v", err)
@@ -113,7 +187,10 @@ func validatorKeyCreate(ctx *cli.Context) error {
 	if err != nil {
 		utils.Fatalf("Failed to decrypt the account: %v", err)
 	}
+	return publicKey
+}
 
+func printNewValidatorKey(valKeystore *valkeystore.FileKeystore, publicKey validatorpk.PubKey) {
 	fmt.Printf("\nYour new key was generated\n\n")
 	fmt.Printf("Public key:                  %s\n", publicKey.String())
 	fmt.Printf("Path of the secret key file: %s\n\n", valKeystore.PathOf(publicKey))
@@ -121,7 +198,6 @@ func validatorKeyCreate(ctx *cli.Context) error {
 	fmt.Printf("- You must NEVER share the secret key with anyone! The key controls access to your validator!\n")
 	fmt.Printf("- You must BACKUP your key file! Without the key, it's impossible to operate the validator!\n")
 	fmt.Printf("- You must REMEMBER your password! Without the password, it's impossible to decrypt the key!\n\n")
-	return nil
 }
 
 // validatorKeyConvert converts account key to validator key.
@@ -2,9 +2,13 @@ package ethapi
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
 )
 
 // PublicAbftAPI provides an API to access consensus related information.
@@ -50,3 +54,67 @@ func (s *PublicAbftAPI) GetOriginatedEpochFee(ctx context.Context, validatorID h
 	}
 	return (*hexutil.Big)(v), nil
 }
+
+// GetValidatorsHealth returns liveness summary of every validator in the current epoch:
+// last seen event, time since the last event, median time lag behind the last block,
+// blocks missed and whether validator's fork is observed.
+func (s *PublicAbftAPI) GetValidatorsHealth(ctx context.Context) (map[string]interface{}, error) {
+	health, err := s.b.GetValidatorsHealth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := inter.Timestamp(time.Now().UnixNano())
+
+	validators := make([]map[string]interface{}, len(health.Validators))
+	for i, v := range health.Validators {
+		fields := map[string]interface{}{
+			"id":            hexutil.Uint64(v.ID),
+			"weight":        hexutil.Uint64(v.Weight),
+			"blocksMissed":  hexutil.Uint64(v.BlocksMissed),
+			"missedTime":    hexutil.Uint64(v.MissedTime),
+			"cheater":       v.Cheater,
+			"lastEvent":     nil,
+			"lastSeen":      nil,
+			"medianTimeLag": nil,
+		}
+		if e := v.LastEvent; e != nil {
+			fields["lastEvent"] = map[string]interface{}{
+				"id":           hexutil.Bytes(e.ID().Bytes()),
+				"lamport":      hexutil.Uint64(e.Lamport()),
+				"seq":          hexutil.Uint64(e.Seq()),
+				"frame":        hexutil.Uint64(e.Frame()),
+				"creationTime": hexutil.Uint64(e.CreationTime()),
+				"medianTime":   hexutil.Uint64(e.MedianTime()),
+			}
+			fields["lastSeen"] = hexutil.Uint64(timeSince(now, e.CreationTime()))
+			fields["medianTimeLag"] = hexutil.Uint64(timeSince(health.LastBlockTime, e.MedianTime()))
+		}
+		validators[i] = fields
+	}
+
+	return map[string]interface{}{
+		"epoch":         hexutil.Uint64(health.Epoch),
+		"lastBlock":     hexutil.Uint64(health.LastBlock),
+		"lastBlockTime": hexutil.Uint64(health.LastBlockTime),
+		"validators":    validators,
+	}, nil
+}
+
+// GetCheaters returns validators which double-signed in the epoch, with pairs of conflicting events as a proof.
+// * When epoch is -2 the cheaters for latest epoch are returned.
+// * When epoch is -1 the cheaters for latest sealed epoch are returned.
+func (s *PublicAbftAPI) GetCheaters(ctx context.Context, epoch rpc.BlockNumber) ([]map[string]interface{}, error) {
+	doubleSigns, err := s.b.GetDoubleSigns(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return RPCMarshalDoubleSigns(doubleSigns)
+}
+
+// timeSince returns a non-negative duration between two timestamps.
+func timeSince(now, t inter.Timestamp) inter.Timestamp {
+	if now > t {
+		return now - t
+	}
+	return 0
+}
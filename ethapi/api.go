@@ -1166,6 +1166,50 @@ func RPCMarshalEventHeader(header inter.EventI) map[string]interface{} {
 	}
 }
 
+// RPCMarshalSignedEvent converts the given event header to the RPC output, which is sufficient to verify event's signature.
+// Header is the event serialization, which is hashed (SHA-256) to get the signed hash.
+func RPCMarshalSignedEvent(event *inter.EventPayload) (map[string]interface{}, error) {
+	fields := RPCMarshalEventHeader(event)
+	header, err := event.Event.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	fields["header"] = hexutil.Bytes(header)
+	fields["hashToSign"] = hexutil.Bytes(event.HashToSign().Bytes())
+	fields["signature"] = hexutil.Bytes(event.Sig().Bytes())
+	return fields, nil
+}
+
+// RPCMarshalDoubleSigns groups the given double signs by creator and converts them to the RPC output.
+// Double signs are expected to be sorted by creator.
+func RPCMarshalDoubleSigns(doubleSigns []inter.DoubleSign) ([]map[string]interface{}, error) {
+	res := make([]map[string]interface{}, 0)
+	var forks []map[string]interface{}
+	for i, ds := range doubleSigns {
+		if i == 0 || doubleSigns[i-1].Creator() != ds.Creator() {
+			forks = make([]map[string]interface{}, 0, 1)
+			res = append(res, map[string]interface{}{
+				"validator": hexutil.Uint64(ds.Creator()),
+				"forks":     forks,
+			})
+		}
+		first, err := RPCMarshalSignedEvent(ds.First)
+		if err != nil {
+			return nil, err
+		}
+		second, err := RPCMarshalSignedEvent(ds.Second)
+		if err != nil {
+			return nil, err
+		}
+		forks = append(forks, map[string]interface{}{
+			"seq":    hexutil.Uint64(ds.Seq()),
+			"events": []map[string]interface{}{first, second},
+		})
+		res[len(res)-1]["forks"] = forks
+	}
+	return res, nil
+}
+
 // RPCMarshalEvent converts the given event to the RPC output which depends on fullTx. If inclTx is true transactions are
 // returned. When fullTx is true the returned block contains full transaction details, otherwise it will only contain
 // transaction hashes.
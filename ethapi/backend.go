@@ -37,6 +37,7 @@ import (
 	"github.com/skyhighblockchain/skyhigh/evmcore"
 	"github.com/skyhighblockchain/skyhigh/gossip/sfcapi"
 	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/dagexport"
 )
 
 // PeerProgress is synchronization status of a peer
@@ -49,6 +50,27 @@ type PeerProgress struct {
 	HighestEpoch     idx.Epoch
 }
 
+// ValidatorHealth is a consensus liveness summary of an epoch validator
+type ValidatorHealth struct {
+	ID        idx.ValidatorID
+	Weight    pos.Weight
+	LastEvent *inter.Event // nil if validator hasn't emitted events in the epoch yet
+	// BlocksMissed is a number of blocks since the last block confirmed validator's event
+	BlocksMissed idx.Block
+	// MissedTime is a time since the last block confirmed validator's event
+	MissedTime inter.Timestamp
+	// Cheater is true if validator's fork is observed in the epoch
+	Cheater bool
+}
+
+// ValidatorsHealth is a consensus liveness summary of all the epoch validators
+type ValidatorsHealth struct {
+	Epoch         idx.Epoch
+	LastBlock     idx.Block
+	LastBlockTime inter.Timestamp
+	Validators    []ValidatorHealth
+}
+
 // Backend interface provides the common API services (that are provided by
 // both full and light clients) with access to necessary functions.
 type Backend interface {
@@ -95,6 +117,9 @@ type Backend interface {
 	GetHeads(ctx context.Context, epoch rpc.BlockNumber) (hash.Events, error)
 	CurrentEpoch(ctx context.Context) idx.Epoch
 	SealedEpochTiming(ctx context.Context) (start inter.Timestamp, end inter.Timestamp)
+	GetValidatorsHealth(ctx context.Context) (*ValidatorsHealth, error)
+	GetDoubleSigns(ctx context.Context, epoch rpc.BlockNumber) ([]inter.DoubleSign, error)
+	GetDagGraph(ctx context.Context, epoch rpc.BlockNumber, from, to idx.Lamport, limit int) (*dagexport.Graph, error)
 
 	// Push SFC API
 	GetValidators(ctx context.Context) *pos.Validators
@@ -1,6 +1,7 @@
 package ethapi
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -9,6 +10,9 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter/dagexport"
 )
 
 // PublicDAGChainAPI provides an API to access the directed acyclic graph chain.
@@ -59,6 +63,33 @@ func (s *PublicDAGChainAPI) GetHeads(ctx context.Context, epoch rpc.BlockNumber)
 	return eventIDsToHex(res), nil
 }
 
+// maxSubgraphEvents is a limit of events in dag_exportSubgraph output.
+const maxSubgraphEvents = 20000
+
+// ExportSubgraph returns epoch events within [fromLamport, toLamport] range with parents,
+// root/Atropos flags and confirmation blocks, in "json" (JSON Graph Format) or "dot" (Graphviz) format.
+// * When epoch is -2 the events of latest epoch are returned.
+// * When epoch is -1 the events of latest sealed epoch are returned.
+// * When toLamport is 0 the range isn't bounded.
+func (s *PublicDAGChainAPI) ExportSubgraph(ctx context.Context, epoch rpc.BlockNumber, fromLamport, toLamport hexutil.Uint64, format *string) (interface{}, error) {
+	graph, err := s.b.GetDagGraph(ctx, epoch, idx.Lamport(fromLamport), idx.Lamport(toLamport), maxSubgraphEvents)
+	if err != nil {
+		return nil, err
+	}
+	if format == nil || *format == "json" {
+		return dagexport.ToJSON(graph), nil
+	}
+	if *format == "dot" {
+		buf := new(bytes.Buffer)
+		err = dagexport.WriteDOT(buf, graph)
+		if err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	}
+	return nil, fmt.Errorf("unknown format %s", *format)
+}
+
 // GetEpochStats returns epoch statistics.
 // * When epoch is -2 the statistics for latest epoch is returned.
 // * When epoch is -1 the statistics for latest sealed epoch is returned.
@@ -0,0 +1,157 @@
+package evmcore
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var _ vm.StateDB = (*txState)(nil)
+
+// ParallelStateProcessor is a Processor, which executes transactions of a block speculatively in parallel.
+// Each transaction is executed on top of the state before the block, recording its read and write sets.
+// Transactions are applied in the block order, and a transaction which has read anything written by the previous
+// transactions is re-executed on top of the actual state. Hence, the result is identical to StateProcessor.
+//
+// ParallelStateProcessor implements Processor.
+type ParallelStateProcessor struct {
+	*StateProcessor
+	workers int
+}
+
+// NewParallelStateProcessor initialises a new ParallelStateProcessor.
+func NewParallelStateProcessor(config *params.ChainConfig, bc DummyChain, workers int) *ParallelStateProcessor {
+	return &ParallelStateProcessor{
+		StateProcessor: NewStateProcessor(config, bc),
+		workers:        workers,
+	}
+}
+
+// speculativeResult is a result of a transaction execution on top of a txState
+type speculativeResult struct {
+	state  *txState
+	result *ExecutionResult
+	err    error
+	// invalid is true if execution has panicked, e.g. because of an inconsistent state observed by the transaction
+	invalid bool
+}
+
+// Process processes the state changes the same way as StateProcessor.Process does, but executes transactions in parallel.
+// Blocks, which cannot be executed speculatively, are processed sequentially.
+func (p *ParallelStateProcessor) Process(
+	block *EvmBlock, statedb *state.StateDB, cfg vm.Config, usedGas *uint64, internal bool, onNewLog func(*types.Log, *state.StateDB),
+) (
+	receipts types.Receipts, allLogs []*types.Log, skipped []uint32, err error,
+) {
+	if p.workers <= 1 || len(block.Transactions) < 2 || internal || cfg.Debug {
+		return p.StateProcessor.Process(block, statedb, cfg, usedGas, internal, onNewLog)
+	}
+	header := block.Header()
+	signer := types.MakeSigner(p.config, header.Number)
+	msgs := make([]types.Message, len(block.Transactions))
+	var totalGas uint64
+	for i, tx := range block.Transactions {
+		msgs[i], err = tx.AsMessage(signer)
+		if err != nil {
+			return p.StateProcessor.Process(block, statedb, cfg, usedGas, internal, onNewLog)
+		}
+		if totalGas > math.MaxUint64-msgs[i].Gas() {
+			return p.StateProcessor.Process(block, statedb, cfg, usedGas, internal, onNewLog)
+		}
+		totalGas += msgs[i].Gas()
+	}
+	// Gas pool cannot be exhausted, so every transaction may use its own gas pool
+	if totalGas > block.GasLimit {
+		return p.StateProcessor.Process(block, statedb, cfg, usedGas, internal, onNewLog)
+	}
+
+	results := p.executeSpeculatively(block, msgs, statedb, cfg)
+
+	skipped = make([]uint32, 0, len(block.Transactions))
+	written := make(map[stateKey]struct{})
+	for i, tx := range block.Transactions {
+		res := results[i]
+		if res.invalid || res.state.conflicts(written) {
+			// re-execute on top of the actual state, a panic isn't recovered the same way as in StateProcessor
+			res = p.execute(block, msgs[i], statedb, cfg, false)
+		}
+
+		statedb.Prepare(tx.Hash(), block.Hash, i)
+		res.state.applyTo(statedb)
+		for key := range res.state.writes() {
+			written[key] = struct{}{}
+		}
+		if res.err != nil {
+			if res.result == nil {
+				skipped = append(skipped, uint32(i))
+				continue
+			}
+			return nil, nil, nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), res.err)
+		}
+
+		receipt := finaliseTransaction(msgs[i], p.config, statedb, header, tx, res.result, usedGas, onNewLog)
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+	return
+}
+
+// executeSpeculatively executes all the transactions on top of the state before the block
+func (p *ParallelStateProcessor) executeSpeculatively(block *EvmBlock, msgs []types.Message, statedb *state.StateDB, cfg vm.Config) []speculativeResult {
+	workers := p.workers
+	if workers > len(msgs) {
+		workers = len(msgs)
+	}
+	// StateDB isn't safe for concurrent use, so each worker reads its own copy
+	copies := make([]*state.StateDB, workers)
+	for w := range copies {
+		copies[w] = statedb.Copy()
+	}
+
+	results := make([]speculativeResult, len(msgs))
+	next := int64(-1)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(base *state.StateDB) {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(msgs) {
+					return
+				}
+				results[i] = p.execute(block, msgs[i], base, cfg, true)
+			}
+		}(copies[w])
+	}
+	wg.Wait()
+	return results
+}
+
+// execute applies the message on top of a new txState over the base state.
+// If speculative is true, a panic is recovered and the result is marked as invalid.
+func (p *ParallelStateProcessor) execute(block *EvmBlock, msg types.Message, base *state.StateDB, cfg vm.Config, speculative bool) (res speculativeResult) {
+	res.state = newTxState(base)
+	defer func() {
+		if !speculative {
+			return
+		}
+		if r := recover(); r != nil {
+			res.invalid = true
+			res.err = fmt.Errorf("%v", r)
+		}
+	}()
+	var (
+		blockContext = NewEVMBlockContext(block.Header(), p.bc, nil)
+		evm          = vm.NewEVM(blockContext, NewEVMTxContext(msg), res.state, p.config, cfg)
+		gp           = new(GasPool).AddGas(msg.Gas())
+	)
+	res.result, res.err = ApplyMessage(evm, msg, gp)
+	return res
+}
@@ -0,0 +1,235 @@
+package evmcore
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+// counterCode is an init code of a contract, which increments slot 0, stores block number into slot CALLER,
+// and logs the counter with CALLER topic. If call data isn't empty, the contract self-destructs.
+var counterCode = common.FromHex("0x6020600c60003960206000f3" +
+	"600054600101600055" + // sstore(0, sload(0) + 1)
+	"433355" + // sstore(caller, number)
+	"600054600052" + // mstore(0, sload(0))
+	"3360206000a1" + // log1(0, 32, caller)
+	"36601d5700" + // if calldatasize == 0 { stop }
+	"5b33ff") // selfdestruct(caller)
+
+type parallelTestEnv struct {
+	t      *testing.T
+	config *params.ChainConfig
+	db     ethdb.Database
+	keys   []*ecdsa.PrivateKey
+	addrs  []common.Address
+
+	genesis *EvmBlock
+	blocks  []*EvmBlock
+	chain   DummyChain
+}
+
+func newParallelTestEnv(t *testing.T, config *params.ChainConfig, accounts int) *parallelTestEnv {
+	env := &parallelTestEnv{
+		t:      t,
+		config: config,
+		db:     rawdb.NewMemoryDatabase(),
+		keys:   make([]*ecdsa.PrivateKey, accounts),
+		addrs:  make([]common.Address, accounts),
+	}
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(env.db), nil)
+	require.NoError(t, err)
+	for i := range env.keys {
+		env.keys[i], _ = crypto.GenerateKey()
+		env.addrs[i] = crypto.PubkeyToAddress(env.keys[i].PublicKey)
+		// the last account has no balance
+		if i != accounts-1 {
+			statedb.AddBalance(env.addrs[i], big.NewInt(1e18))
+		}
+	}
+	root, err := flush(statedb, true)
+	require.NoError(t, err)
+	env.genesis = &EvmBlock{
+		EvmHeader: EvmHeader{
+			Number:   big.NewInt(0),
+			Root:     root,
+			GasLimit: 100000000,
+		},
+	}
+	return env
+}
+
+func (env *parallelTestEnv) tx(gen *BlockGen, from int, to *common.Address, value int64, gas uint64, data []byte) *types.Transaction {
+	return env.txWithNonce(gen, from, gen.TxNonce(env.addrs[from]), to, value, gas, data)
+}
+
+func (env *parallelTestEnv) txWithNonce(gen *BlockGen, from int, nonce uint64, to *common.Address, value int64, gas uint64, data []byte) *types.Transaction {
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(nonce, big.NewInt(value), gas, big.NewInt(1), data)
+	} else {
+		tx = types.NewTransaction(nonce, *to, big.NewInt(value), gas, big.NewInt(1), data)
+	}
+	tx, err := types.SignTx(tx, types.MakeSigner(env.config, gen.Number()), env.keys[from])
+	require.NoError(env.t, err)
+	return tx
+}
+
+// generate makes blocks, including the transactions which were added to a block generator
+func (env *parallelTestEnv) generate(n int, gen func(int, *BlockGen)) {
+	txs := make([]types.Transactions, n)
+	blocks, _, chain := GenerateChain(env.config, env.genesis, env.db, n, func(i int, b *BlockGen) {
+		gen(i, b)
+		txs[i] = b.txs
+	})
+	env.chain = chain
+	env.blocks = make([]*EvmBlock, n)
+	for i, b := range blocks {
+		env.blocks[i] = NewEvmBlock(b.Header(), txs[i])
+	}
+}
+
+type blockProcessor interface {
+	Process(block *EvmBlock, statedb *state.StateDB, cfg vm.Config, usedGas *uint64, internal bool, onNewLog func(*types.Log, *state.StateDB)) (types.Receipts, []*types.Log, []uint32, error)
+}
+
+type processResult struct {
+	Receipts types.Receipts
+	Logs     []*types.Log
+	Skipped  []uint32
+	GasUsed  uint64
+	Root     common.Hash
+}
+
+func (env *parallelTestEnv) process(processor blockProcessor, block *EvmBlock, parentRoot common.Hash) processResult {
+	statedb, err := state.New(parentRoot, state.NewDatabase(env.db), nil)
+	require.NoError(env.t, err)
+	var res processResult
+	var notified []*types.Log
+	res.Receipts, res.Logs, res.Skipped, err = processor.Process(block, statedb, skyhigh.DefaultVMConfig, &res.GasUsed, false, func(l *types.Log, _ *state.StateDB) {
+		notified = append(notified, l)
+	})
+	require.NoError(env.t, err)
+	require.Equal(env.t, res.Logs, notified)
+	res.Root, err = statedb.Commit(true)
+	require.NoError(env.t, err)
+	return res
+}
+
+// checkDifferential compares results of the sequential and parallel processing of every block
+func (env *parallelTestEnv) checkDifferential() {
+	parentRoot := env.genesis.Root
+	for _, block := range env.blocks {
+		expected := env.process(NewStateProcessor(env.config, env.chain), block, parentRoot)
+		expectedJson, err := json.Marshal(expected)
+		require.NoError(env.t, err)
+		for _, workers := range []int{2, 4, 16} {
+			got := env.process(NewParallelStateProcessor(env.config, env.chain, workers), block, parentRoot)
+			gotJson, err := json.Marshal(got)
+			require.NoError(env.t, err)
+			require.Equal(env.t, string(expectedJson), string(gotJson), "block %d, workers %d", block.Number, workers)
+		}
+		parentRoot = block.Root
+	}
+}
+
+func TestParallelStateProcessor(t *testing.T) {
+	for name, config := range map[string]*params.ChainConfig{
+		"skyhigh":      skyhigh.FakeNetRules().EvmChainConfig(),
+		"preByzantium": {ChainID: big.NewInt(1), HomesteadBlock: big.NewInt(0), EIP150Block: big.NewInt(0), EIP155Block: big.NewInt(0), EIP158Block: big.NewInt(0)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			testParallelStateProcessor(t, config)
+		})
+	}
+}
+
+func testParallelStateProcessor(t *testing.T, config *params.ChainConfig) {
+	const accounts = 20
+	env := newParallelTestEnv(t, config, accounts)
+	noBalance := accounts - 1
+	contract := crypto.CreateAddress(env.addrs[0], 0)
+	empty := common.Address{0xee}
+	env.generate(4, func(i int, gen *BlockGen) {
+		switch i {
+		case 0:
+			// contract creation and independent transfers
+			gen.AddTx(env.tx(gen, 0, nil, 0, 200000, counterCode))
+			for from := 1; from < noBalance; from++ {
+				to := common.Address{byte(from)}
+				gen.AddTx(env.tx(gen, from, &to, int64(from), 21000, nil))
+			}
+		case 1:
+			// conflicting contract calls, chains of transfers and txs from the same sender
+			for from := 1; from < 10; from++ {
+				gen.AddTx(env.tx(gen, from, &contract, 0, 100000, nil))
+			}
+			for from := 10; from < noBalance-1; from++ {
+				gen.AddTx(env.tx(gen, from, &env.addrs[from+1], 1e17, 21000, nil))
+			}
+			for n := 0; n < 3; n++ {
+				gen.AddTx(env.tx(gen, 1, &env.addrs[2], 1, 21000, nil))
+			}
+			// touch of an empty account
+			gen.AddTx(env.tx(gen, 3, &empty, 0, 21000, nil))
+			// skipped transactions: no balance, nonce too high, intrinsic gas too low
+			gen.AddUncheckedTx(env.txWithNonce(gen, noBalance, 0, &empty, 0, 21000, nil))
+			gen.AddUncheckedTx(env.txWithNonce(gen, 4, 100, &empty, 0, 21000, nil))
+			gen.AddUncheckedTx(env.txWithNonce(gen, 5, gen.TxNonce(env.addrs[5]), &empty, 0, 20000, nil))
+			gen.AddTx(env.tx(gen, 5, &contract, 0, 100000, nil))
+			// failed transaction
+			gen.AddTx(env.tx(gen, 6, &contract, 0, 30000, nil))
+		case 2:
+			// self-destruct in the middle of the block
+			gen.AddTx(env.tx(gen, 1, &contract, 0, 100000, nil))
+			gen.AddTx(env.tx(gen, 2, &contract, 0, 100000, []byte{1}))
+			gen.AddTx(env.tx(gen, 3, &contract, 1, 100000, nil))
+			gen.AddTx(env.tx(gen, 4, &env.addrs[2], 1, 21000, nil))
+		case 3:
+			// another contract creation and calls to it
+			gen.AddTx(env.tx(gen, 7, nil, 5, 200000, counterCode))
+			recreated := crypto.CreateAddress(env.addrs[7], gen.TxNonce(env.addrs[7])-1)
+			for from := 8; from < 12; from++ {
+				gen.AddTx(env.tx(gen, from, &recreated, 0, 100000, nil))
+			}
+		}
+	})
+	env.checkDifferential()
+}
+
+func TestParallelStateProcessor_Random(t *testing.T) {
+	const accounts = 10
+	config := skyhigh.FakeNetRules().EvmChainConfig()
+	env := newParallelTestEnv(t, config, accounts)
+	r := rand.New(rand.NewSource(0))
+	env.generate(10, func(i int, gen *BlockGen) {
+		if i == 0 {
+			gen.AddTx(env.tx(gen, 0, nil, 0, 200000, counterCode))
+			return
+		}
+		contract := crypto.CreateAddress(env.addrs[0], 0)
+		for n := 0; n < 30; n++ {
+			from := r.Intn(accounts - 1)
+			if r.Intn(3) == 0 {
+				gen.AddTx(env.tx(gen, from, &contract, r.Int63n(1000), 100000, nil))
+				continue
+			}
+			to := env.addrs[r.Intn(accounts)]
+			gen.AddTx(env.tx(gen, from, &to, r.Int63n(1e16), 21000, nil))
+		}
+	})
+	env.checkDifferential()
+}
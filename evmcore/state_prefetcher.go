@@ -25,17 +25,17 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 )
 
-// statePrefetcher is a basic Prefetcher, which blindly executes a block on top
+// StatePrefetcher is a basic Prefetcher, which blindly executes a block on top
 // of an arbitrary state with the goal of prefetching potentially useful state
 // data from disk before the main block processor start executing.
-type statePrefetcher struct {
+type StatePrefetcher struct {
 	config *params.ChainConfig // Chain configuration options
 	bc     DummyChain          // Canonical block chain
 }
 
-// newStatePrefetcher initialises a new statePrefetcher.
-func newStatePrefetcher(config *params.ChainConfig, bc DummyChain) *statePrefetcher {
-	return &statePrefetcher{
+// NewStatePrefetcher initialises a new StatePrefetcher.
+func NewStatePrefetcher(config *params.ChainConfig, bc DummyChain) *StatePrefetcher {
+	return &StatePrefetcher{
 		config: config,
 		bc:     bc,
 	}
@@ -44,7 +44,9 @@ func newStatePrefetcher(config *params.ChainConfig, bc DummyChain) *statePrefetc
 // Prefetch processes the state changes according to the Ethereum rules by running
 // the transaction messages using the statedb, but any changes are discarded. The
 // only goal is to pre-cache transaction signatures and state trie nodes.
-func (p *statePrefetcher) Prefetch(block *EvmBlock, statedb *state.StateDB, cfg vm.Config, interrupt *uint32) {
+// Transactions which cannot be applied are skipped, because the block may be
+// executed speculatively on top of a state which differs from the final one.
+func (p *StatePrefetcher) Prefetch(block *EvmBlock, statedb *state.StateDB, cfg vm.Config, interrupt *uint32) {
 	var (
 		header       = block.Header()
 		gaspool      = new(GasPool).AddGas(block.GasLimit)
@@ -62,11 +64,11 @@ func (p *statePrefetcher) Prefetch(block *EvmBlock, statedb *state.StateDB, cfg
 		// Convert the transaction into an executable message and pre-cache its sender
 		msg, err := tx.AsMessage(signer)
 		if err != nil {
-			return // Also invalid block, bail out
+			continue // Invalid transaction, skip it
 		}
 		statedb.Prepare(tx.Hash(), block.Hash, i)
 		if err := precacheTransaction(msg, p.config, gaspool, statedb, header, evm); err != nil {
-			return // Ugh, something went horribly wrong, bail out
+			continue // Transaction would be skipped, try the next one
 		}
 		// If we're pre-byzantium, pre-load trie nodes for the intermediate root
 		if !byzantium {
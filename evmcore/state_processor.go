@@ -118,6 +118,22 @@ func applyTransaction(
 	if err != nil {
 		return nil, 0, result == nil, err
 	}
+	receipt := finaliseTransaction(msg, config, statedb, header, tx, result, usedGas, onNewLog)
+	return receipt, result.UsedGas, false, err
+}
+
+// finaliseTransaction notifies about logs of the applied transaction, updates the state with pending changes,
+// and creates a receipt for the transaction.
+func finaliseTransaction(
+	msg types.Message,
+	config *params.ChainConfig,
+	statedb *state.StateDB,
+	header *EvmHeader,
+	tx *types.Transaction,
+	result *ExecutionResult,
+	usedGas *uint64,
+	onNewLog func(*types.Log, *state.StateDB),
+) *types.Receipt {
 	// Notify about logs with potential state changes
 	logs := statedb.GetLogs(tx.Hash())
 	for _, l := range logs {
@@ -133,9 +149,21 @@ func applyTransaction(
 	}
 	*usedGas += result.UsedGas
 
-	// Create a new receipt for the transaction, storing the intermediate root and gas used
-	// by the tx.
-	receipt := &types.Receipt{Type: tx.Type(), PostState: root, CumulativeGasUsed: *usedGas}
+	return newReceipt(msg, tx, result, root, *usedGas, logs, statedb, header)
+}
+
+// newReceipt creates a new receipt for the transaction, storing the intermediate root and gas used by the tx.
+func newReceipt(
+	msg types.Message,
+	tx *types.Transaction,
+	result *ExecutionResult,
+	root []byte,
+	cumulativeGasUsed uint64,
+	logs []*types.Log,
+	statedb *state.StateDB,
+	header *EvmHeader,
+) *types.Receipt {
+	receipt := &types.Receipt{Type: tx.Type(), PostState: root, CumulativeGasUsed: cumulativeGasUsed}
 	if result.Failed() {
 		receipt.Status = types.ReceiptStatusFailed
 	} else {
@@ -146,7 +174,7 @@ func applyTransaction(
 
 	// If the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
-		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+		receipt.ContractAddress = crypto.CreateAddress(msg.From(), tx.Nonce())
 	}
 
 	// Set the receipt logs.
@@ -154,5 +182,5 @@ func applyTransaction(
 	receipt.BlockHash = statedb.BlockHash()
 	receipt.BlockNumber = header.Number
 	receipt.TransactionIndex = uint(statedb.TxIndex())
-	return receipt, result.UsedGas, false, err
+	return receipt
 }
@@ -346,7 +346,9 @@ func (pool *TxPool) loop() {
 		if time.Since(pool.lastGasPriceUpdate) > time.Second {
 			newPrice := pool.chain.RecommendedMinGasPrice()
 			if newPrice != nil {
+				pool.mu.RLock()
 				cfgLimit := new(big.Int).SetUint64(pool.config.PriceLimit)
+				pool.mu.RUnlock()
 				if newPrice.Cmp(cfgLimit) < 0 {
 					newPrice = cfgLimit
 				}
@@ -460,6 +462,27 @@ func (pool *TxPool) SetGasPriceWithCap(price, cap *big.Int) {
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
+// SetConfig applies the limits of the config to the running pool, i.e. the price limit and bump,
+// the slots, the queues and the lifetime. The rest of the fields take effect only after a restart.
+func (pool *TxPool) SetConfig(config TxPoolConfig) {
+	config = (&config).sanitize()
+
+	pool.mu.Lock()
+	pool.config.PriceLimit = config.PriceLimit
+	pool.config.PriceBump = config.PriceBump
+	pool.config.AccountSlots = config.AccountSlots
+	pool.config.GlobalSlots = config.GlobalSlots
+	pool.config.AccountQueue = config.AccountQueue
+	pool.config.GlobalQueue = config.GlobalQueue
+	pool.config.Lifetime = config.Lifetime
+	pool.mu.Unlock()
+
+	// drop the excessive transactions if the limits were lowered, the price limit is applied by the loop
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer))
+	log.Info("Transaction pool limits updated", "accountslots", config.AccountSlots, "globalslots", config.GlobalSlots,
+		"accountqueue", config.AccountQueue, "globalqueue", config.GlobalQueue)
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 func (pool *TxPool) Nonce(addr common.Address) uint64 {
@@ -1139,6 +1139,50 @@ func TestTransactionPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+// Tests that the lowered limits are enforced after the config is reloaded.
+func TestTransactionPoolSetConfig(t *testing.T) {
+	t.Parallel()
+
+	// Create the pool to test the limit enforcement with
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	// Create a number of test accounts, fund them and fill the pool within the limits
+	keys := make([]*ecdsa.PrivateKey, 4)
+	txs := types.Transactions{}
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+		for j := uint64(0); j < testTxPoolConfig.AccountSlots; j++ {
+			txs = append(txs, transaction(j, 100000, keys[i]))
+		}
+	}
+	pool.AddRemotesSync(txs)
+	if pending, _ := pool.Stats(); pending != len(txs) {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, len(txs))
+	}
+
+	// Lower the limits and check that the excessive transactions are dropped
+	config := testTxPoolConfig
+	config.AccountSlots = 2
+	config.GlobalSlots = 4
+	config.PriceBump = 50
+	pool.SetConfig(config)
+
+	if pending, _ := pool.Stats(); pending > len(keys)*int(config.AccountSlots) {
+		t.Fatalf("total pending transactions overflow allowance: %d > %d", pending, len(keys)*int(config.AccountSlots))
+	}
+	if pool.config.PriceBump != config.PriceBump {
+		t.Fatalf("price bump mismatched: have %d, want %d", pool.config.PriceBump, config.PriceBump)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the limit on transaction size is enforced correctly.
 // This test verifies every transaction having allowed size
 // is added to the pool, and longer transactions are rejected.
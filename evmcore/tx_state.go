@@ -0,0 +1,576 @@
+package evmcore
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+	// ripemd is a precompile which is touched persistently, see go-ethereum/core/state/journal.go
+	ripemd = common.HexToAddress("0000000000000000000000000000000000000003")
+
+	errForEachStorageUnsupported = errors.New("ForEachStorage isn't supported by the transaction state")
+)
+
+// stateKeyKind is a kind of an item in a transaction's read or write set
+type stateKeyKind uint8
+
+const (
+	accountKey stateKeyKind = iota // existence of an account, written on creation and destruction
+	balanceKey
+	nonceKey
+	codeKey
+	storageKey
+)
+
+// stateKey is an item of a transaction's read or write set
+type stateKey struct {
+	kind stateKeyKind
+	addr common.Address
+	slot common.Hash
+}
+
+// txAccount is an account, as observed and modified by a transaction
+type txAccount struct {
+	exists   bool
+	suicided bool
+	// created is true if account object was (re)created by the transaction, i.e. base storage isn't visible
+	created bool
+
+	balance  *big.Int // nil if not loaded
+	nonce    uint64
+	code     []byte
+	codeHash common.Hash
+
+	nonceLoaded bool
+	codeLoaded  bool
+
+	balanceWritten bool
+	nonceWritten   bool
+	codeWritten    bool
+
+	committed map[common.Hash]common.Hash // storage at the beginning of the transaction
+	storage   map[common.Hash]common.Hash // storage written by the transaction
+}
+
+func (a *txAccount) copy() *txAccount {
+	cp := *a
+	if a.balance != nil {
+		cp.balance = new(big.Int).Set(a.balance)
+	}
+	cp.committed = make(map[common.Hash]common.Hash, len(a.committed))
+	for k, v := range a.committed {
+		cp.committed[k] = v
+	}
+	cp.storage = make(map[common.Hash]common.Hash, len(a.storage))
+	for k, v := range a.storage {
+		cp.storage[k] = v
+	}
+	return &cp
+}
+
+type txJournalEntry struct {
+	addr   *common.Address // dirtied account, if any
+	revert func()
+}
+
+// txState is a vm.StateDB of a single transaction, executed on top of a read-only base state.
+// It records the read set of the transaction and buffers its writes, so the transaction may be
+// executed speculatively and applied to the base state later, if the read set is still valid.
+// Semantics of the modifications follow go-ethereum's state.StateDB.
+type txState struct {
+	base *state.StateDB
+
+	accounts map[common.Address]*txAccount
+	reads    map[stateKey]struct{}
+
+	journal   []txJournalEntry
+	snapshots []int
+	dirties   map[common.Address]int
+
+	refund     uint64
+	logs       []*types.Log
+	preimages  map[common.Hash][]byte
+	accessList map[common.Address]map[common.Hash]struct{}
+}
+
+func newTxState(base *state.StateDB) *txState {
+	return &txState{
+		base:       base,
+		accounts:   make(map[common.Address]*txAccount),
+		reads:      make(map[stateKey]struct{}),
+		dirties:    make(map[common.Address]int),
+		preimages:  make(map[common.Hash][]byte),
+		accessList: make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+func (s *txState) read(kind stateKeyKind, addr common.Address, slot common.Hash) {
+	s.reads[stateKey{kind, addr, slot}] = struct{}{}
+}
+
+func (s *txState) appendJournal(addr *common.Address, revert func()) {
+	s.journal = append(s.journal, txJournalEntry{addr, revert})
+	if addr != nil {
+		s.dirties[*addr]++
+	}
+}
+
+// account returns the account, loading its existence from the base state
+func (s *txState) account(addr common.Address) *txAccount {
+	a := s.accounts[addr]
+	if a == nil {
+		a = &txAccount{
+			exists:    s.base.Exist(addr),
+			committed: make(map[common.Hash]common.Hash),
+			storage:   make(map[common.Hash]common.Hash),
+		}
+		s.read(accountKey, addr, common.Hash{})
+		s.accounts[addr] = a
+	}
+	return a
+}
+
+func (s *txState) getOrNewAccount(addr common.Address) *txAccount {
+	a := s.account(addr)
+	if !a.exists {
+		s.createAccount(addr, a)
+	}
+	return a
+}
+
+func (s *txState) createAccount(addr common.Address, a *txAccount) {
+	prev := a.copy()
+	if !a.exists {
+		s.appendJournal(&addr, func() { *a = *prev })
+	} else {
+		// re-creation of an existing account doesn't dirty it
+		s.appendJournal(nil, func() { *a = *prev })
+	}
+	a.exists = true
+	a.suicided = false
+	a.created = true
+	a.balance = new(big.Int)
+	a.nonce, a.nonceLoaded = 0, true
+	a.code, a.codeHash, a.codeLoaded = nil, emptyCodeHash, true
+	a.balanceWritten, a.nonceWritten, a.codeWritten = false, false, false
+	a.committed = make(map[common.Hash]common.Hash)
+	a.storage = make(map[common.Hash]common.Hash)
+}
+
+func (s *txState) balanceOf(addr common.Address, a *txAccount) *big.Int {
+	if !a.exists {
+		return common.Big0
+	}
+	if a.balance == nil {
+		a.balance = new(big.Int).Set(s.base.GetBalance(addr))
+		s.read(balanceKey, addr, common.Hash{})
+	}
+	return a.balance
+}
+
+func (s *txState) nonceOf(addr common.Address, a *txAccount) uint64 {
+	if !a.exists {
+		return 0
+	}
+	if !a.nonceLoaded {
+		a.nonce, a.nonceLoaded = s.base.GetNonce(addr), true
+		s.read(nonceKey, addr, common.Hash{})
+	}
+	return a.nonce
+}
+
+func (s *txState) loadCode(addr common.Address, a *txAccount) {
+	if !a.codeLoaded {
+		a.code, a.codeHash, a.codeLoaded = s.base.GetCode(addr), s.base.GetCodeHash(addr), true
+		s.read(codeKey, addr, common.Hash{})
+	}
+}
+
+func (s *txState) committedState(addr common.Address, a *txAccount, key common.Hash) common.Hash {
+	if !a.exists || a.created {
+		return common.Hash{}
+	}
+	value, ok := a.committed[key]
+	if !ok {
+		value = s.base.GetCommittedState(addr, key)
+		a.committed[key] = value
+		s.read(storageKey, addr, key)
+	}
+	return value
+}
+
+func (s *txState) stateOf(addr common.Address, a *txAccount, key common.Hash) common.Hash {
+	if value, ok := a.storage[key]; ok && a.exists {
+		return value
+	}
+	return s.committedState(addr, a, key)
+}
+
+func (s *txState) empty(addr common.Address, a *txAccount) bool {
+	if !a.exists {
+		return true
+	}
+	s.loadCode(addr, a)
+	return s.nonceOf(addr, a) == 0 && s.balanceOf(addr, a).Sign() == 0 && a.codeHash == emptyCodeHash
+}
+
+func (s *txState) setBalance(addr common.Address, a *txAccount, amount *big.Int) {
+	prev, prevWritten := s.balanceOf(addr, a), a.balanceWritten
+	s.appendJournal(&addr, func() { a.balance, a.balanceWritten = prev, prevWritten })
+	a.balance, a.balanceWritten = amount, true
+}
+
+func (s *txState) CreateAccount(addr common.Address) {
+	a := s.account(addr)
+	var balance *big.Int
+	if a.exists {
+		// balance is carried over to the new account
+		balance = new(big.Int).Set(s.balanceOf(addr, a))
+	}
+	s.createAccount(addr, a)
+	if balance != nil {
+		a.balance = balance
+		a.balanceWritten = balance.Sign() != 0
+	}
+}
+
+func (s *txState) SubBalance(addr common.Address, amount *big.Int) {
+	a := s.getOrNewAccount(addr)
+	if amount.Sign() == 0 {
+		return
+	}
+	s.setBalance(addr, a, new(big.Int).Sub(s.balanceOf(addr, a), amount))
+}
+
+func (s *txState) AddBalance(addr common.Address, amount *big.Int) {
+	a := s.getOrNewAccount(addr)
+	if amount.Sign() == 0 {
+		if s.empty(addr, a) {
+			// touch
+			s.appendJournal(&addr, func() {})
+			if addr == ripemd {
+				s.dirties[addr]++
+			}
+		}
+		return
+	}
+	s.setBalance(addr, a, new(big.Int).Add(s.balanceOf(addr, a), amount))
+}
+
+func (s *txState) GetBalance(addr common.Address) *big.Int {
+	return s.balanceOf(addr, s.account(addr))
+}
+
+func (s *txState) GetNonce(addr common.Address) uint64 {
+	return s.nonceOf(addr, s.account(addr))
+}
+
+func (s *txState) SetNonce(addr common.Address, nonce uint64) {
+	a := s.getOrNewAccount(addr)
+	prev, prevWritten := s.nonceOf(addr, a), a.nonceWritten
+	s.appendJournal(&addr, func() { a.nonce, a.nonceWritten = prev, prevWritten })
+	a.nonce, a.nonceWritten = nonce, true
+}
+
+func (s *txState) GetCodeHash(addr common.Address) common.Hash {
+	a := s.account(addr)
+	if !a.exists {
+		return common.Hash{}
+	}
+	s.loadCode(addr, a)
+	return a.codeHash
+}
+
+func (s *txState) GetCode(addr common.Address) []byte {
+	a := s.account(addr)
+	if !a.exists {
+		return nil
+	}
+	s.loadCode(addr, a)
+	return a.code
+}
+
+func (s *txState) SetCode(addr common.Address, code []byte) {
+	a := s.getOrNewAccount(addr)
+	s.loadCode(addr, a)
+	prevCode, prevHash, prevWritten := a.code, a.codeHash, a.codeWritten
+	s.appendJournal(&addr, func() { a.code, a.codeHash, a.codeWritten = prevCode, prevHash, prevWritten })
+	a.code, a.codeHash, a.codeWritten = code, crypto.Keccak256Hash(code), true
+}
+
+func (s *txState) GetCodeSize(addr common.Address) int {
+	return len(s.GetCode(addr))
+}
+
+func (s *txState) AddRefund(gas uint64) {
+	prev := s.refund
+	s.appendJournal(nil, func() { s.refund = prev })
+	s.refund += gas
+}
+
+func (s *txState) SubRefund(gas uint64) {
+	prev := s.refund
+	s.appendJournal(nil, func() { s.refund = prev })
+	if gas > s.refund {
+		panic("refund counter below zero")
+	}
+	s.refund -= gas
+}
+
+func (s *txState) GetRefund() uint64 {
+	return s.refund
+}
+
+func (s *txState) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	return s.committedState(addr, s.account(addr), key)
+}
+
+func (s *txState) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.stateOf(addr, s.account(addr), key)
+}
+
+func (s *txState) SetState(addr common.Address, key, value common.Hash) {
+	a := s.getOrNewAccount(addr)
+	prev := s.stateOf(addr, a, key)
+	if prev == value {
+		return
+	}
+	prevDirty, wasDirty := a.storage[key]
+	s.appendJournal(&addr, func() {
+		if wasDirty {
+			a.storage[key] = prevDirty
+		} else {
+			delete(a.storage, key)
+		}
+	})
+	a.storage[key] = value
+}
+
+func (s *txState) Suicide(addr common.Address) bool {
+	a := s.account(addr)
+	if !a.exists {
+		return false
+	}
+	prev, prevBalance, prevWritten := a.suicided, s.balanceOf(addr, a), a.balanceWritten
+	s.appendJournal(&addr, func() { a.suicided, a.balance, a.balanceWritten = prev, prevBalance, prevWritten })
+	a.suicided = true
+	a.balance, a.balanceWritten = new(big.Int), true
+	return true
+}
+
+func (s *txState) HasSuicided(addr common.Address) bool {
+	a := s.account(addr)
+	return a.exists && a.suicided
+}
+
+func (s *txState) Exist(addr common.Address) bool {
+	return s.account(addr).exists
+}
+
+func (s *txState) Empty(addr common.Address) bool {
+	return s.empty(addr, s.account(addr))
+}
+
+func (s *txState) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	s.AddAddressToAccessList(sender)
+	if dst != nil {
+		s.AddAddressToAccessList(*dst)
+	}
+	for _, addr := range precompiles {
+		s.AddAddressToAccessList(addr)
+	}
+	for _, el := range list {
+		s.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			s.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
+func (s *txState) AddressInAccessList(addr common.Address) bool {
+	_, ok := s.accessList[addr]
+	return ok
+}
+
+func (s *txState) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool) {
+	slots, addressOk := s.accessList[addr]
+	if !addressOk {
+		return false, false
+	}
+	_, slotOk = slots[slot]
+	return addressOk, slotOk
+}
+
+func (s *txState) AddAddressToAccessList(addr common.Address) {
+	if _, ok := s.accessList[addr]; ok {
+		return
+	}
+	s.accessList[addr] = make(map[common.Hash]struct{})
+	s.appendJournal(nil, func() { delete(s.accessList, addr) })
+}
+
+func (s *txState) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(addr)
+	slots := s.accessList[addr]
+	if _, ok := slots[slot]; ok {
+		return
+	}
+	slots[slot] = struct{}{}
+	s.appendJournal(nil, func() { delete(slots, slot) })
+}
+
+func (s *txState) Snapshot() int {
+	s.snapshots = append(s.snapshots, len(s.journal))
+	return len(s.snapshots) - 1
+}
+
+func (s *txState) RevertToSnapshot(id int) {
+	if id < 0 || id >= len(s.snapshots) {
+		panic("revision id cannot be reverted")
+	}
+	snapshot := s.snapshots[id]
+	for i := len(s.journal) - 1; i >= snapshot; i-- {
+		s.journal[i].revert()
+		if addr := s.journal[i].addr; addr != nil {
+			if s.dirties[*addr]--; s.dirties[*addr] == 0 {
+				delete(s.dirties, *addr)
+			}
+		}
+	}
+	s.journal = s.journal[:snapshot]
+	s.snapshots = s.snapshots[:id]
+}
+
+func (s *txState) AddLog(log *types.Log) {
+	s.appendJournal(nil, func() { s.logs = s.logs[:len(s.logs)-1] })
+	s.logs = append(s.logs, log)
+}
+
+func (s *txState) AddPreimage(hash common.Hash, preimage []byte) {
+	if _, ok := s.preimages[hash]; ok {
+		return
+	}
+	s.preimages[hash] = common.CopyBytes(preimage)
+	s.appendJournal(nil, func() { delete(s.preimages, hash) })
+}
+
+func (s *txState) ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) error {
+	return errForEachStorageUnsupported
+}
+
+// modified returns the accounts which have to be applied to the base state, in a deterministic order
+func (s *txState) modified() []common.Address {
+	addrs := make([]common.Address, 0, len(s.dirties))
+	for addr, a := range s.accounts {
+		if s.dirties[addr] > 0 || a.created {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].Hash().Big().Cmp(addrs[j].Hash().Big()) < 0
+	})
+	return addrs
+}
+
+// writes returns the write set of the transaction
+func (s *txState) writes() map[stateKey]struct{} {
+	writes := make(map[stateKey]struct{})
+	for _, addr := range s.modified() {
+		a := s.accounts[addr]
+		put := func(kind stateKeyKind, slot common.Hash) {
+			writes[stateKey{kind, addr, slot}] = struct{}{}
+		}
+		// account may be created, destructed, or removed as empty on finalization
+		if a.created || a.suicided || (s.dirties[addr] > 0 && s.empty(addr, a)) {
+			put(accountKey, common.Hash{})
+			put(balanceKey, common.Hash{})
+			put(nonceKey, common.Hash{})
+			put(codeKey, common.Hash{})
+		}
+		if a.balanceWritten {
+			put(balanceKey, common.Hash{})
+		}
+		if a.nonceWritten {
+			put(nonceKey, common.Hash{})
+		}
+		if a.codeWritten {
+			put(codeKey, common.Hash{})
+		}
+		for key := range a.storage {
+			put(storageKey, key)
+		}
+	}
+	return writes
+}
+
+// conflicts returns true if the transaction has read any of the written keys
+func (s *txState) conflicts(written map[stateKey]struct{}) bool {
+	if len(written) < len(s.reads) {
+		for key := range written {
+			if _, ok := s.reads[key]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	for key := range s.reads {
+		if _, ok := written[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTo applies the transaction's modifications to the statedb.
+// Logs are added to the statedb, so Prepare should be called before.
+func (s *txState) applyTo(statedb *state.StateDB) {
+	for _, addr := range s.modified() {
+		a := s.accounts[addr]
+		if a.created {
+			statedb.CreateAccount(addr)
+		}
+		if !a.exists {
+			continue
+		}
+		if a.balanceWritten {
+			statedb.SetBalance(addr, a.balance)
+		}
+		if a.nonceWritten {
+			statedb.SetNonce(addr, a.nonce)
+		}
+		if a.codeWritten {
+			statedb.SetCode(addr, a.code)
+		}
+		keys := make([]common.Hash, 0, len(a.storage))
+		for key := range a.storage {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].Big().Cmp(keys[j].Big()) < 0
+		})
+		for _, key := range keys {
+			statedb.SetState(addr, key, a.storage[key])
+		}
+		if a.suicided {
+			statedb.Suicide(addr)
+		}
+		if s.dirties[addr] > 0 {
+			// touch, so the account is removed on finalization if it's empty
+			statedb.AddBalance(addr, common.Big0)
+		}
+	}
+	for _, log := range s.logs {
+		statedb.AddLog(log)
+	}
+	for hash, preimage := range s.preimages {
+		statedb.AddPreimage(hash, preimage)
+	}
+}
@@ -0,0 +1,263 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/filters"
+)
+
+// DecodedCall is a contract call decoded with the contract ABI
+type DecodedCall struct {
+	Method    string                 `json:"method"`
+	Signature string                 `json:"signature"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// DecodedEvent is a contract log decoded with the contract ABI
+type DecodedEvent struct {
+	Event     string                 `json:"event"`
+	Signature string                 `json:"signature"`
+	Args      map[string]interface{} `json:"args"`
+}
+
+// DecodedLog is a log along with its decoding. Decoded is nil if contract ABI or event is unknown.
+type DecodedLog struct {
+	Log     *types.Log    `json:"log"`
+	Decoded *DecodedEvent `json:"decoded"`
+}
+
+// DecodedTransaction is a transaction along with decoding of its input. Decoded is nil if contract ABI or method is unknown.
+type DecodedTransaction struct {
+	Hash    common.Hash     `json:"hash"`
+	To      *common.Address `json:"to"`
+	Decoded *DecodedCall    `json:"decoded"`
+}
+
+// contractABIs parses and memorizes the registered ABIs during a single API call
+type contractABIs struct {
+	store  *Store
+	parsed map[common.Address]*abi.ABI
+}
+
+func newContractABIs(store *Store) *contractABIs {
+	return &contractABIs{
+		store:  store,
+		parsed: make(map[common.Address]*abi.ABI),
+	}
+}
+
+// Get returns parsed ABI of the contract, or nil if ABI isn't known
+func (c *contractABIs) Get(addr common.Address) (*abi.ABI, error) {
+	if parsed, ok := c.parsed[addr]; ok {
+		return parsed, nil
+	}
+	var parsed *abi.ABI
+	if abiJSON := c.store.GetContractABI(addr); len(abiJSON) != 0 {
+		res, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI of contract %s: %v", addr.String(), err)
+		}
+		parsed = &res
+	}
+	c.parsed[addr] = parsed
+	return parsed, nil
+}
+
+// decodeLog returns nil if the log can't be decoded with the contract ABI
+func decodeLog(contract *abi.ABI, l *types.Log) *DecodedEvent {
+	if contract == nil || len(l.Topics) == 0 {
+		return nil
+	}
+	event, err := contract.EventByID(l.Topics[0])
+	if err != nil {
+		return nil
+	}
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	args := make(map[string]interface{})
+	if err := abi.ParseTopicsIntoMap(args, indexed, l.Topics[1:]); err != nil {
+		return nil
+	}
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(args, l.Data); err != nil {
+		return nil
+	}
+	return &DecodedEvent{
+		Event:     event.Name,
+		Signature: event.Sig,
+		Args:      formatABIValues(args),
+	}
+}
+
+// decodeCall returns nil if the call input can't be decoded with the contract ABI
+func decodeCall(contract *abi.ABI, input []byte) *DecodedCall {
+	if contract == nil || len(input) < 4 {
+		return nil
+	}
+	method, err := contract.MethodById(input[:4])
+	if err != nil {
+		return nil
+	}
+	args := make(map[string]interface{})
+	if err := method.Inputs.UnpackIntoMap(args, input[4:]); err != nil {
+		return nil
+	}
+	return &DecodedCall{
+		Method:    method.Name,
+		Signature: method.Sig,
+		Args:      formatABIValues(args),
+	}
+}
+
+func formatABIValues(args map[string]interface{}) map[string]interface{} {
+	for name, v := range args {
+		args[name] = formatABIValue(reflect.ValueOf(v))
+	}
+	return args
+}
+
+// formatABIValue converts the unpacked values into the RPC encoding: numbers and bytes are hex-encoded
+func formatABIValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		return (*hexutil.Big)(x)
+	case common.Address, common.Hash:
+		return x
+	case []byte:
+		return hexutil.Bytes(x)
+	}
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return hexutil.Uint64(v.Uint())
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return (*hexutil.Big)(big.NewInt(v.Int()))
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(buf), v)
+			return hexutil.Bytes(buf)
+		}
+		fallthrough
+	case reflect.Slice:
+		res := make([]interface{}, v.Len())
+		for i := range res {
+			res[i] = formatABIValue(v.Index(i))
+		}
+		return res
+	case reflect.Struct:
+		res := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			res[abi.ToCamelCase(v.Type().Field(i).Name)] = formatABIValue(v.Field(i))
+		}
+		return res
+	}
+	return v.Interface()
+}
+
+// PublicABIDecodingAPI provides an API to decode logs and txs of the contracts with registered ABIs.
+type PublicABIDecodingAPI struct {
+	s       *Service
+	filters *filters.PublicFilterAPI
+}
+
+// NewPublicABIDecodingAPI creates a new ABI decoding API.
+func NewPublicABIDecodingAPI(s *Service, filters *filters.PublicFilterAPI) *PublicABIDecodingAPI {
+	return &PublicABIDecodingAPI{s, filters}
+}
+
+// GetDecodedLogs returns logs matching the given criteria, decoded with ABIs of the emitting contracts.
+func (api *PublicABIDecodingAPI) GetDecodedLogs(ctx context.Context, crit filters.FilterCriteria) ([]*DecodedLog, error) {
+	logs, err := api.filters.GetLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+	abis := newContractABIs(api.s.store)
+	res := make([]*DecodedLog, len(logs))
+	for i, l := range logs {
+		contract, err := abis.Get(l.Address)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = &DecodedLog{
+			Log:     l,
+			Decoded: decodeLog(contract, l),
+		}
+	}
+	return res, nil
+}
+
+// GetDecodedTransaction returns the transaction input decoded with ABI of the called contract.
+func (api *PublicABIDecodingAPI) GetDecodedTransaction(ctx context.Context, hash common.Hash) (*DecodedTransaction, error) {
+	tx, _, _, err := api.s.EthAPI.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, nil
+	}
+	res := &DecodedTransaction{
+		Hash: hash,
+		To:   tx.To(),
+	}
+	if tx.To() != nil {
+		contract, err := newContractABIs(api.s.store).Get(*tx.To())
+		if err != nil {
+			return nil, err
+		}
+		res.Decoded = decodeCall(contract, tx.Data())
+	}
+	return res, nil
+}
+
+// PrivateABIRegistryAPI provides an API to register ABIs of contracts.
+type PrivateABIRegistryAPI struct {
+	s *Service
+}
+
+// NewPrivateABIRegistryAPI creates a new ABI registry API.
+func NewPrivateABIRegistryAPI(s *Service) *PrivateABIRegistryAPI {
+	return &PrivateABIRegistryAPI{s}
+}
+
+// AddContractABI registers ABI JSON of the contract, replacing the previous one.
+func (api *PrivateABIRegistryAPI) AddContractABI(addr common.Address, abiJSON string) (bool, error) {
+	if len(abiJSON) == 0 {
+		return false, errors.New("empty ABI")
+	}
+	if _, err := abi.JSON(strings.NewReader(abiJSON)); err != nil {
+		return false, fmt.Errorf("invalid ABI: %v", err)
+	}
+	api.s.store.SetContractABI(addr, abiJSON)
+	return true, nil
+}
+
+// RemoveContractABI unregisters ABI of the contract. ABIs of the system contracts remain known.
+func (api *PrivateABIRegistryAPI) RemoveContractABI(addr common.Address) bool {
+	api.s.store.DelContractABI(addr)
+	return true
+}
+
+// GetContractABI returns ABI JSON of the contract, or nil if ABI isn't known.
+func (api *PrivateABIRegistryAPI) GetContractABI(addr common.Address) *string {
+	abiJSON := api.s.store.GetContractABI(addr)
+	if len(abiJSON) == 0 {
+		return nil
+	}
+	return &abiJSON
+}
@@ -0,0 +1,86 @@
+package gossip
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/ballot"
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/sfc100"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/sfc"
+)
+
+func TestContractABIs(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+
+	// system contracts are known without registration
+	require.Equal(sfc100.ContractABI, store.GetContractABI(sfc.ContractAddress))
+
+	addr := common.Address{1}
+	require.Empty(store.GetContractABI(addr))
+	store.SetContractABI(addr, ballot.ContractABI)
+	require.Equal(ballot.ContractABI, store.GetContractABI(addr))
+	store.DelContractABI(addr)
+	require.Empty(store.GetContractABI(addr))
+
+	abis := newContractABIs(store)
+	contract, err := abis.Get(sfc.ContractAddress)
+	require.NoError(err)
+	require.NotNil(contract)
+	unknown, err := abis.Get(addr)
+	require.NoError(err)
+	require.Nil(unknown)
+
+	// call
+	input, err := contract.Pack("delegate", big.NewInt(5))
+	require.NoError(err)
+	call := decodeCall(contract, input)
+	require.NotNil(call)
+	require.Equal("delegate", call.Method)
+	require.Equal("delegate(uint256)", call.Signature)
+	require.Equal((*hexutil.Big)(big.NewInt(5)), call.Args["toValidatorID"])
+	require.Nil(decodeCall(contract, []byte{1, 2, 3, 4}))
+	require.Nil(decodeCall(unknown, input))
+
+	// log
+	event := contract.Events["Delegated"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(100))
+	require.NoError(err)
+	delegator := common.Address{2}
+	l := &types.Log{
+		Address: sfc.ContractAddress,
+		Topics:  []common.Hash{event.ID, delegator.Hash(), common.BigToHash(big.NewInt(5))},
+		Data:    data,
+	}
+	decoded := decodeLog(contract, l)
+	require.NotNil(decoded)
+	require.Equal("Delegated", decoded.Event)
+	require.Equal(delegator, decoded.Args["delegator"])
+	require.Equal((*hexutil.Big)(big.NewInt(5)), decoded.Args["toValidatorID"])
+	require.Equal((*hexutil.Big)(big.NewInt(100)), decoded.Args["amount"])
+	l.Topics[0] = common.Hash{}
+	require.Nil(decodeLog(contract, l))
+}
+
+func TestFormatABIValue(t *testing.T) {
+	require := require.New(t)
+
+	parsed, err := abi.JSON(strings.NewReader(`[{"name":"f","type":"function","inputs":[{"name":"a","type":"bytes32"},{"name":"b","type":"uint16[]"},{"name":"c","type":"bytes"}]}]`))
+	require.NoError(err)
+	input, err := parsed.Pack("f", [32]byte{1}, []uint16{1, 2}, []byte{3})
+	require.NoError(err)
+	call := decodeCall(&parsed, input)
+	require.NotNil(call)
+	require.Equal(hexutil.Bytes(common.Hash{1}.Bytes()), call.Args["a"])
+	require.Equal([]interface{}{hexutil.Uint64(1), hexutil.Uint64(2)}, call.Args["b"])
+	require.Equal(hexutil.Bytes{3}, call.Args["c"])
+}
@@ -0,0 +1,163 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/evmstore"
+)
+
+const (
+	defaultAddressTxsPageSize = 100
+	maxAddressTxsPageSize     = 1000
+)
+
+var errAddressIndexDisabled = errors.New("address index is disabled (enable it with --index.addresses)")
+
+// AddressTxsCriteria selects txs, which touched the address
+type AddressTxsCriteria struct {
+	Address common.Address `json:"address"`
+	// Direction is "from", "to" or "any" (default)
+	Direction string           `json:"direction"`
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+}
+
+// AddressTxsCursor is a position of the first tx of the next page
+type AddressTxsCursor struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+}
+
+// AddressTx is a tx, which touched the address
+type AddressTx struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint   `json:"transactionIndex"`
+	Hash             common.Hash    `json:"hash"`
+	// Directions is a subset of "from", "to", "transferFrom" and "transferTo"
+	Directions []string `json:"directions"`
+}
+
+// AddressTxsPage is a page of txs, which touched the address. Next is nil on the last page.
+type AddressTxsPage struct {
+	Transactions []*AddressTx      `json:"transactions"`
+	Next         *AddressTxsCursor `json:"next"`
+}
+
+var addressTxDirections = []struct {
+	flag evmstore.AddressTxFlags
+	name string
+}{
+	{evmstore.AddressTxFrom, "from"},
+	{evmstore.AddressTxTo, "to"},
+	{evmstore.AddressTxTransferFrom, "transferFrom"},
+	{evmstore.AddressTxTransferTo, "transferTo"},
+}
+
+func addressTxDirectionFlags(direction string) (evmstore.AddressTxFlags, error) {
+	switch direction {
+	case "", "any":
+		return evmstore.AddressTxFrom | evmstore.AddressTxTo | evmstore.AddressTxTransferFrom | evmstore.AddressTxTransferTo, nil
+	case "from":
+		return evmstore.AddressTxFrom | evmstore.AddressTxTransferFrom, nil
+	case "to":
+		return evmstore.AddressTxTo | evmstore.AddressTxTransferTo, nil
+	}
+	return 0, fmt.Errorf("unknown direction %q, expected from, to or any", direction)
+}
+
+// PublicAddressTxsAPI provides an API to access txs by address.
+type PublicAddressTxsAPI struct {
+	s *Service
+}
+
+// NewPublicAddressTxsAPI creates a new address txs API.
+func NewPublicAddressTxsAPI(s *Service) *PublicAddressTxsAPI {
+	return &PublicAddressTxsAPI{s}
+}
+
+func (api *PublicAddressTxsAPI) blockNumber(n *rpc.BlockNumber, def idx.Block) idx.Block {
+	if n == nil {
+		return def
+	}
+	if *n < 0 {
+		// latest and pending
+		return api.s.store.GetLatestBlockIndex()
+	}
+	return idx.Block(*n)
+}
+
+// GetTransactionsByAddress returns a page of txs, which touched the address, in the execution order.
+// Pass the returned Next cursor to get the next page.
+func (api *PublicAddressTxsAPI) GetTransactionsByAddress(ctx context.Context, crit AddressTxsCriteria, cursor *AddressTxsCursor, limit *hexutil.Uint) (*AddressTxsPage, error) {
+	if !api.s.config.AddressIndex {
+		return nil, errAddressIndexDisabled
+	}
+	directions, err := addressTxDirectionFlags(crit.Direction)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := defaultAddressTxsPageSize
+	if limit != nil {
+		pageSize = int(*limit)
+		if pageSize <= 0 || pageSize > maxAddressTxsPageSize {
+			return nil, fmt.Errorf("limit must be in range [1, %d]", maxAddressTxsPageSize)
+		}
+	}
+
+	from := api.blockNumber(crit.FromBlock, 0)
+	to := api.blockNumber(crit.ToBlock, api.s.store.GetLatestBlockIndex())
+	var fromOffset uint32
+	if cursor != nil && idx.Block(cursor.BlockNumber) >= from {
+		from = idx.Block(cursor.BlockNumber)
+		fromOffset = uint32(cursor.TransactionIndex)
+	}
+	if start := api.s.store.GetHistoryStart(); from < start {
+		from, fromOffset = start, 0
+	}
+
+	page := &AddressTxsPage{
+		Transactions: make([]*AddressTx, 0, pageSize),
+	}
+	api.s.store.evm.ForEachAddressTx(crit.Address, from, fromOffset, func(tx evmstore.AddressTx) bool {
+		if tx.Block > to {
+			return false
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			return false
+		}
+		if tx.Flags&directions == 0 {
+			return true
+		}
+		if len(page.Transactions) == pageSize {
+			page.Next = &AddressTxsCursor{
+				BlockNumber:      hexutil.Uint64(tx.Block),
+				TransactionIndex: hexutil.Uint(tx.BlockOffset),
+			}
+			return false
+		}
+		res := &AddressTx{
+			BlockNumber:      hexutil.Uint64(tx.Block),
+			TransactionIndex: hexutil.Uint(tx.BlockOffset),
+			Hash:             tx.TxHash,
+		}
+		for _, d := range addressTxDirections {
+			if tx.Flags&d.flag != 0 {
+				res.Directions = append(res.Directions, d.name)
+			}
+		}
+		page.Transactions = append(page.Transactions, res)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
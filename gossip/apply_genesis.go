@@ -182,6 +182,7 @@ func (s *Store) applyEpoch1Genesis(blockProc BlockProc, g skyhigh.Genesis) (err
 
 	bs.LastBlock = blockCtx
 	s.SetBlockEpochState(bs, es)
+	s.SetHistoryEpochState(es)
 
 	prettyHash := func(root common.Hash, g skyhigh.Genesis) hash.Event {
 		e := inter.MutableEventPayload{}
@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/skyhighblockchain/skyhigh/evmcore"
@@ -16,12 +17,23 @@ import (
 	"github.com/skyhighblockchain/skyhigh/utils"
 )
 
-type EVMModule struct{}
+type EVMModule struct {
+	// workers is a number of goroutines for a speculative parallel execution of transactions, 0 or 1 disables it
+	workers int
+}
 
 func New() *EVMModule {
 	return &EVMModule{}
 }
 
+// NewParallel returns EVM module, which executes transactions of a block speculatively in parallel.
+// The result is identical to the sequential execution.
+func NewParallel(workers int) *EVMModule {
+	return &EVMModule{
+		workers: workers,
+	}
+}
+
 func (p *EVMModule) Start(block blockproc.BlockCtx, statedb *state.StateDB, reader evmcore.DummyChain, onNewLog func(*types.Log), net skyhigh.Rules) blockproc.EVMProcessor {
 	var prevBlockHash common.Hash
 	if block.Idx != 0 {
@@ -33,6 +45,7 @@ func (p *EVMModule) Start(block blockproc.BlockCtx, statedb *state.StateDB, read
 		statedb:       statedb,
 		onNewLog:      onNewLog,
 		net:           net,
+		workers:       p.workers,
 		blockIdx:      utils.U64toBig(uint64(block.Idx)),
 		prevBlockHash: prevBlockHash,
 	}
@@ -44,6 +57,7 @@ type SkyhighEVMProcessor struct {
 	statedb  *state.StateDB
 	onNewLog func(*types.Log)
 	net      skyhigh.Rules
+	workers  int
 
 	blockIdx      *big.Int
 	prevBlockHash common.Hash
@@ -70,8 +84,19 @@ func (p *SkyhighEVMProcessor) evmBlockWith(txs types.Transactions) *evmcore.EvmB
 	return evmcore.NewEvmBlock(h, txs)
 }
 
+type stateProcessor interface {
+	Process(block *evmcore.EvmBlock, statedb *state.StateDB, cfg vm.Config, usedGas *uint64, internal bool, onNewLog func(*types.Log, *state.StateDB)) (types.Receipts, []*types.Log, []uint32, error)
+}
+
+func (p *SkyhighEVMProcessor) stateProcessor() stateProcessor {
+	if p.workers > 1 {
+		return evmcore.NewParallelStateProcessor(p.net.EvmChainConfig(), p.reader, p.workers)
+	}
+	return evmcore.NewStateProcessor(p.net.EvmChainConfig(), p.reader)
+}
+
 func (p *SkyhighEVMProcessor) Execute(txs types.Transactions, internal bool) types.Receipts {
-	evmProcessor := evmcore.NewStateProcessor(p.net.EvmChainConfig(), p.reader)
+	evmProcessor := p.stateProcessor()
 
 	// Process txs
 	evmBlock := p.evmBlockWith(txs)
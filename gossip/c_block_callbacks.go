@@ -42,9 +42,11 @@ func (s *Service) GetConsensusCallbacks() push.ConsensusCallbacks {
 			s.store,
 			s.blockProcModules,
 			s.config.TxIndex,
+			s.config.AddressIndex,
 			&s.feed,
-			s.emitter,
+			s.emitters,
 			s.verWatcher,
+			s.prefetcher,
 			nil,
 		),
 	}
@@ -60,9 +62,11 @@ func consensusCallbackBeginBlockFn(
 	store *Store,
 	blockProc BlockProc,
 	txIndex bool,
+	addressIndex bool,
 	feed *ServiceFeed,
-	emitter *emitter.Emitter,
+	emitters []*emitter.Emitter,
 	verWatcher *verwatcher.VerWarcher,
+	prefetcher *eventsPrefetcher,
 	onBlockEnd func(block *inter.Block, preInternalReceipts, internalReceipts, externalReceipts types.Receipts),
 ) push.BeginBlockFn {
 	return func(cBlock *push.Block) push.BlockCallbacks {
@@ -102,8 +106,8 @@ func consensusCallbackBeginBlockFn(
 					confirmedEvents = append(confirmedEvents, e.ID())
 				}
 				eventProcessor.ProcessConfirmedEvent(e)
-				if emitter != nil {
-					emitter.OnEventConfirmed(e)
+				for _, em := range emitters {
+					em.OnEventConfirmed(e)
 				}
 			},
 			EndBlock: func() (newValidators *pos.Validators) {
@@ -167,6 +171,7 @@ func consensusCallbackBeginBlockFn(
 					sealer.Update(bs, es)
 					bs, es = sealer.SealEpoch() // TODO: refactor to not mutate the bs, it is unclear
 					store.SetBlockEpochState(bs, es)
+					store.SetHistoryEpochState(es)
 					newValidators = es.Validators
 					txListener.Update(bs, es)
 				}
@@ -201,7 +206,11 @@ func consensusCallbackBeginBlockFn(
 						txs = append(txs, e.Txs()...)
 					}
 
+					executionStart := time.Now()
 					externalReceipts := evmProcessor.Execute(txs, false)
+					if prefetcher != nil {
+						prefetcher.OnBlockExecuted(txs, time.Since(executionStart))
+					}
 					evmBlock, skippedTxs, allReceipts := evmProcessor.Finalize()
 
 					block.SkippedTxs = skippedTxs
@@ -261,6 +270,9 @@ func consensusCallbackBeginBlockFn(
 							}
 						}
 					}
+					if addressIndex {
+						store.IndexAddressTxs(blockCtx.Idx, evmBlock.Transactions, allReceipts)
+					}
 					for _, tx := range append(preInternalTxs, internalTxs...) {
 						store.evm.SetTx(tx.Hash(), tx)
 					}
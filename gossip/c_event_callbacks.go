@@ -168,7 +168,10 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 		s.store.SetHighestLamport(e.Lamport())
 	}
 
-	s.emitter.OnEventConnected(e)
+	for _, em := range s.emitters {
+		em.OnEventConnected(e)
+	}
+	s.prefetcher.Prefetch(e)
 
 	if newEpoch != oldEpoch {
 		// reset dag indexer
@@ -181,7 +184,9 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 		s.gasPowerCheckReader.Ctx.Store(NewGasPowerContext(s.store, s.store.GetValidators(), newEpoch, s.store.GetRules().Economy)) // read gaspower check data from disk
 		s.heavyCheckReader.Addrs.Store(NewEpochPubKeys(s.store, newEpoch))
 		// notify about new epoch
-		s.emitter.OnNewEpoch(s.store.GetValidators(), newEpoch)
+		for _, em := range s.emitters {
+			em.OnNewEpoch(s.store.GetValidators(), newEpoch)
+		}
 		s.feed.newEpoch.Send(newEpoch)
 	}
 
@@ -122,7 +122,10 @@ func (env *testEnv) GetEvmStateReader() *EvmStateReader {
 func (env *testEnv) consensusCallbackBeginBlockFn(
 	onBlockEnd func(block *inter.Block, preInternalReceipts, internalReceipts, externalReceipts types.Receipts),
 ) push.BeginBlockFn {
-	const txIndex = true
+	const (
+		txIndex      = true
+		addressIndex = true
+	)
 	callback := consensusCallbackBeginBlockFn(
 		env.blockProcTasks,
 		&env.blockProcWg,
@@ -130,6 +133,8 @@ func (env *testEnv) consensusCallbackBeginBlockFn(
 		env.store,
 		env.blockProcModules,
 		txIndex,
+		addressIndex,
+		nil,
 		nil,
 		nil,
 		nil,
@@ -60,7 +60,8 @@ type (
 
 		FilterAPI filters.Config
 
-		TxIndex bool // Whether to enable indexing transactions and receipts or not
+		TxIndex      bool // Whether to enable indexing transactions and receipts or not
+		AddressIndex bool // Whether to enable indexing transactions by address or not
 
 		// Protocol options
 		Protocol ProtocolConfig
@@ -79,12 +80,60 @@ type (
 		// send-transction variants. The unit is ether.
 		RPCTxFeeCap float64 `toml:",omitempty"`
 
+		// Prefetch is a config of the speculative execution of connected events
+		Prefetch PrefetchConfig
+
+		// EVMWorkers is a number of goroutines for a speculative parallel execution of block transactions.
+		// 0 or 1 means sequential execution.
+		EVMWorkers int
+
 		// allows only for EIP155 transactions.
 		AllowUnprotectedTxs bool
 
 		ExtRPCEnabled bool
 
 		RPCLogsBloom bool
+
+		// DevValidators are validators, which emit events in this process in development mode.
+		// Emitter config is ignored if it isn't empty
+		DevValidators []emitter.ValidatorConfig `toml:"-"`
+	}
+
+	// PrefetchConfig is a config of the speculative execution of connected events,
+	// which warms up EVM state caches before the events get confirmed.
+	PrefetchConfig struct {
+		// Workers is a number of prefetching goroutines, 0 disables prefetching
+		Workers int
+		// MaxQueuedEvents is a number of events waiting for prefetching, newer events are dropped
+		MaxQueuedEvents int
+		// MaxTrackedTxs is a number of prefetched txs remembered to measure prefetching efficiency
+		MaxTrackedTxs int
+	}
+
+	// AncientStoreConfig is a config of the append-only flat-file store of old blocks, events, txs and receipts.
+	AncientStoreConfig struct {
+		// Dir is a directory of the ancient store, empty value disables the ancient store
+		Dir string
+		// Epochs is a number of recent epochs, which data is kept in the key-value DB. 0 disables the migration
+		Epochs idx.Epoch
+		// Period is a period of the background migration into the ancient store
+		Period time.Duration
+		// MaxBatch is a max number of blocks and events migrated at once
+		MaxBatch int
+	}
+
+	// HistoryConfig is a config of the history pruning, which deletes receipts, logs index,
+	// txs index and event payloads of old blocks. Blocks themselves are kept.
+	// If both Blocks and Epochs are set, history is kept if it's within any of the limits.
+	HistoryConfig struct {
+		// Blocks is a number of recent blocks, which history is kept. 0 means no limit
+		Blocks idx.Block
+		// Epochs is a number of recent epochs, which history is kept. 0 means no limit
+		Epochs idx.Epoch
+		// Period is a period of the background pruning
+		Period time.Duration
+		// MaxBatch is a max number of blocks and events pruned at once
+		MaxBatch int
 	}
 
 	StoreCacheConfig struct {
@@ -100,7 +149,11 @@ type (
 	StoreConfig struct {
 		Cache StoreCacheConfig
 		// EVM is EVM store config
-		EVM                 evmstore.StoreConfig
+		EVM evmstore.StoreConfig
+		// Ancient is a config of the ancient store
+		Ancient AncientStoreConfig
+		// History is a config of the history pruning
+		History             HistoryConfig
 		MaxNonFlushedSize   int
 		MaxNonFlushedPeriod time.Duration
 	}
@@ -128,6 +181,12 @@ func DefaultConfig(scale cachescale.Func) Config {
 
 		HeavyCheck: heavycheck.DefaultConfig(),
 
+		Prefetch: PrefetchConfig{
+			Workers:         1,
+			MaxQueuedEvents: 256,
+			MaxTrackedTxs:   scale.I(20000),
+		},
+
 		Protocol: ProtocolConfig{
 			LatencyImportance:    60,
 			ThroughputImportance: 40,
@@ -220,6 +279,15 @@ func (c *Config) Validate() error {
 	if c.Protocol.Processor.EventsBufferLimit.Size < protocolMaxMsgSize {
 		return fmt.Errorf("EventsBufferLimit.Size has to be at least %d", protocolMaxMsgSize)
 	}
+	if !emitter.HasStrategy(c.Emitter.Strategy) {
+		return fmt.Errorf("unknown emitter strategy %s", c.Emitter.Strategy)
+	}
+	if c.Prefetch.Workers > 0 && (c.Prefetch.MaxQueuedEvents <= 0 || c.Prefetch.MaxTrackedTxs <= 0) {
+		return fmt.Errorf("Prefetch.MaxQueuedEvents and Prefetch.MaxTrackedTxs have to be positive")
+	}
+	if c.EVMWorkers < 0 {
+		return fmt.Errorf("EVMWorkers has to be non-negative")
+	}
 
 	return nil
 }
@@ -231,6 +299,14 @@ func FakeConfig(num int, scale cachescale.Func) Config {
 	return cfg
 }
 
+// DevConfig returns the configurations for the gossip service in development mode,
+// where all the validators emit events in one process.
+func DevConfig(validators []emitter.ValidatorConfig, scale cachescale.Func) Config {
+	cfg := DefaultConfig(scale)
+	cfg.DevValidators = validators
+	return cfg
+}
+
 // DefaultStoreConfig for product.
 func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 	return StoreConfig{
@@ -240,7 +316,15 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			BlocksNum:  scale.I(5000),
 			BlocksSize: scale.U(512 * opt.KiB),
 		},
-		EVM:                 evmstore.DefaultStoreConfig(scale),
+		EVM: evmstore.DefaultStoreConfig(scale),
+		Ancient: AncientStoreConfig{
+			Period:   time.Minute,
+			MaxBatch: 1000,
+		},
+		History: HistoryConfig{
+			Period:   time.Minute,
+			MaxBatch: 1000,
+		},
 		MaxNonFlushedSize:   17*opt.MiB + scale.I(5*opt.MiB),
 		MaxNonFlushedPeriod: 30 * time.Minute,
 	}
@@ -255,7 +339,15 @@ func LiteStoreConfig() StoreConfig {
 			BlocksNum:  100,
 			BlocksSize: 50 * opt.KiB,
 		},
-		EVM:                 evmstore.LiteStoreConfig(),
+		EVM: evmstore.LiteStoreConfig(),
+		Ancient: AncientStoreConfig{
+			Period:   time.Minute,
+			MaxBatch: 100,
+		},
+		History: HistoryConfig{
+			Period:   time.Minute,
+			MaxBatch: 100,
+		},
 		MaxNonFlushedSize:   800 * opt.KiB,
 		MaxNonFlushedPeriod: 30 * time.Minute,
 	}
@@ -0,0 +1,108 @@
+package gossip
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/skyhighblockchain/skyhigh/utils"
+)
+
+// ConfigReload is a result of the config reloading
+type ConfigReload struct {
+	// Applied are the changed fields, which are applied to the running node
+	Applied []string `json:"applied"`
+	// RestartRequired are the changed fields, which take effect only after a restart
+	RestartRequired []string `json:"restartRequired"`
+}
+
+// hotReloadable are the config fields (or sections of fields), which are applied by ReloadConfig without a restart
+var hotReloadable = []string{
+	"Emitter.EmitIntervals",
+	"Emitter.MaxTxsPerAddress",
+	"Emitter.MaxParents",
+	"Emitter.LimitedTpsThreshold",
+	"Emitter.NoTxsThreshold",
+	"Emitter.EmergencyThreshold",
+	"Emitter.TxsCacheInvalidation",
+	"TxPool.PriceLimit",
+	"TxPool.PriceBump",
+	"TxPool.AccountSlots",
+	"TxPool.GlobalSlots",
+	"TxPool.AccountQueue",
+	"TxPool.GlobalQueue",
+	"TxPool.Lifetime",
+	"GPO",
+}
+
+func hasField(sections []string, field string) bool {
+	for _, section := range sections {
+		if field == section || strings.HasPrefix(field, section+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// ReloadConfig validates the new config and applies its hot-reloadable subsets to the running emitter, txpool and GPO.
+// Changes of the other fields are reported as requiring a restart, until the node is restarted.
+func (s *Service) ReloadConfig(config Config) (ConfigReload, error) {
+	var res ConfigReload
+	if err := config.Validate(); err != nil {
+		return res, err
+	}
+	if config.TxPool.Journal != "" && s.resolvePath != nil {
+		config.TxPool.Journal = s.resolvePath(config.TxPool.Journal)
+	}
+
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	running := s.runningConfig()
+	for _, field := range utils.ConfigDiff(running, config) {
+		if hasField(hotReloadable, field) {
+			res.Applied = append(res.Applied, field)
+		} else {
+			res.RestartRequired = append(res.RestartRequired, field)
+		}
+	}
+
+	// memorize only the applied fields, so the rest is compared with the running config on the next reload
+	dst, src := reflect.ValueOf(&running).Elem(), reflect.ValueOf(config)
+	for _, field := range res.Applied {
+		d, v := dst, src
+		for _, name := range strings.Split(field, ".") {
+			d, v = d.FieldByName(name), v.FieldByName(name)
+		}
+		d.Set(v)
+	}
+	s.running.Store(running)
+
+	// emitters of the development mode ignore the emitter config
+	if sectionChanged(res.Applied, "Emitter") && s.devGroup == nil {
+		for _, em := range s.emitters {
+			em.SetConfig(running.Emitter)
+		}
+	}
+	if sectionChanged(res.Applied, "TxPool") {
+		s.txpool.SetConfig(running.TxPool)
+	}
+	if sectionChanged(res.Applied, "GPO") {
+		s.gpo.SetConfig(running.GPO)
+	}
+	return res, nil
+}
+
+// runningConfig returns the config with the applied reloads.
+// The reloadable sub-configs must be read from it rather than from s.config, which keeps the startup config.
+func (s *Service) runningConfig() Config {
+	return s.running.Load().(Config)
+}
+
+func sectionChanged(fields []string, section string) bool {
+	for _, field := range fields {
+		if hasField([]string{section}, field) {
+			return true
+		}
+	}
+	return false
+}
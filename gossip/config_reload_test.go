@@ -0,0 +1,57 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ReloadConfig(t *testing.T) {
+	require := require.New(t)
+
+	sim := newSimulation(t, defaultSimConfig(1))
+	defer sim.Close()
+	svc := sim.nodes[0].svc
+
+	// nothing is changed
+	res, err := svc.ReloadConfig(svc.config)
+	require.NoError(err)
+	require.Empty(res.Applied)
+	require.Empty(res.RestartRequired)
+
+	config := svc.config
+	config.Emitter.NoTxsThreshold *= 2
+	config.Emitter.EmitIntervals.Min *= 2
+	config.Emitter.Validator.ID = 5
+	config.TxPool.GlobalSlots /= 2
+	config.TxPool.Rejournal *= 2
+	config.GPO.MaxPrice = big.NewInt(1e9)
+	config.TxIndex = !config.TxIndex
+	res, err = svc.ReloadConfig(config)
+	require.NoError(err)
+	require.Equal([]string{"Emitter.EmitIntervals.Min", "Emitter.NoTxsThreshold", "TxPool.GlobalSlots", "GPO.MaxPrice"}, res.Applied)
+	require.Equal([]string{"Emitter.Validator.ID", "TxPool.Rejournal", "TxIndex"}, res.RestartRequired)
+
+	// only the applied fields are memorized, the startup config isn't changed
+	running := svc.runningConfig()
+	require.Equal(config.Emitter.NoTxsThreshold, running.Emitter.NoTxsThreshold)
+	require.Equal(config.TxPool.GlobalSlots, running.TxPool.GlobalSlots)
+	require.Equal(config.GPO.MaxPrice, running.GPO.MaxPrice)
+	require.NotEqual(config.TxIndex, running.TxIndex)
+	require.NotEqual(config.Emitter.NoTxsThreshold, svc.config.Emitter.NoTxsThreshold)
+
+	// the fields requiring a restart are reported until the restart
+	res, err = svc.ReloadConfig(config)
+	require.NoError(err)
+	require.Empty(res.Applied)
+	require.Equal([]string{"Emitter.Validator.ID", "TxPool.Rejournal", "TxIndex"}, res.RestartRequired)
+
+	// invalid config isn't applied
+	invalid := config
+	invalid.Emitter.Strategy = "unknown"
+	invalid.Emitter.NoTxsThreshold *= 2
+	_, err = svc.ReloadConfig(invalid)
+	require.Error(err)
+	require.Equal(config.Emitter.NoTxsThreshold, svc.runningConfig().Emitter.NoTxsThreshold)
+}
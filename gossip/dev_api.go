@@ -0,0 +1,80 @@
+package gossip
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/evmcore"
+)
+
+var errTimeInPast = errors.New("timestamp is before the current time of the network")
+
+// DevUint64 is a number, which is accepted both as a JSON number and as a hex string,
+// for compatibility with the tools, which use evm_increaseTime/evm_mine.
+type DevUint64 uint64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *DevUint64) UnmarshalJSON(input []byte) error {
+	if len(input) != 0 && input[0] == '"' {
+		return (*hexutil.Uint64)(v).UnmarshalJSON(input)
+	}
+	var n uint64
+	if err := json.Unmarshal(input, &n); err != nil {
+		return err
+	}
+	*v = DevUint64(n)
+	return nil
+}
+
+// PublicDevAPI provides evm_increaseTime/evm_mine style methods for tests in development mode.
+type PublicDevAPI struct {
+	s *Service
+}
+
+// NewPublicDevAPI creates a new development mode API.
+func NewPublicDevAPI(s *Service) *PublicDevAPI {
+	return &PublicDevAPI{s}
+}
+
+// IncreaseTime warps time of next blocks into future, and returns the total warp in seconds.
+func (api *PublicDevAPI) IncreaseTime(seconds DevUint64) hexutil.Uint64 {
+	offset := api.s.devGroup.IncreaseTime(time.Duration(seconds) * time.Second)
+	return hexutil.Uint64(offset / time.Second)
+}
+
+// Mine produces a new block, even if there are no transactions, and returns its number.
+// If timestamp (in unix seconds) is specified, time is warped to it before the block is produced.
+func (api *PublicDevAPI) Mine(ctx context.Context, timestamp *DevUint64) (hexutil.Uint64, error) {
+	if timestamp != nil {
+		target := time.Unix(int64(*timestamp), 0)
+		now := api.s.devGroup.Now()
+		if target.Before(now) {
+			return 0, errTimeInPast
+		}
+		api.s.devGroup.IncreaseTime(target.Sub(now))
+	}
+
+	blocks := make(chan evmcore.ChainHeadNotify, 16)
+	sub := api.s.feed.SubscribeNewBlock(blocks)
+	defer sub.Unsubscribe()
+
+	target := api.s.store.GetLatestBlockIndex() + 1
+	api.s.devGroup.RequestBlock(target)
+	for {
+		select {
+		case b := <-blocks:
+			if idx.Block(b.Block.NumberU64()) >= target {
+				return hexutil.Uint64(b.Block.NumberU64()), nil
+			}
+		case err := <-sub.Err():
+			return 0, err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
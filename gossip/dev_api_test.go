@@ -0,0 +1,24 @@
+package gossip
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevUint64_UnmarshalJSON(t *testing.T) {
+	for input, expected := range map[string]DevUint64{
+		`3600`:    3600,
+		`"0xe10"`: 3600,
+		`0`:       0,
+	} {
+		var v DevUint64
+		require.NoError(t, json.Unmarshal([]byte(input), &v), input)
+		require.Equal(t, expected, v, input)
+	}
+	for _, input := range []string{`-1`, `"3600"`, `1.5`, `true`} {
+		var v DevUint64
+		require.Error(t, json.Unmarshal([]byte(input), &v), input)
+	}
+}
@@ -20,6 +20,14 @@ type EmitIntervals struct {
 	DoublesignProtection       time.Duration
 }
 
+// AdaptiveLatencyConfig is the configuration of the adaptive-latency strategy.
+type AdaptiveLatencyConfig struct {
+	// ConfirmationEvents is a desired number of self-events emitted during the observed confirmation latency
+	ConfirmationEvents uint64
+	// MaxSlowdown is a max factor by which Min and Confirming emit intervals may be increased
+	MaxSlowdown uint64
+}
+
 type ValidatorConfig struct {
 	ID     idx.ValidatorID
 	PubKey validatorpk.PubKey
@@ -38,6 +46,10 @@ type Config struct {
 
 	EmitIntervals EmitIntervals // event emission intervals
 
+	// Strategy is a name of the parents selection and emission timing strategy
+	Strategy        string
+	AdaptiveLatency AdaptiveLatencyConfig
+
 	MaxTxsPerAddress int
 
 	MaxParents idx.Event
@@ -50,6 +62,11 @@ type Config struct {
 	TxsCacheInvalidation time.Duration
 
 	PrevEmittedEventFile PrevEmittedEventFile
+
+	// MaintenanceFile persists the paused state of the emission across restarts, see Emitter.Pause
+	MaintenanceFile string
+	// KeyRotationFile persists the scheduled switch of the signing key across restarts, see Emitter.RotateKey
+	KeyRotationFile string
 }
 
 // DefaultConfig returns the default configurations for the events emitter.
@@ -65,6 +82,12 @@ func DefaultConfig() Config {
 			ParallelInstanceProtection: 1 * time.Minute,
 		},
 
+		Strategy: DefaultStrategy,
+		AdaptiveLatency: AdaptiveLatencyConfig{
+			ConfirmationEvents: 8,
+			MaxSlowdown:        5,
+		},
+
 		MaxTxsPerAddress: TxTurnNonces,
 
 		MaxParents: 0,
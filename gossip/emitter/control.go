@@ -38,7 +38,7 @@ func eventMetric(orig ancestor.Metric, seq idx.Event) ancestor.Metric {
 	return metric
 }
 
-func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool {
+func (em *Emitter) isAllowedToEmit(intervals EmitIntervals, e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool {
 	passedTime := e.CreationTime().Time().Sub(em.prevEmittedAtTime)
 	passedTimeIdle := e.CreationTime().Time().Sub(em.prevIdleTime)
 	if em.stakeRatio[e.Creator()] < 0.35*piecefunc.DecimalUnit {
@@ -64,6 +64,7 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 					"power", e.GasPowerLeft().String(),
 					"selfParentPower", selfParent.GasPowerLeft().String(),
 					"stake%", 100*float64(em.validators.Get(e.Creator()))/float64(em.validators.TotalWeight()))
+				em.stats.Emergency++
 				return false
 			}
 		}
@@ -75,7 +76,7 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 		if rules.Economy.BlockMissedSlack > maxBlocks && maxBlocks < rules.Economy.BlockMissedSlack-5 {
 			maxBlocks = rules.Economy.BlockMissedSlack - 5
 		}
-		if passedTime >= em.intervals.Max ||
+		if passedTime >= intervals.Max ||
 			passedBlocks >= maxBlocks*4/5 && metric >= piecefunc.DecimalUnit/2 ||
 			passedBlocks >= maxBlocks {
 			return true
@@ -86,8 +87,8 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 		threshold := (em.config.NoTxsThreshold + em.config.EmergencyThreshold) / 2
 		if e.GasPowerLeft().Min() <= threshold {
 			// it's emitter, so no need in determinism => fine to use float
-			minT := float64(em.intervals.Min)
-			maxT := float64(em.intervals.Max)
+			minT := float64(intervals.Min)
+			maxT := float64(intervals.Max)
 			factor := float64(e.GasPowerLeft().Min()) / float64(threshold)
 			adjustedEmitInterval := time.Duration(maxT - (maxT-minT)*factor)
 			if passedTime < adjustedEmitInterval {
@@ -97,7 +98,7 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 	}
 	// Slow down emitting if no txs to confirm/originate
 	{
-		if passedTime < em.intervals.Max &&
+		if passedTime < intervals.Max &&
 			em.idle() &&
 			!eTxs {
 			return false
@@ -105,14 +106,14 @@ func (em *Emitter) isAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Me
 	}
 	// Emitting is controlled by the efficiency metric
 	{
-		if passedTime < em.intervals.Min {
+		if passedTime < intervals.Min {
 			return false
 		}
-		if adjustedPassedTime < em.intervals.Min &&
+		if adjustedPassedTime < intervals.Min &&
 			!em.idle() {
 			return false
 		}
-		if adjustedPassedIdleTime < em.intervals.Confirming &&
+		if adjustedPassedIdleTime < intervals.Confirming &&
 			!em.idle() &&
 			!eTxs {
 			return false
@@ -126,6 +127,6 @@ func (em *Emitter) recheckIdleTime() {
 	em.world.Lock()
 	defer em.world.Unlock()
 	if em.idle() {
-		em.prevIdleTime = time.Now()
+		em.prevIdleTime = em.clock()
 	}
 }
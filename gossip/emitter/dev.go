@@ -0,0 +1,98 @@
+package emitter
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/skyhighblockchain/push-base/emitter/ancestor"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// DevGroup coordinates emitters of all the validators of a development network, which run in one process.
+// The emitters emit events as soon as there are transactions to originate or confirm, or a block is requested.
+// The clock of the emitters may be warped into future, which moves time of next blocks.
+type DevGroup struct {
+	offset    int64  // atomic, clock offset in nanoseconds
+	mineUntil uint64 // atomic, index of the requested block
+}
+
+// NewDevGroup makes an emitters group for development mode.
+func NewDevGroup() *DevGroup {
+	return &DevGroup{}
+}
+
+// DevConfig returns the emitter config for development mode.
+func DevConfig(validator ValidatorConfig) Config {
+	cfg := DefaultConfig()
+	cfg.Validator = validator
+	// emission timing is controlled by the dev strategy
+	cfg.EmitIntervals.Min = 0
+	cfg.EmitIntervals.Confirming = 0
+	// all the validators are in one process
+	cfg.EmitIntervals.DoublesignProtection = 0
+	cfg.EmitIntervals.ParallelInstanceProtection = 0
+	cfg.PrevEmittedEventFile = PrevEmittedEventFile{}
+	return cfg
+}
+
+// NewEmitter makes an emitter, which belongs to the group.
+func (g *DevGroup) NewEmitter(config Config, world World) *Emitter {
+	em := NewEmitter(config, world)
+	em.clock = g.Now
+	em.strategy = &devStrategy{
+		Strategy: NewDefaultStrategy(em),
+		em:       em,
+		group:    g,
+	}
+	return em
+}
+
+// Now returns the warped time.
+func (g *DevGroup) Now() time.Time {
+	return time.Now().Add(g.Offset())
+}
+
+// Offset returns the total clock offset.
+func (g *DevGroup) Offset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&g.offset))
+}
+
+// IncreaseTime warps the clock into future, and returns the total clock offset.
+func (g *DevGroup) IncreaseTime(d time.Duration) time.Duration {
+	if d <= 0 {
+		return g.Offset()
+	}
+	return time.Duration(atomic.AddInt64(&g.offset, int64(d)))
+}
+
+// RequestBlock makes emitters to emit events until the block is produced, even if there are no transactions.
+func (g *DevGroup) RequestBlock(n idx.Block) {
+	for {
+		prev := atomic.LoadUint64(&g.mineUntil)
+		if uint64(n) <= prev || atomic.CompareAndSwapUint64(&g.mineUntil, prev, uint64(n)) {
+			return
+		}
+	}
+}
+
+func (g *DevGroup) isBlockRequested(latest idx.Block) bool {
+	return uint64(latest) < atomic.LoadUint64(&g.mineUntil)
+}
+
+// devStrategy emits events without delays when there's something to confirm, and doesn't emit otherwise.
+// Parents selection is delegated to the default strategy.
+type devStrategy struct {
+	Strategy
+	em    *Emitter
+	group *DevGroup
+}
+
+func (s *devStrategy) IsAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool {
+	// eTxs is an optimistic guess before txs are added, skip building of events if there's nothing to add
+	if eTxs && s.em.world.TxPool.Count() != 0 {
+		return true
+	}
+	return !s.em.idle() || s.group.isBlockRequested(s.em.world.GetLatestBlockIndex())
+}
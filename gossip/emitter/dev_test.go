@@ -0,0 +1,25 @@
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevGroup(t *testing.T) {
+	require := require.New(t)
+	g := NewDevGroup()
+
+	require.Equal(time.Duration(0), g.Offset())
+	require.Equal(time.Hour, g.IncreaseTime(time.Hour))
+	require.Equal(time.Hour, g.IncreaseTime(-time.Minute))
+	require.Equal(time.Hour+time.Second, g.IncreaseTime(time.Second))
+	require.True(g.Now().After(time.Now().Add(time.Hour)))
+
+	require.False(g.isBlockRequested(0))
+	g.RequestBlock(5)
+	g.RequestBlock(3)
+	require.True(g.isBlockRequested(4))
+	require.False(g.isBlockRequested(5))
+}
@@ -66,6 +66,19 @@ type Emitter struct {
 
 	intervals EmitIntervals
 
+	strategy Strategy
+	// manual emitter doesn't emit events by itself, see NewManualEmitter
+	manual bool
+
+	stats Stats
+
+	maintenance maintenance
+
+	keyRotation keyRotation
+
+	// clock is a source of time, which may be warped in development mode or virtual in simulations
+	clock func() time.Time
+
 	done chan struct{}
 	wg   sync.WaitGroup
 
@@ -95,21 +108,24 @@ func NewEmitter(
 	config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(r)
 
 	txTime, _ := lru.New(TxTimeBufferSize)
-	return &Emitter{
+	em := &Emitter{
 		config:        config,
 		world:         world,
 		originatedTxs: originatedtxs.New(SenderCountBufferSize),
 		txTime:        txTime,
 		intervals:     config.EmitIntervals,
+		clock:         time.Now,
 		Periodic:      logger.Periodic{Instance: logger.MakeInstance()},
 	}
+	em.strategy = em.makeStrategy()
+	return em
 }
 
 // init emitter without starting events emission
 func (em *Emitter) init() {
-	em.syncStatus.startup = time.Now()
-	em.syncStatus.lastConnected = time.Now()
-	em.syncStatus.p2pSynced = time.Now()
+	em.syncStatus.startup = em.clock()
+	em.syncStatus.lastConnected = em.clock()
+	em.syncStatus.p2pSynced = em.clock()
 	validators, epoch := em.world.GetEpochValidators()
 	em.OnNewEpoch(validators, epoch)
 
@@ -117,6 +133,8 @@ func (em *Emitter) init() {
 		em.emittedEventFile = openEventFile(em.config.PrevEmittedEventFile.Path, em.config.PrevEmittedEventFile.SyncMode)
 	}
 	em.busyRate = rate.NewGauge()
+	em.loadMaintenanceFile()
+	em.loadKeyRotationFile()
 }
 
 // Start starts event emission.
@@ -130,6 +148,9 @@ func (em *Emitter) Start() {
 	}
 	em.init()
 	em.done = make(chan struct{})
+	if em.manual {
+		return
+	}
 
 	newTxsCh := make(chan evmcore.NewTxsNotify)
 	em.world.TxPool.SubscribeNewTxsNotify(newTxsCh)
@@ -167,15 +188,41 @@ func (em *Emitter) Stop() {
 	em.busyRate.Stop()
 }
 
-func (em *Emitter) tick() {
+// SetConfig applies the emit intervals, the txs limits and the gas power thresholds of the config to the running emitter.
+// The rest of the fields, such as the validator and the strategy, take effect only after a restart.
+func (em *Emitter) SetConfig(config Config) {
+	em.world.Lock()
+	defer em.world.Unlock()
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	em.config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(r)
+	em.config.MaxTxsPerAddress = config.MaxTxsPerAddress
+	em.config.MaxParents = config.MaxParents
+	em.config.LimitedTpsThreshold = config.LimitedTpsThreshold
+	em.config.NoTxsThreshold = config.NoTxsThreshold
+	em.config.EmergencyThreshold = config.EmergencyThreshold
+	em.config.TxsCacheInvalidation = config.TxsCacheInvalidation
+
+	em.intervals = em.config.EmitIntervals
+	if em.validators == nil {
+		// not started yet
+		return
+	}
+	em.recountMaxParents()
+	if em.isValidator() {
+		em.recountValidators(em.validators)
+	}
+}
+
+func (em *Emitter) tick() *inter.EventPayload {
 	// track synced time
 	if em.world.PeersNum() == 0 {
 		// connected time ~= last time when it's true that "not connected yet"
-		em.syncStatus.lastConnected = time.Now()
+		em.syncStatus.lastConnected = em.clock()
 	}
 	if !em.world.IsSynced() {
 		// synced time ~= last time when it's true that "not synced yet"
-		em.syncStatus.p2pSynced = time.Now()
+		em.syncStatus.p2pSynced = em.clock()
 	}
 	if em.idle() {
 		em.busyRate.Mark(0)
@@ -183,14 +230,15 @@ func (em *Emitter) tick() {
 		em.busyRate.Mark(1)
 	}
 	if em.world.IsBusy() {
-		return
+		return nil
 	}
 
 	em.recheckChallenges()
 	em.recheckIdleTime()
-	if time.Since(em.prevEmittedAtTime) >= em.intervals.Min {
-		_ = em.EmitEvent()
+	if em.clock().Sub(em.prevEmittedAtTime) >= em.intervals.Min {
+		return em.EmitEvent()
 	}
+	return nil
 }
 
 func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
@@ -199,7 +247,7 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 	if em.cache.sortedTxs != nil &&
 		em.cache.poolBlock == em.world.GetLatestBlockIndex() &&
 		em.cache.poolCount == poolCount &&
-		time.Since(em.cache.poolTime) < em.config.TxsCacheInvalidation {
+		em.clock().Sub(em.cache.poolTime) < em.config.TxsCacheInvalidation {
 		return em.cache.sortedTxs.Copy()
 	}
 	// Build the cache
@@ -218,7 +266,7 @@ func (em *Emitter) getSortedTxs() *types.TransactionsByPriceAndNonce {
 	em.cache.sortedTxs = sortedTxs
 	em.cache.poolCount = poolCount
 	em.cache.poolBlock = em.world.GetLatestBlockIndex()
-	em.cache.poolTime = time.Now()
+	em.cache.poolTime = em.clock()
 	return sortedTxs.Copy()
 }
 
@@ -253,8 +301,12 @@ func (em *Emitter) EmitEvent() *inter.EventPayload {
 	// broadcast the event
 	em.world.Broadcast(e)
 
-	em.prevEmittedAtTime = time.Now() // record time after connecting, to add the event processing time"
+	em.prevEmittedAtTime = em.clock() // record time after connecting, to add the event processing time"
 	em.prevEmittedAtBlock = em.world.GetLatestBlockIndex()
+	em.stats.Events++
+	em.stats.Txs += uint64(e.Txs().Len())
+	em.stats.GasPowerUsed += e.GasPowerUsed()
+	em.stats.GasPowerLeft = e.GasPowerLeft().Min()
 	em.Log.Info("New event emitted", "id", e.ID(), "parents", len(e.Parents()), "by", e.Creator(),
 		"frame", e.Frame(), "txs", e.Txs().Len(), "age", common.PrettyDuration(0), "t", common.PrettyDuration(time.Since(start)))
 
@@ -286,6 +338,10 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 	if !em.isValidator() {
 		return nil
 	}
+	if em.maintenance.Paused {
+		em.Periodic.Info(7*time.Second, "Emitting is paused", "reason", "maintenance mode")
+		return nil
+	}
 
 	if synced := em.logSyncStatus(em.isSyncedToEmit()); !synced {
 		// I'm reindexing my old events, so don't create events until connect all the existing self-events
@@ -337,7 +393,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 
 	mutEvent.SetParents(parents)
 	mutEvent.SetLamport(maxLamport + 1)
-	mutEvent.SetCreationTime(inter.MaxTimestamp(inter.Timestamp(time.Now().UnixNano()), selfParentTime+1))
+	mutEvent.SetCreationTime(inter.MaxTimestamp(inter.Timestamp(em.clock().UnixNano()), selfParentTime+1))
 
 	// set consensus fields
 	var metric ancestor.Metric
@@ -358,17 +414,17 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 
 	// Pre-check if event should be emitted
 	// It is checked in advance to avoid adding transactions just to immediately drop the event later
-	if !em.isAllowedToEmit(mutEvent, true, metric, selfParentHeader) {
+	if !em.strategy.IsAllowedToEmit(mutEvent, true, metric, selfParentHeader) {
 		return nil
 	}
 
 	// Add txs
-	em.addTxs(mutEvent, sortedTxs)
+	limit := em.addTxs(mutEvent, sortedTxs)
 
 	// Check if event should be emitted
 	// Check only if no txs were added, since check in a case with added txs was performed above
 	if mutEvent.Txs().Len() == 0 {
-		if !em.isAllowedToEmit(mutEvent, mutEvent.Txs().Len() != 0, metric, selfParentHeader) {
+		if !em.strategy.IsAllowedToEmit(mutEvent, mutEvent.Txs().Len() != 0, metric, selfParentHeader) {
 			return nil
 		}
 	}
@@ -395,6 +451,13 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) *in
 		return nil
 	}
 
+	switch limit {
+	case txsLimitedTps:
+		em.stats.LimitedTps++
+	case txsNotAllowed:
+		em.stats.NoTxs++
+	}
+
 	// set mutEvent name for debug
 	em.nameEventForDebug(event)
 
@@ -105,3 +105,59 @@ func TestEmitter(t *testing.T) {
 		em.tick()
 	})
 }
+
+func TestEmitter_SetConfig(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Validator.ID = 1
+	vv := pos.NewBuilder()
+	for v := idx.ValidatorID(1); v <= 3; v++ {
+		vv.Set(v, pos.Weight(1))
+	}
+	validators := vv.Build()
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	external.EXPECT().Lock().
+		AnyTimes()
+	external.EXPECT().Unlock().
+		AnyTimes()
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(skyhigh.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(validators, idx.Epoch(1)).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(idx.Epoch(1), cfg.Validator.ID).
+		Return((*hash.Event)(nil)).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(0)).
+		AnyTimes()
+
+	em := NewEmitter(cfg, World{External: external})
+	em.init()
+	require.Equal(skyhigh.FakeNetRules().Dag.MaxParents, em.maxParents)
+
+	newCfg := cfg
+	newCfg.Validator.ID = 2
+	newCfg.EmitIntervals.Min = time.Second
+	newCfg.EmitIntervals.Max = time.Hour
+	newCfg.MaxParents = 3
+	newCfg.NoTxsThreshold = cfg.NoTxsThreshold * 2
+	newCfg.MaxTxsPerAddress = 1
+	em.SetConfig(newCfg)
+
+	require.Equal(cfg.Validator.ID, em.config.Validator.ID)
+	require.Equal(newCfg.NoTxsThreshold, em.config.NoTxsThreshold)
+	require.Equal(newCfg.MaxTxsPerAddress, em.config.MaxTxsPerAddress)
+	require.Equal(idx.Event(3), em.maxParents)
+	require.Equal(time.Second, em.intervals.Min)
+	// Max is randomized
+	require.True(em.intervals.Max > time.Hour*9/10 && em.intervals.Max <= time.Hour, em.intervals.Max)
+	require.NotZero(em.intervals.Confirming)
+}
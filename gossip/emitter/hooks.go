@@ -14,16 +14,11 @@ import (
 
 // OnNewEpoch should be called after each epoch change, and on startup
 func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch) {
-	em.maxParents = em.config.MaxParents
-	rules := em.world.GetRules()
-	if em.maxParents == 0 {
-		em.maxParents = rules.Dag.MaxParents
-	}
-	if em.maxParents > rules.Dag.MaxParents {
-		em.maxParents = rules.Dag.MaxParents
-	}
+	em.recountMaxParents()
+	em.onMaintenanceNewEpoch(newEpoch)
 
 	em.validators, em.epoch = newValidators, newEpoch
+	em.onKeyRotationNewEpoch()
 
 	if !em.isValidator() {
 		return
@@ -46,6 +41,19 @@ func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch)
 			return updMetric(median, current, update, validatorIdx, newValidators)
 		})
 	em.payloadIndexer = ancestor.NewPayloadIndexer(PayloadIndexerSize)
+	em.strategy.OnNewEpoch(newValidators, newEpoch)
+}
+
+// recountMaxParents limits the configured MaxParents with the network rules
+func (em *Emitter) recountMaxParents() {
+	em.maxParents = em.config.MaxParents
+	rules := em.world.GetRules()
+	if em.maxParents == 0 {
+		em.maxParents = rules.Dag.MaxParents
+	}
+	if em.maxParents > rules.Dag.MaxParents {
+		em.maxParents = rules.Dag.MaxParents
+	}
 }
 
 // OnEventConnected tracks new events
@@ -68,12 +76,14 @@ func (em *Emitter) OnEventConnected(e inter.EventPayloadI) {
 	delete(em.challenges, e.Creator())
 	// mark validator as online
 	delete(em.offlineValidators, e.Creator())
+	em.strategy.OnEventConnected(e)
 }
 
 func (em *Emitter) OnEventConfirmed(he inter.EventI) {
 	if !em.isValidator() {
 		return
 	}
+	em.onMaintenanceEventConfirmed(he)
 	if em.pendingGas > he.GasPowerUsed() {
 		em.pendingGas -= he.GasPowerUsed()
 	} else {
@@ -86,4 +96,5 @@ func (em *Emitter) OnEventConfirmed(he inter.EventI) {
 			em.originatedTxs.Dec(addr)
 		}
 	}
+	em.strategy.OnEventConfirmed(he)
 }
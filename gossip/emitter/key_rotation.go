@@ -0,0 +1,173 @@
+package emitter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+)
+
+var (
+	ErrSameKey = errors.New("the key is already used for signing")
+)
+
+// KeyRotationStatus is a state of the validator key rotation
+type KeyRotationStatus struct {
+	// PubKey is the key which events are signed with
+	PubKey *validatorpk.PubKey `json:"pubkey"`
+	// Next is the key which events will be signed with, after it becomes active in the SFC
+	Next *validatorpk.PubKey `json:"next"`
+	// SwitchedAt is the epoch when the signing key was switched last time
+	SwitchedAt idx.Epoch `json:"switchedAt"`
+}
+
+type keyRotation struct {
+	next       *validatorpk.PubKey
+	switchedAt idx.Epoch
+}
+
+// KeyRotationStatus returns the state of the key rotation.
+func (em *Emitter) KeyRotationStatus() KeyRotationStatus {
+	em.world.Lock()
+	defer em.world.Unlock()
+	return em.keyRotationStatus()
+}
+
+func (em *Emitter) keyRotationStatus() KeyRotationStatus {
+	pubkey := em.config.Validator.PubKey
+	return KeyRotationStatus{
+		PubKey:     &pubkey,
+		Next:       em.keyRotation.next,
+		SwitchedAt: em.keyRotation.switchedAt,
+	}
+}
+
+// RotateKey schedules a switch of the signing key. Events are signed with the current key until the first epoch
+// in which the new key is the validator's pubkey, starting from that epoch they are signed with the new key.
+// The new key has to be unlocked in the signer.
+func (em *Emitter) RotateKey(pubkey validatorpk.PubKey) (KeyRotationStatus, error) {
+	em.world.Lock()
+	defer em.world.Unlock()
+	if em.config.Validator.ID == 0 {
+		return KeyRotationStatus{}, ErrNotValidator
+	}
+	if bytes.Equal(pubkey.Bytes(), em.config.Validator.PubKey.Bytes()) {
+		return KeyRotationStatus{}, ErrSameKey
+	}
+	// make sure the new key is usable before the switch
+	if _, err := em.world.Signer.Sign(pubkey, make([]byte, 32)); err != nil {
+		return KeyRotationStatus{}, err
+	}
+	prev := em.keyRotation.next
+	em.keyRotation.next = &pubkey
+	if err := em.writeKeyRotationFile(em.keyRotationStatus()); err != nil {
+		em.keyRotation.next = prev
+		return KeyRotationStatus{}, err
+	}
+	em.Log.Warn("Validator key rotation is scheduled", "pubkey", pubkey.String())
+	// the new key may be active already
+	em.switchKey()
+	return em.keyRotationStatus(), nil
+}
+
+// onKeyRotationNewEpoch switches the signing key if the scheduled key is active in the new epoch
+func (em *Emitter) onKeyRotationNewEpoch() {
+	if em.keyRotation.next == nil {
+		return
+	}
+	em.switchKey()
+}
+
+func (em *Emitter) switchKey() {
+	pubkeys, epoch := em.world.GetEpochPubKeys()
+	active, ok := pubkeys[em.config.Validator.ID]
+	if !ok || !bytes.Equal(active.Bytes(), em.keyRotation.next.Bytes()) {
+		return
+	}
+	prev := em.config.Validator.PubKey
+	em.config.Validator.PubKey = *em.keyRotation.next
+	em.keyRotation.next = nil
+	em.keyRotation.switchedAt = epoch
+	// the file keeps the new key, so the node refuses to restart with the previous one
+	if err := em.writeKeyRotationFile(em.keyRotationStatus()); err != nil {
+		em.Log.Error("Failed to write key rotation file", "err", err)
+	}
+	em.Log.Warn("Switched to the new validator key, use it as --validator.pubkey after a restart",
+		"epoch", epoch, "pubkey", em.config.Validator.PubKey.String(), "prev", prev.String())
+}
+
+// ReadKeyRotationFile returns the key rotation state, persisted by the emitter. Returns nil if the file doesn't exist.
+func ReadKeyRotationFile(path string) (*KeyRotationStatus, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status := &KeyRotationStatus{}
+	if err := json.Unmarshal(data, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// loadKeyRotationFile restores the scheduled key rotation after a restart.
+// The scheduled key has to be unlocked in the signer before the emitter is started.
+func (em *Emitter) loadKeyRotationFile() {
+	if em.config.Validator.ID == 0 {
+		return
+	}
+	status, err := ReadKeyRotationFile(em.config.KeyRotationFile)
+	if err != nil {
+		// don't emit if the signing key is unknown
+		em.Log.Crit("Failed to read key rotation file", "file", em.config.KeyRotationFile, "err", err)
+	}
+	if status == nil {
+		return
+	}
+	em.keyRotation.switchedAt = status.SwitchedAt
+	pubkey := em.config.Validator.PubKey
+	if status.Next != nil && !bytes.Equal(status.Next.Bytes(), pubkey.Bytes()) {
+		if _, err := em.world.Signer.Sign(*status.Next, make([]byte, 32)); err != nil {
+			em.Log.Crit("Scheduled validator key is locked", "pubkey", status.Next.String(), "err", err)
+		}
+		em.keyRotation.next = status.Next
+		em.Log.Warn("Validator key rotation is scheduled", "pubkey", status.Next.String())
+		em.switchKey()
+		return
+	}
+	if status.Next == nil && status.PubKey != nil && !bytes.Equal(status.PubKey.Bytes(), pubkey.Bytes()) {
+		pubkeys, _ := em.world.GetEpochPubKeys()
+		if active, ok := pubkeys[em.config.Validator.ID]; ok && bytes.Equal(active.Bytes(), status.PubKey.Bytes()) {
+			em.Log.Crit("Validator key was rotated, restart with the new key", "flag", "--validator.pubkey="+status.PubKey.String(), "configured", pubkey.String())
+		}
+	}
+}
+
+func (em *Emitter) writeKeyRotationFile(status KeyRotationStatus) error {
+	if len(em.config.KeyRotationFile) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(em.config.KeyRotationFile), 0700); err != nil {
+		return err
+	}
+	tmp := em.config.KeyRotationFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, em.config.KeyRotationFile)
+}
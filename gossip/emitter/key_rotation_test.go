@@ -0,0 +1,112 @@
+package emitter
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter/mock"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/vecmt"
+)
+
+func TestEmitter_RotateKey(t *testing.T) {
+	require := require.New(t)
+
+	oldKey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{1}}
+	newKey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{2}}
+	lockedKey := validatorpk.PubKey{Type: validatorpk.Types.Secp256k1, Raw: []byte{3}}
+
+	cfg := DefaultConfig()
+	cfg.Validator.ID = 1
+	cfg.Validator.PubKey = oldKey
+	cfg.KeyRotationFile = filepath.Join(t.TempDir(), "keyrotation")
+	vv := pos.NewBuilder()
+	vv.Set(cfg.Validator.ID, pos.Weight(1))
+	validators := vv.Build()
+
+	epochPubKeys := map[idx.ValidatorID]validatorpk.PubKey{cfg.Validator.ID: oldKey}
+	epoch := idx.Epoch(1)
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	external.EXPECT().Lock().
+		AnyTimes()
+	external.EXPECT().Unlock().
+		AnyTimes()
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(skyhigh.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(validators, epoch).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(gomock.Any(), cfg.Validator.ID).
+		Return((*hash.Event)(nil)).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(0)).
+		AnyTimes()
+	external.EXPECT().GetEpochPubKeys().
+		DoAndReturn(func() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch) {
+			return epochPubKeys, epoch
+		}).
+		AnyTimes()
+
+	signer := mock.NewMockSigner(ctrl)
+	signer.EXPECT().Sign(lockedKey, gomock.Any()).
+		Return(nil, errors.New("locked")).
+		AnyTimes()
+	signer.EXPECT().Sign(newKey, gomock.Any()).
+		Return(make([]byte, 64), nil).
+		AnyTimes()
+
+	em := NewEmitter(cfg, World{External: external, Signer: signer})
+	em.init()
+
+	_, err := em.RotateKey(oldKey)
+	require.Equal(ErrSameKey, err)
+	_, err = em.RotateKey(lockedKey)
+	require.Error(err)
+	require.Nil(em.KeyRotationStatus().Next)
+
+	// the new key isn't active yet
+	status, err := em.RotateKey(newKey)
+	require.NoError(err)
+	require.Equal(oldKey, *status.PubKey)
+	require.Equal(newKey, *status.Next)
+
+	// the scheduled rotation is restored after a restart
+	em = NewEmitter(cfg, World{External: external, Signer: signer})
+	em.init()
+	require.Equal(newKey, *em.KeyRotationStatus().Next)
+
+	epoch = 2
+	em.OnNewEpoch(validators, epoch)
+	require.Equal(oldKey, *em.KeyRotationStatus().PubKey)
+
+	// the new key is active since epoch 3
+	epoch = 3
+	epochPubKeys = map[idx.ValidatorID]validatorpk.PubKey{cfg.Validator.ID: newKey}
+	em.OnNewEpoch(validators, epoch)
+	status = em.KeyRotationStatus()
+	require.Equal(newKey, *status.PubKey)
+	require.Nil(status.Next)
+	require.Equal(idx.Epoch(3), status.SwitchedAt)
+
+	// the new key is persisted after the switch
+	persisted, err := ReadKeyRotationFile(cfg.KeyRotationFile)
+	require.NoError(err)
+	require.Equal(newKey, *persisted.PubKey)
+	require.Nil(persisted.Next)
+}
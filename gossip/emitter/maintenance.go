@@ -0,0 +1,260 @@
+package emitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+var (
+	ErrNotValidator    = errors.New("not a validator")
+	ErrNotPaused       = errors.New("emission isn't paused")
+	ErrNotAcknowledged = errors.New("resuming requires an acknowledgement")
+)
+
+// MaintenanceStatus is a state of the maintenance mode, in which the validator doesn't emit events
+type MaintenanceStatus struct {
+	Paused bool `json:"paused"`
+	// Since is a time when the emission was paused
+	Since time.Time `json:"since"`
+	// Host is a host name of the node which paused the emission
+	Host string `json:"host"`
+	// LastEmitted is the last self-event at the moment of pausing
+	LastEmitted *hash.Event `json:"lastEmitted"`
+	// Drained is true if LastEmitted is confirmed or may not be confirmed anymore
+	Drained bool `json:"drained"`
+	// ExternalEvents is true if self-events created by another instance were observed during the pause
+	ExternalEvents bool `json:"externalEvents"`
+}
+
+type maintenance struct {
+	MaintenanceStatus
+	drained chan struct{}
+	// confirmedSeq is the highest confirmed self-event of the current epoch
+	confirmedSeq idx.Event
+}
+
+// MaintenanceStatus returns the state of the maintenance mode.
+func (em *Emitter) MaintenanceStatus() MaintenanceStatus {
+	em.world.Lock()
+	defer em.world.Unlock()
+	return em.maintenance.MaintenanceStatus
+}
+
+// Pause stops the events emission until Resume is called, the paused state survives restarts.
+func (em *Emitter) Pause() (MaintenanceStatus, error) {
+	em.world.Lock()
+	defer em.world.Unlock()
+	if em.config.Validator.ID == 0 {
+		return MaintenanceStatus{}, ErrNotValidator
+	}
+	if em.maintenance.Paused {
+		return em.maintenance.MaintenanceStatus, nil
+	}
+
+	host, _ := os.Hostname()
+	status := MaintenanceStatus{
+		Paused: true,
+		Since:  em.clock(),
+		Host:   host,
+	}
+	if em.validators != nil {
+		status.LastEmitted = em.world.GetLastEvent(em.epoch, em.config.Validator.ID)
+	}
+	if status.LastEmitted == nil {
+		status.Drained = true
+	} else if e := em.world.GetEvent(*status.LastEmitted); e == nil || e.Seq() <= em.maintenance.confirmedSeq {
+		status.Drained = true
+	}
+	if err := em.writeMaintenanceFile(status); err != nil {
+		return MaintenanceStatus{}, err
+	}
+	em.maintenance.MaintenanceStatus = status
+	em.maintenance.drained = make(chan struct{})
+	if status.Drained {
+		close(em.maintenance.drained)
+	}
+	em.Log.Warn("Emitting is paused for maintenance", "last", status.LastEmitted, "drained", status.Drained)
+	return status, nil
+}
+
+// WaitDrained waits until the last self-event emitted before the pause is confirmed.
+func (em *Emitter) WaitDrained(ctx context.Context) error {
+	em.world.Lock()
+	drained := em.maintenance.drained
+	paused := em.maintenance.Paused
+	em.world.Unlock()
+	if !paused {
+		return ErrNotPaused
+	}
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if !em.MaintenanceStatus().Paused {
+		return ErrNotPaused
+	}
+	return nil
+}
+
+// Resume resumes the events emission. If the emission was paused on another host, or self-events
+// of another instance were observed, then resuming requires an acknowledgement that the other instance is stopped.
+// In such a case, the emission starts only after the doublesign protection interval.
+func (em *Emitter) Resume(acknowledge bool) (MaintenanceStatus, error) {
+	em.world.Lock()
+	defer em.world.Unlock()
+	if !em.maintenance.Paused {
+		return em.maintenance.MaintenanceStatus, ErrNotPaused
+	}
+	risks := em.resumeRisks()
+	if len(risks) != 0 && !acknowledge {
+		return em.maintenance.MaintenanceStatus, fmt.Errorf("%w: %s", ErrNotAcknowledged, strings.Join(risks, "; "))
+	}
+	if err := em.removeMaintenanceFile(); err != nil {
+		return em.maintenance.MaintenanceStatus, err
+	}
+	if len(risks) != 0 {
+		// wait as if a self-event of another instance was just downloaded
+		em.syncStatus.externalSelfEventDetected = em.clock()
+	}
+	em.setDrained()
+	em.maintenance.MaintenanceStatus = MaintenanceStatus{}
+	em.Log.Warn("Emitting is resumed", "acknowledged", len(risks) != 0)
+	return em.maintenance.MaintenanceStatus, nil
+}
+
+// resumeRisks returns the reasons why another instance of the validator may be running
+func (em *Emitter) resumeRisks() []string {
+	var risks []string
+	if host, _ := os.Hostname(); em.maintenance.Host != host {
+		risks = append(risks, fmt.Sprintf("emission was paused on another host %s", em.maintenance.Host))
+	}
+	if em.maintenance.ExternalEvents {
+		risks = append(risks, "self-events of another instance were observed during the pause")
+	}
+	if em.emittedEventFile != nil && em.validators != nil {
+		last := em.world.GetLastEvent(em.epoch, em.config.Validator.ID)
+		prev := em.readLastEmittedEventID()
+		if last != nil && (prev == nil || *prev != *last) {
+			risks = append(risks, fmt.Sprintf("the last self-event %s wasn't emitted by this node", last.String()))
+		}
+	}
+	return risks
+}
+
+func (em *Emitter) setDrained() {
+	em.maintenance.Drained = true
+	if em.maintenance.drained != nil {
+		select {
+		case <-em.maintenance.drained:
+		default:
+			close(em.maintenance.drained)
+		}
+	}
+}
+
+// onMaintenanceEventConfirmed marks the emission as drained once the last self-event is confirmed
+func (em *Emitter) onMaintenanceEventConfirmed(e inter.EventI) {
+	if e.Creator() != em.config.Validator.ID {
+		return
+	}
+	if e.Seq() > em.maintenance.confirmedSeq {
+		em.maintenance.confirmedSeq = e.Seq()
+	}
+	if em.maintenance.Paused && !em.maintenance.Drained && e.ID() == *em.maintenance.LastEmitted {
+		em.onDrained()
+	}
+}
+
+// onMaintenanceNewEpoch marks the emission as drained, as events of a sealed epoch cannot be confirmed anymore
+func (em *Emitter) onMaintenanceNewEpoch(newEpoch idx.Epoch) {
+	em.maintenance.confirmedSeq = 0
+	if em.maintenance.Paused && !em.maintenance.Drained && em.maintenance.LastEmitted.Epoch() < newEpoch {
+		em.onDrained()
+	}
+}
+
+// onMaintenanceExternalEvent memorizes that another instance emits events during the pause.
+// Unlike the unpaused emitter, it doesn't stop the node, because the paused one cannot doublesign.
+func (em *Emitter) onMaintenanceExternalEvent(e inter.EventPayloadI) {
+	if em.maintenance.ExternalEvents || e.CreationTime().Time().Before(em.maintenance.Since) {
+		return
+	}
+	em.maintenance.ExternalEvents = true
+	em.Log.Warn("Self-event of another instance is observed during the pause", "id", e.ID())
+	if err := em.writeMaintenanceFile(em.maintenance.MaintenanceStatus); err != nil {
+		em.Log.Error("Failed to write maintenance file", "err", err)
+	}
+}
+
+func (em *Emitter) onDrained() {
+	em.setDrained()
+	em.Log.Info("Last self-event before the pause is confirmed", "id", em.maintenance.LastEmitted)
+	if err := em.writeMaintenanceFile(em.maintenance.MaintenanceStatus); err != nil {
+		em.Log.Error("Failed to write maintenance file", "err", err)
+	}
+}
+
+// loadMaintenanceFile restores the paused state after a restart
+func (em *Emitter) loadMaintenanceFile() {
+	if len(em.config.MaintenanceFile) == 0 {
+		return
+	}
+	data, err := ioutil.ReadFile(em.config.MaintenanceFile)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &em.maintenance.MaintenanceStatus)
+	}
+	if err != nil {
+		// don't emit if the paused state is unknown
+		em.Log.Crit("Failed to read maintenance file", "file", em.config.MaintenanceFile, "err", err)
+	}
+	em.maintenance.drained = make(chan struct{})
+	if em.maintenance.Drained {
+		close(em.maintenance.drained)
+	}
+	em.Log.Warn("Emitting is paused for maintenance", "since", em.maintenance.Since, "host", em.maintenance.Host)
+}
+
+func (em *Emitter) writeMaintenanceFile(status MaintenanceStatus) error {
+	if len(em.config.MaintenanceFile) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(em.config.MaintenanceFile), 0700); err != nil {
+		return err
+	}
+	tmp := em.config.MaintenanceFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, em.config.MaintenanceFile)
+}
+
+func (em *Emitter) removeMaintenanceFile() error {
+	if len(em.config.MaintenanceFile) == 0 {
+		return nil
+	}
+	err := os.Remove(em.config.MaintenanceFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,165 @@
+package emitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter/mock"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/vecmt"
+)
+
+func TestEmitter_Maintenance(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Validator.ID = 1
+	cfg.MaintenanceFile = filepath.Join(t.TempDir(), "emitter", "maintenance-1")
+	vv := pos.NewBuilder()
+	for v := idx.ValidatorID(1); v <= 3; v++ {
+		vv.Set(v, pos.Weight(1))
+	}
+	validators := vv.Build()
+
+	me := &inter.MutableEventPayload{}
+	me.SetEpoch(1)
+	me.SetCreator(cfg.Validator.ID)
+	me.SetSeq(2)
+	lastEmitted := me.Build()
+	lastEmittedID := lastEmitted.ID()
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	external.EXPECT().Lock().
+		AnyTimes()
+	external.EXPECT().Unlock().
+		AnyTimes()
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(skyhigh.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(validators, idx.Epoch(1)).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(idx.Epoch(1), cfg.Validator.ID).
+		Return(&lastEmittedID).
+		AnyTimes()
+	external.EXPECT().GetEvent(lastEmittedID).
+		Return(&lastEmitted.Event).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(0)).
+		AnyTimes()
+
+	newEmitter := func() *Emitter {
+		em := NewEmitter(cfg, World{External: external})
+		em.init()
+		return em
+	}
+	em := newEmitter()
+	require.False(em.MaintenanceStatus().Paused)
+	_, err := em.Resume(false)
+	require.Equal(ErrNotPaused, err)
+
+	// pause
+	status, err := em.Pause()
+	require.NoError(err)
+	require.True(status.Paused)
+	require.False(status.Drained)
+	require.Equal(lastEmittedID, *status.LastEmitted)
+	require.Nil(em.createEvent(nil))
+
+	// drain
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.Equal(context.DeadlineExceeded, em.WaitDrained(ctx))
+	em.OnEventConfirmed(lastEmitted)
+	require.NoError(em.WaitDrained(context.Background()))
+	require.True(em.MaintenanceStatus().Drained)
+
+	// paused state survives a restart
+	em = newEmitter()
+	require.Equal(status.Since.Unix(), em.MaintenanceStatus().Since.Unix())
+	require.True(em.MaintenanceStatus().Paused)
+	require.True(em.MaintenanceStatus().Drained)
+
+	// resume
+	status, err = em.Resume(false)
+	require.NoError(err)
+	require.False(status.Paused)
+	require.True(em.syncStatus.externalSelfEventDetected.IsZero())
+	_, err = os.Stat(cfg.MaintenanceFile)
+	require.True(os.IsNotExist(err))
+}
+
+func TestEmitter_MaintenanceOnAnotherHost(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Validator.ID = 1
+	cfg.MaintenanceFile = filepath.Join(t.TempDir(), "maintenance-1")
+	vv := pos.NewBuilder()
+	vv.Set(cfg.Validator.ID, pos.Weight(1))
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	external.EXPECT().Lock().
+		AnyTimes()
+	external.EXPECT().Unlock().
+		AnyTimes()
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(skyhigh.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(vv.Build(), idx.Epoch(1)).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(idx.Epoch(1), cfg.Validator.ID).
+		Return((*hash.Event)(nil)).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(0)).
+		AnyTimes()
+
+	// the datadir is moved from another host
+	data, err := json.Marshal(MaintenanceStatus{
+		Paused:  true,
+		Since:   time.Now(),
+		Host:    "another-host",
+		Drained: true,
+	})
+	require.NoError(err)
+	require.NoError(ioutil.WriteFile(cfg.MaintenanceFile, data, 0600))
+
+	em := NewEmitter(cfg, World{External: external})
+	em.init()
+	require.True(em.MaintenanceStatus().Paused)
+
+	_, err = em.Resume(false)
+	require.True(errors.Is(err, ErrNotAcknowledged), err)
+	require.Contains(err.Error(), "another-host")
+	require.True(em.MaintenanceStatus().Paused)
+
+	// the doublesign protection is applied after the acknowledgement
+	_, err = em.Resume(true)
+	require.NoError(err)
+	require.False(em.MaintenanceStatus().Paused)
+	require.False(em.syncStatus.externalSelfEventDetected.IsZero())
+}
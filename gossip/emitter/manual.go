@@ -0,0 +1,93 @@
+package emitter
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/skyhighblockchain/push-base/emitter/ancestor"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// NewManualEmitter makes an emitter, which doesn't emit events by itself.
+// Events are emitted only by EmitEvent calls, at the time of the clock, and parents are chosen using r.
+// The emission is deterministic if the clock, r and the world are deterministic, which is used by simulations.
+func NewManualEmitter(config Config, world World, clock func() time.Time, r *rand.Rand) *Emitter {
+	return newManualEmitter(config, world, clock, r, false)
+}
+
+// NewTickedEmitter makes an emitter, which doesn't emit events by itself, similar to NewManualEmitter.
+// Unlike the manual emitter, the emission timing is decided by the configured strategy on every Tick call,
+// as if the calls were the ticks of a started emitter. It's used to evaluate the emitter config in simulations.
+func NewTickedEmitter(config Config, world World, clock func() time.Time, r *rand.Rand) *Emitter {
+	return newManualEmitter(config, world, clock, r, true)
+}
+
+func newManualEmitter(config Config, world World, clock func() time.Time, r *rand.Rand, ticked bool) *Emitter {
+	em := NewEmitter(config, world)
+	em.clock = clock
+	em.manual = true
+	// randomize the emit time using r instead of the time seed
+	em.config.EmitIntervals = config.EmitIntervals.RandomizeEmitTime(r)
+	em.intervals = em.config.EmitIntervals
+	em.strategy = &manualStrategy{
+		Strategy: em.makeStrategy(),
+		em:       em,
+		r:        r,
+		ticked:   ticked,
+	}
+	return em
+}
+
+// Tick runs a single iteration of the emission loop, and returns the emitted event if any.
+// It's supposed to be called only for the ticked emitters, which don't emit events by themselves.
+func (em *Emitter) Tick() *inter.EventPayload {
+	return em.tick()
+}
+
+// manualStrategy emits an event on every EmitEvent call, the timing is decided by the caller.
+// If ticked, the timing is decided by the wrapped strategy.
+type manualStrategy struct {
+	Strategy
+	em     *Emitter
+	r      *rand.Rand
+	ticked bool
+}
+
+func (s *manualStrategy) SearchStrategies(maxParents idx.Event) []ancestor.SearchStrategy {
+	strategies := s.em.buildSearchStrategies(maxParents, ancestor.NewRandomStrategy(s.r))
+	for i, st := range strategies {
+		strategies[i] = sortedStrategy{st}
+	}
+	return strategies
+}
+
+func (s *manualStrategy) IsAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool {
+	if s.ticked {
+		return s.Strategy.IsAllowedToEmit(e, eTxs, metric, selfParent)
+	}
+	return true
+}
+
+// sortedStrategy sorts the options before the choice, because ancestor.ChooseParents shuffles them
+type sortedStrategy struct {
+	ancestor.SearchStrategy
+}
+
+func (st sortedStrategy) Choose(existingParents hash.Events, options hash.Events) int {
+	sorted := options.Copy()
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Bytes(), sorted[j].Bytes()) < 0
+	})
+	chosen := sorted[st.SearchStrategy.Choose(existingParents, sorted)]
+	for i, id := range options {
+		if id == chosen {
+			return i
+		}
+	}
+	return 0
+}
@@ -98,6 +98,21 @@ func (mr *MockExternalMockRecorder) DagIndex() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DagIndex", reflect.TypeOf((*MockExternal)(nil).DagIndex))
 }
 
+// GetEpochPubKeys mocks base method
+func (m *MockExternal) GetEpochPubKeys() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpochPubKeys")
+	ret0, _ := ret[0].(map[idx.ValidatorID]validatorpk.PubKey)
+	ret1, _ := ret[1].(idx.Epoch)
+	return ret0, ret1
+}
+
+// GetEpochPubKeys indicates an expected call of GetEpochPubKeys
+func (mr *MockExternalMockRecorder) GetEpochPubKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpochPubKeys", reflect.TypeOf((*MockExternal)(nil).GetEpochPubKeys))
+}
+
 // GetEpochValidators mocks base method
 func (m *MockExternal) GetEpochValidators() (*pos.Validators, idx.Epoch) {
 	m.ctrl.T.Helper()
@@ -8,8 +8,9 @@ import (
 	"github.com/skyhighblockchain/push-base/inter/idx"
 )
 
-// buildSearchStrategies returns a strategy for each parent search
-func (em *Emitter) buildSearchStrategies(maxParents idx.Event) []ancestor.SearchStrategy {
+// buildSearchStrategies returns a strategy for each parent search.
+// First parent is chosen by payload, the second quarter by auxStrategy, the rest by quorum metric.
+func (em *Emitter) buildSearchStrategies(maxParents idx.Event, auxStrategy ancestor.SearchStrategy) []ancestor.SearchStrategy {
 	strategies := make([]ancestor.SearchStrategy, 0, maxParents)
 	if maxParents == 0 {
 		return strategies
@@ -18,9 +19,8 @@ func (em *Emitter) buildSearchStrategies(maxParents idx.Event) []ancestor.Search
 	for idx.Event(len(strategies)) < 1 {
 		strategies = append(strategies, payloadStrategy)
 	}
-	randStrategy := ancestor.NewRandomStrategy(nil)
 	for idx.Event(len(strategies)) < maxParents/2 {
-		strategies = append(strategies, randStrategy)
+		strategies = append(strategies, auxStrategy)
 	}
 	quorumStrategy := em.quorumIndexer.SearchStrategy()
 	for idx.Event(len(strategies)) < maxParents {
@@ -43,6 +43,6 @@ func (em *Emitter) chooseParents(epoch idx.Epoch, myValidatorID idx.ValidatorID)
 	if selfParent != nil {
 		parents = hash.Events{*selfParent}
 	}
-	parents = ancestor.ChooseParents(parents, heads, em.buildSearchStrategies(em.maxParents-idx.Event(len(parents))))
+	parents = ancestor.ChooseParents(parents, heads, em.strategy.SearchStrategies(em.maxParents-idx.Event(len(parents))))
 	return selfParent, parents, true
 }
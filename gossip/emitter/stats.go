@@ -0,0 +1,27 @@
+package emitter
+
+// Stats is a snapshot of the emitter counters, which are used to evaluate the emitter config under load
+type Stats struct {
+	// Events is a number of the emitted events
+	Events uint64
+	// Txs is a number of the originated transactions
+	Txs uint64
+	// GasPowerUsed is a gas power consumed by the emitted events
+	GasPowerUsed uint64
+	// GasPowerLeft is a min gas power left of the last emitted event
+	GasPowerLeft uint64
+
+	// LimitedTps is a number of events, whose transactions were limited because of LimitedTpsThreshold
+	LimitedTps uint64
+	// NoTxs is a number of events, which couldn't originate transactions because of NoTxsThreshold
+	NoTxs uint64
+	// Emergency is a number of times when the emission was refused because of EmergencyThreshold
+	Emergency uint64
+}
+
+// Stats returns the counters since the emitter was created.
+func (em *Emitter) Stats() Stats {
+	em.world.Lock()
+	defer em.world.Unlock()
+	return em.stats
+}
@@ -0,0 +1,94 @@
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter/mock"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/vecmt"
+)
+
+func TestEmitterStats(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Validator.ID = 1
+	vv := pos.NewBuilder()
+	for v := idx.ValidatorID(1); v <= 3; v++ {
+		vv.Set(v, pos.Weight(1))
+	}
+	validators := vv.Build()
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	external.EXPECT().Lock().
+		AnyTimes()
+	external.EXPECT().Unlock().
+		AnyTimes()
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(skyhigh.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(validators, idx.Epoch(1)).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(idx.Epoch(1), cfg.Validator.ID).
+		Return((*hash.Event)(nil)).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(uint64(time.Now().UnixNano()))).
+		AnyTimes()
+	external.EXPECT().GetLatestBlockIndex().
+		Return(idx.Block(1)).
+		AnyTimes()
+
+	em := NewEmitter(cfg, World{External: external})
+	em.init()
+	require.Equal(Stats{}, em.Stats())
+
+	newEvent := func(gasPowerLeft uint64) *inter.MutableEventPayload {
+		now := inter.Timestamp(time.Now().UnixNano())
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(1)
+		me.SetCreator(cfg.Validator.ID)
+		me.SetSeq(1)
+		me.SetCreationTime(now)
+		me.SetMedianTime(now)
+		me.SetGasPowerLeft(inter.GasPowerLeft{Gas: [inter.GasPowerConfigs]uint64{gasPowerLeft, gasPowerLeft}})
+		return me
+	}
+
+	// enough gas power
+	gas, limit := em.maxGasPowerToUse(newEvent(cfg.LimitedTpsThreshold * 10))
+	require.NotZero(gas)
+	require.Equal(txsNotLimited, limit)
+
+	// smoothed TPS
+	gas, limit = em.maxGasPowerToUse(newEvent((cfg.LimitedTpsThreshold + cfg.NoTxsThreshold) / 2))
+	require.NotZero(gas)
+	require.Equal(txsLimitedTps, limit)
+
+	// no txs
+	gas, limit = em.maxGasPowerToUse(newEvent(cfg.NoTxsThreshold / 2))
+	require.Zero(gas)
+	require.Equal(txsNotAllowed, limit)
+
+	// the limits are counted per emitted event, not per attempt
+	require.Equal(Stats{}, em.Stats())
+
+	// emergency
+	selfParent := newEvent(cfg.EmergencyThreshold).Build()
+	e := newEvent(cfg.EmergencyThreshold / 2)
+	require.False(em.isAllowedToEmit(em.intervals, e, false, 0, &selfParent.Event))
+	require.Equal(uint64(1), em.Stats().Emergency)
+}
@@ -0,0 +1,86 @@
+package emitter
+
+import (
+	"github.com/skyhighblockchain/push-base/emitter/ancestor"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+const (
+	// DefaultStrategy is the name of the default emitter strategy
+	DefaultStrategy = "default"
+	// AdaptiveLatencyStrategy is the name of the strategy which adapts to the observed latencies
+	AdaptiveLatencyStrategy = "adaptive-latency"
+)
+
+// Strategy is a policy of parents selection and emission timing.
+// Strategy is called under the world lock, so it doesn't have to be safe for concurrent use.
+type Strategy interface {
+	// SearchStrategies returns a strategy for each parent search, besides the self-parent
+	SearchStrategies(maxParents idx.Event) []ancestor.SearchStrategy
+	// IsAllowedToEmit decides whether the event should be emitted.
+	// eTxs is true if event has (or may have) transactions, metric is an estimation of how much the event will advance the consensus
+	IsAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool
+
+	// OnNewEpoch is called after each epoch change, and on startup
+	OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch)
+	// OnEventConnected is called for each connected event
+	OnEventConnected(e inter.EventPayloadI)
+	// OnEventConfirmed is called for each confirmed event
+	OnEventConfirmed(e inter.EventI)
+}
+
+// StrategyFactory makes a strategy for the emitter.
+type StrategyFactory func(em *Emitter) Strategy
+
+var strategies = map[string]StrategyFactory{
+	DefaultStrategy:         NewDefaultStrategy,
+	AdaptiveLatencyStrategy: NewAdaptiveLatencyStrategy,
+}
+
+// RegisterStrategy registers a strategy, which may be selected by Config.Strategy.
+// Not safe for concurrent use, should be called before emitters are created.
+func RegisterStrategy(name string, factory StrategyFactory) {
+	strategies[name] = factory
+}
+
+// HasStrategy returns true if strategy is registered. Empty name stands for the default strategy.
+func HasStrategy(name string) bool {
+	_, ok := strategies[name]
+	return ok || name == ""
+}
+
+func (em *Emitter) makeStrategy() Strategy {
+	factory, ok := strategies[em.config.Strategy]
+	if !ok {
+		factory = NewDefaultStrategy
+	}
+	return factory(em)
+}
+
+// defaultStrategy chooses parents by payload, randomly and by quorum metric,
+// and controls emission timing by EmitIntervals
+type defaultStrategy struct {
+	em *Emitter
+}
+
+// NewDefaultStrategy makes the default strategy. It may be wrapped by custom strategies.
+func NewDefaultStrategy(em *Emitter) Strategy {
+	return &defaultStrategy{em}
+}
+
+func (s *defaultStrategy) SearchStrategies(maxParents idx.Event) []ancestor.SearchStrategy {
+	return s.em.buildSearchStrategies(maxParents, ancestor.NewRandomStrategy(nil))
+}
+
+func (s *defaultStrategy) IsAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool {
+	return s.em.isAllowedToEmit(s.em.intervals, e, eTxs, metric, selfParent)
+}
+
+func (s *defaultStrategy) OnNewEpoch(*pos.Validators, idx.Epoch) {}
+
+func (s *defaultStrategy) OnEventConnected(inter.EventPayloadI) {}
+
+func (s *defaultStrategy) OnEventConfirmed(inter.EventI) {}
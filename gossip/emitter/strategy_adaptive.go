@@ -0,0 +1,104 @@
+package emitter
+
+import (
+	"time"
+
+	"github.com/skyhighblockchain/push-base/emitter/ancestor"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/utils/piecefunc"
+)
+
+// latencySmoothing is an inverse weight of a new observation in the latency moving averages
+const latencySmoothing = 8
+
+// adaptiveLatencyStrategy prefers parents from the validators with a low events delivery latency,
+// and slows down emission when events are confirmed slowly, to not produce events which don't advance the consensus.
+// Other decisions are delegated to the default strategy.
+type adaptiveLatencyStrategy struct {
+	Strategy
+	em  *Emitter
+	cfg AdaptiveLatencyConfig
+
+	// peerLatency is a moving average of a delay between event creation and its connection, per creator
+	peerLatency map[idx.ValidatorID]time.Duration
+	// confirmationLatency is a moving average of a delay between self-event creation and its confirmation
+	confirmationLatency time.Duration
+}
+
+// NewAdaptiveLatencyStrategy makes the adaptive-latency strategy.
+func NewAdaptiveLatencyStrategy(em *Emitter) Strategy {
+	return &adaptiveLatencyStrategy{
+		Strategy:    NewDefaultStrategy(em),
+		em:          em,
+		cfg:         em.config.AdaptiveLatency,
+		peerLatency: make(map[idx.ValidatorID]time.Duration),
+	}
+}
+
+func observeLatency(avg time.Duration, since inter.Timestamp, now time.Time) time.Duration {
+	latency := now.Sub(since.Time())
+	if latency < 0 {
+		latency = 0
+	}
+	if avg == 0 {
+		return latency
+	}
+	return avg + (latency-avg)/latencySmoothing
+}
+
+// latencyMetric is a decimal (0.0, 1.0], which is higher for creators with a lower latency
+func (s *adaptiveLatencyStrategy) latencyMetric(id hash.Event) ancestor.Metric {
+	e := s.em.world.GetEvent(id)
+	if e == nil {
+		return 1
+	}
+	latency := s.peerLatency[e.Creator()]
+	return ancestor.Metric(piecefunc.DecimalUnit * uint64(time.Millisecond) / uint64(latency+time.Millisecond))
+}
+
+func (s *adaptiveLatencyStrategy) SearchStrategies(maxParents idx.Event) []ancestor.SearchStrategy {
+	return s.em.buildSearchStrategies(maxParents, ancestor.NewMetricStrategy(s.latencyMetric))
+}
+
+// adaptedIntervals returns emit intervals, such that ConfirmationEvents self-events are emitted during the confirmation latency.
+// Min and Confirming intervals may be only increased, not more than MaxSlowdown times.
+func (s *adaptiveLatencyStrategy) adaptedIntervals() EmitIntervals {
+	intervals := s.em.intervals
+	if s.confirmationLatency == 0 || s.cfg.ConfirmationEvents == 0 || intervals.Min == 0 {
+		return intervals
+	}
+	adjustedMin := s.confirmationLatency / time.Duration(s.cfg.ConfirmationEvents)
+	if maxMin := intervals.Min * time.Duration(s.cfg.MaxSlowdown); adjustedMin > maxMin {
+		adjustedMin = maxMin
+	}
+	if adjustedMin > intervals.Max {
+		adjustedMin = intervals.Max
+	}
+	if adjustedMin <= intervals.Min {
+		return intervals
+	}
+	intervals.Confirming = time.Duration(float64(intervals.Confirming) * float64(adjustedMin) / float64(intervals.Min))
+	intervals.Min = adjustedMin
+	return intervals
+}
+
+func (s *adaptiveLatencyStrategy) IsAllowedToEmit(e inter.EventI, eTxs bool, metric ancestor.Metric, selfParent *inter.Event) bool {
+	return s.em.isAllowedToEmit(s.adaptedIntervals(), e, eTxs, metric, selfParent)
+}
+
+func (s *adaptiveLatencyStrategy) OnEventConnected(e inter.EventPayloadI) {
+	if e.Creator() == s.em.config.Validator.ID {
+		return
+	}
+	s.peerLatency[e.Creator()] = observeLatency(s.peerLatency[e.Creator()], e.CreationTime(), s.em.clock())
+}
+
+func (s *adaptiveLatencyStrategy) OnEventConfirmed(e inter.EventI) {
+	if e.Creator() != s.em.config.Validator.ID {
+		return
+	}
+	s.confirmationLatency = observeLatency(s.confirmationLatency, e.CreationTime(), s.em.clock())
+}
@@ -0,0 +1,138 @@
+package emitter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter/mock"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/vecmt"
+)
+
+func TestStrategies(t *testing.T) {
+	require := require.New(t)
+
+	require.True(HasStrategy(""))
+	require.True(HasStrategy(DefaultStrategy))
+	require.True(HasStrategy(AdaptiveLatencyStrategy))
+	require.False(HasStrategy("unknown"))
+
+	cfg := DefaultConfig()
+	cfg.Strategy = "unknown"
+	em := NewEmitter(cfg, World{})
+	require.IsType(&defaultStrategy{}, em.strategy)
+
+	RegisterStrategy("custom", func(em *Emitter) Strategy {
+		return NewAdaptiveLatencyStrategy(em)
+	})
+	t.Cleanup(func() {
+		delete(strategies, "custom")
+	})
+	require.True(HasStrategy("custom"))
+	cfg.Strategy = "custom"
+	em = NewEmitter(cfg, World{})
+	require.IsType(&adaptiveLatencyStrategy{}, em.strategy)
+}
+
+func TestAdaptiveLatencyStrategy(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.Strategy = AdaptiveLatencyStrategy
+	cfg.Validator.ID = 1
+	vv := pos.NewBuilder()
+	for v := idx.ValidatorID(1); v <= 3; v++ {
+		vv.Set(v, pos.Weight(1))
+	}
+	validators := vv.Build()
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(skyhigh.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(validators, idx.Epoch(1)).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(idx.Epoch(1), cfg.Validator.ID).
+		Return((*hash.Event)(nil)).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(uint64(time.Now().UnixNano()))).
+		AnyTimes()
+
+	events := make(map[hash.Event]*inter.Event)
+	external.EXPECT().GetEvent(gomock.Any()).
+		DoAndReturn(func(id hash.Event) *inter.Event {
+			return events[id]
+		}).
+		AnyTimes()
+
+	em := NewEmitter(cfg, World{External: external})
+	em.init()
+	s := em.strategy.(*adaptiveLatencyStrategy)
+
+	newEvent := func(creator idx.ValidatorID, seq idx.Event, age time.Duration) *inter.EventPayload {
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(1)
+		me.SetCreator(creator)
+		me.SetSeq(seq)
+		me.SetLamport(idx.Lamport(seq))
+		me.SetParents(hash.Events{})
+		me.SetCreationTime(inter.Timestamp(time.Now().Add(-age).UnixNano()))
+		e := me.Build()
+		events[e.ID()] = &e.Event
+		return e
+	}
+
+	// intervals aren't adapted until confirmation latency is observed
+	require.Equal(em.intervals, s.adaptedIntervals())
+
+	// validator 2 is slow, validator 3 is fast
+	slow := newEvent(2, 1, 500*time.Millisecond)
+	fast := newEvent(3, 1, 10*time.Millisecond)
+	s.OnEventConnected(slow)
+	s.OnEventConnected(fast)
+	// self-events aren't taken into account
+	s.OnEventConnected(newEvent(1, 1, time.Hour))
+	require.Len(s.peerLatency, 2)
+	require.True(s.peerLatency[2] > s.peerLatency[3])
+
+	strategies := s.SearchStrategies(4)
+	require.Len(strategies, 4)
+	for _, options := range []hash.Events{{slow.ID(), fast.ID()}, {fast.ID(), slow.ID()}} {
+		best := strategies[1].Choose(hash.Events{}, options)
+		require.Equal(fast.ID(), options[best])
+	}
+
+	// events of other validators don't affect confirmation latency
+	s.OnEventConfirmed(newEvent(2, 2, time.Hour))
+	require.Equal(time.Duration(0), s.confirmationLatency)
+
+	// slow confirmation slows down emission
+	s.OnEventConfirmed(newEvent(1, 2, 2*time.Second))
+	intervals := s.adaptedIntervals()
+	require.InDelta(float64(2*time.Second/8), float64(intervals.Min), float64(50*time.Millisecond))
+	require.True(intervals.Confirming > em.intervals.Confirming)
+	require.Equal(em.intervals.Max, intervals.Max)
+
+	// slowdown is limited
+	s.OnEventConfirmed(newEvent(1, 3, time.Hour))
+	intervals = s.adaptedIntervals()
+	require.Equal(em.intervals.Min*time.Duration(cfg.AdaptiveLatency.MaxSlowdown), intervals.Min)
+
+	// fast confirmation doesn't speed up emission
+	s.confirmationLatency = 0
+	s.OnEventConfirmed(newEvent(1, 4, 100*time.Millisecond))
+	require.Equal(em.intervals, s.adaptedIntervals())
+}
@@ -22,8 +22,12 @@ type syncStatus struct {
 }
 
 func (em *Emitter) onNewExternalEvent(e inter.EventPayloadI) {
-	em.syncStatus.externalSelfEventDetected = time.Now()
+	em.syncStatus.externalSelfEventDetected = em.clock()
 	em.syncStatus.externalSelfEventCreated = e.CreationTime().Time()
+	if em.maintenance.Paused {
+		em.onMaintenanceExternalEvent(e)
+		return
+	}
 	status := em.currentSyncStatus()
 	if doublesign.DetectParallelInstance(status, em.config.EmitIntervals.ParallelInstanceProtection) {
 		passedSinceEvent := status.Since(status.ExternalSelfEventCreated)
@@ -40,7 +44,7 @@ func (em *Emitter) onNewExternalEvent(e inter.EventPayloadI) {
 
 func (em *Emitter) currentSyncStatus() doublesign.SyncStatus {
 	s := doublesign.SyncStatus{
-		Now:                       time.Now(),
+		Now:                       em.clock(),
 		PeersNum:                  em.world.PeersNum(),
 		Startup:                   em.syncStatus.startup,
 		LastConnected:             em.syncStatus.lastConnected,
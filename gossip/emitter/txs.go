@@ -24,6 +24,17 @@ const (
 	TxTurnNonces        = 32
 )
 
+// txsLimit is a reason why the transactions of an event are limited
+type txsLimit int
+
+const (
+	txsNotLimited txsLimit = iota
+	// txsLimitedTps means that the gas power is below LimitedTpsThreshold
+	txsLimitedTps
+	// txsNotAllowed means that the gas power is below NoTxsThreshold
+	txsNotAllowed
+)
+
 func max64(a, b uint64) uint64 {
 	if a > b {
 		return a
@@ -31,9 +42,10 @@ func max64(a, b uint64) uint64 {
 	return b
 }
 
-func (em *Emitter) maxGasPowerToUse(e *inter.MutableEventPayload) uint64 {
+func (em *Emitter) maxGasPowerToUse(e *inter.MutableEventPayload) (uint64, txsLimit) {
 	rules := em.world.GetRules()
 	maxGasToUse := rules.Economy.Gas.MaxEventGas
+	limit := txsNotLimited
 	if maxGasToUse > e.GasPowerLeft().Min() {
 		maxGasToUse = e.GasPowerLeft().Min()
 	}
@@ -53,7 +65,7 @@ func (em *Emitter) maxGasPowerToUse(e *inter.MutableEventPayload) uint64 {
 
 		gasPowerLeft := e.GasPowerLeft().Min() + estimatedAlloc
 		if gasPowerLeft < downThreshold {
-			return 0
+			return 0, txsNotAllowed
 		}
 		newGasPowerLeft := uint64(0)
 		if gasPowerLeft > maxGasToUse {
@@ -79,13 +91,14 @@ func (em *Emitter) maxGasPowerToUse(e *inter.MutableEventPayload) uint64 {
 		smoothGasToUse := healthyPart + trespassingPart/2
 		if maxGasToUse > smoothGasToUse {
 			maxGasToUse = smoothGasToUse
+			limit = txsLimitedTps
 		}
 	}
 	// pendingGas should be below MaxBlockGas
 	{
 		maxPendingGas := max64(rules.Blocks.MaxBlockGas*3/5, rules.Economy.Gas.MaxEventGas+rules.Economy.Gas.EventGas*uint64(em.validators.Len()))
 		if maxPendingGas <= em.pendingGas {
-			return 0
+			return 0, limit
 		}
 		if maxPendingGas < em.pendingGas+maxGasToUse {
 			maxGasToUse = maxPendingGas - em.pendingGas
@@ -95,12 +108,12 @@ func (em *Emitter) maxGasPowerToUse(e *inter.MutableEventPayload) uint64 {
 	{
 		threshold := em.config.NoTxsThreshold
 		if e.GasPowerLeft().Min() <= threshold {
-			return 0
+			return 0, txsNotAllowed
 		} else if e.GasPowerLeft().Min() < threshold+maxGasToUse {
 			maxGasToUse = e.GasPowerLeft().Min() - threshold
 		}
 	}
-	return maxGasToUse
+	return maxGasToUse, limit
 }
 
 // safe for concurrent use
@@ -108,7 +121,7 @@ func (em *Emitter) memorizeTxTimes(txs types.Transactions) {
 	if em.config.Validator.ID == 0 {
 		return // short circuit if not a validator
 	}
-	now := time.Now()
+	now := em.clock()
 	for _, tx := range txs {
 		_, ok := em.txTime.Get(tx.Hash())
 		if !ok {
@@ -128,7 +141,7 @@ func getTxRoundIndex(now, txTime time.Time, validatorsNum idx.Validator) int {
 func (em *Emitter) getTxTime(txHash common.Hash) time.Time {
 	txTimeI, ok := em.txTime.Get(txHash)
 	if !ok {
-		now := time.Now()
+		now := em.clock()
 		em.txTime.Add(txHash, now)
 		return now
 	}
@@ -150,10 +163,10 @@ func (em *Emitter) isMyTxTurn(txHash common.Hash, sender common.Address, account
 	return validators.GetID(idx.Validator(rounds[roundIndex])) == me
 }
 
-func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.TransactionsByPriceAndNonce) {
-	maxGasUsed := em.maxGasPowerToUse(e)
+func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.TransactionsByPriceAndNonce) txsLimit {
+	maxGasUsed, limit := em.maxGasPowerToUse(e)
 	if maxGasUsed <= e.GasPowerUsed() {
-		return
+		return limit
 	}
 
 	// sort transactions by price and nonce
@@ -186,7 +199,7 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 			continue
 		}
 		// my turn, i.e. try to not include the same tx simultaneously by different validators
-		if !em.isMyTxTurn(tx.Hash(), sender, tx.Nonce(), time.Now(), em.validators, e.Creator(), em.epoch) {
+		if !em.isMyTxTurn(tx.Hash(), sender, tx.Nonce(), em.clock(), em.validators, e.Creator(), em.epoch) {
 			sorted.Pop()
 			continue
 		}
@@ -201,4 +214,5 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 		e.SetTxs(append(e.Txs(), tx))
 		sorted.Shift()
 	}
+	return limit
 }
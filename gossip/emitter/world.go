@@ -14,6 +14,7 @@ import (
 
 	"github.com/skyhighblockchain/skyhigh/evmcore"
 	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
 	"github.com/skyhighblockchain/skyhigh/skyhigh"
 	"github.com/skyhighblockchain/skyhigh/valkeystore"
 	"github.com/skyhighblockchain/skyhigh/vecmt"
@@ -57,6 +58,7 @@ type (
 type Reader interface {
 	GetLatestBlockIndex() idx.Block
 	GetEpochValidators() (*pos.Validators, idx.Epoch)
+	GetEpochPubKeys() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch)
 	GetEvent(hash.Event) *inter.Event
 	GetEventPayload(hash.Event) *inter.EventPayload
 	GetLastEvent(epoch idx.Epoch, from idx.ValidatorID) *hash.Event
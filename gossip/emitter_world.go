@@ -10,6 +10,7 @@ import (
 
 	"github.com/skyhighblockchain/skyhigh/evmcore"
 	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
 	"github.com/skyhighblockchain/skyhigh/utils/wgmutex"
 	"github.com/skyhighblockchain/skyhigh/valkeystore"
 	"github.com/skyhighblockchain/skyhigh/vecmt"
@@ -67,6 +68,11 @@ func (ew *emitterWorld) GetHeads(epoch idx.Epoch) hash.Events {
 func (ew *emitterWorld) GetLastEvent(epoch idx.Epoch, from idx.ValidatorID) *hash.Event {
 	return ew.Store.GetLastEvent(epoch, from)
 }
+
+func (ew *emitterWorld) GetEpochPubKeys() (map[idx.ValidatorID]validatorpk.PubKey, idx.Epoch) {
+	return ew.s.heavyCheckReader.GetEpochPubKeys()
+}
+
 func (ew *emitterWorld) GetRecommendedGasPrice() *big.Int {
 	return ew.s.GetEvmStateReader().RecommendedMinGasPrice()
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
 	"github.com/skyhighblockchain/skyhigh/gossip/sfcapi"
 	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/dagexport"
 	"github.com/skyhighblockchain/skyhigh/inter/drivertype"
 	"github.com/skyhighblockchain/skyhigh/skyhigh"
 	"github.com/skyhighblockchain/skyhigh/topicsdb"
@@ -51,6 +52,14 @@ func (b *EthAPIBackend) CurrentBlock() *evmcore.EvmBlock {
 }
 
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	if number >= 0 && b.checkHistory(idx.Block(number)) != nil {
+		// headers of pruned blocks are still available, but without the txs root
+		header := b.state.GetHeader(common.Hash{}, uint64(number))
+		if header == nil {
+			return nil, nil
+		}
+		return header, nil
+	}
 	blk, err := b.BlockByNumber(ctx, number)
 	if err != nil {
 		return nil, err
@@ -81,12 +90,33 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		blk = b.state.CurrentBlock()
 	} else {
 		n := uint64(number.Int64())
+		if err := b.checkHistory(idx.Block(n)); err != nil {
+			return nil, err
+		}
 		blk = b.state.GetBlock(common.Hash{}, n)
 	}
 
 	return blk, nil
 }
 
+// checkHistory returns ErrHistoryPruned if receipts and txs of the block are pruned.
+func (b *EthAPIBackend) checkHistory(n idx.Block) error {
+	start := b.svc.store.GetHistoryStart()
+	if n < start {
+		return fmt.Errorf("%w: first available block is %d", evmcore.ErrHistoryPruned, start)
+	}
+	return nil
+}
+
+// checkEpochHistory returns ErrHistoryPruned if events of the epoch are pruned.
+func (b *EthAPIBackend) checkEpochHistory(epoch idx.Epoch) error {
+	start := b.svc.store.GetHistoryEpochStart()
+	if epoch < start {
+		return fmt.Errorf("%w: first available epoch is %d", evmcore.ErrHistoryPruned, start)
+	}
+	return nil
+}
+
 func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
 	var header *evmcore.EvmHeader
 	if number, ok := blockNrOrHash.Number(); ok && (number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber) {
@@ -162,6 +192,9 @@ func (b *EthAPIBackend) GetEventPayload(ctx context.Context, shortEventID string
 	if err != nil {
 		return nil, err
 	}
+	if err := b.checkEpochHistory(id.Epoch()); err != nil {
+		return nil, err
+	}
 	return b.svc.store.GetEventPayload(id), nil
 }
 
@@ -171,6 +204,9 @@ func (b *EthAPIBackend) GetEvent(ctx context.Context, shortEventID string) (*int
 	if err != nil {
 		return nil, err
 	}
+	if err := b.checkEpochHistory(id.Epoch()); err != nil {
+		return nil, err
+	}
 	return b.svc.store.GetEvent(id), nil
 }
 
@@ -223,11 +259,47 @@ func (b *EthAPIBackend) ForEachEpochEvent(ctx context.Context, epoch rpc.BlockNu
 	if err != nil {
 		return err
 	}
+	if err := b.checkEpochHistory(requested); err != nil {
+		return err
+	}
 
 	b.svc.store.ForEachEpochEvent(requested, onEvent)
 	return nil
 }
 
+// GetDoubleSigns returns pairs of conflicting events of the epoch, which prove validators' forks.
+// * When epoch is -2 the pairs for latest epoch are returned.
+// * When epoch is -1 the pairs for latest sealed epoch are returned.
+func (b *EthAPIBackend) GetDoubleSigns(ctx context.Context, epoch rpc.BlockNumber) ([]inter.DoubleSign, error) {
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkEpochHistory(requested); err != nil {
+		return nil, err
+	}
+
+	b.svc.engineMu.RLock() // lock because of iteration
+	defer b.svc.engineMu.RUnlock()
+
+	return b.svc.store.FindDoubleSigns(requested), nil
+}
+
+func (b *EthAPIBackend) GetDagGraph(ctx context.Context, epoch rpc.BlockNumber, from, to idx.Lamport, limit int) (*dagexport.Graph, error) {
+	requested, err := b.epochWithDefault(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.checkEpochHistory(requested); err != nil {
+		return nil, err
+	}
+
+	b.svc.engineMu.RLock() // lock because of iteration
+	defer b.svc.engineMu.RUnlock()
+
+	return b.svc.store.GetDagGraph(requested, from, to, limit)
+}
+
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcore.EvmBlock, error) {
 	index := b.svc.store.GetBlockIndex(hash.Event(h))
 	if index == nil {
@@ -243,6 +315,9 @@ func (b *EthAPIBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcor
 		blk = b.state.CurrentBlock()
 	} else {
 		n := uint64(*index)
+		if err := b.checkHistory(idx.Block(n)); err != nil {
+			return nil, err
+		}
 		blk = b.state.GetBlock(common.Hash{}, n)
 	}
 
@@ -252,7 +327,7 @@ func (b *EthAPIBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcor
 // GetReceiptsByNumber returns receipts by block number.
 func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error) {
 	if !b.svc.config.TxIndex {
-		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAGs)")
+		return nil, errors.New("transactions index is disabled (enable TxIndex and run db reindex)")
 	}
 
 	if number == rpc.PendingBlockNumber {
@@ -262,6 +337,9 @@ func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.Bloc
 		header := b.state.CurrentHeader()
 		number = rpc.BlockNumber(header.Number.Uint64())
 	}
+	if err := b.checkHistory(idx.Block(number)); err != nil {
+		return nil, err
+	}
 
 	receipts := b.svc.store.evm.GetReceipts(idx.Block(number))
 	block := b.state.GetBlock(common.Hash{}, uint64(number))
@@ -349,13 +427,16 @@ func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
 	if !b.svc.config.TxIndex {
-		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and run db reindex)")
 	}
 
 	position := b.svc.store.evm.GetTxPosition(txHash)
 	if position == nil {
 		return nil, 0, 0, nil
 	}
+	if err := b.checkHistory(position.Block); err != nil {
+		return nil, 0, 0, err
+	}
 
 	var tx *types.Transaction
 	if position.Event.IsZero() {
@@ -442,6 +523,11 @@ func (b *EthAPIBackend) EvmLogIndex() *topicsdb.Index {
 	return b.svc.store.evm.EvmLogs()
 }
 
+// HistoryStart returns the first block, which receipts and logs aren't pruned.
+func (b *EthAPIBackend) HistoryStart() idx.Block {
+	return b.svc.store.GetHistoryStart()
+}
+
 // CurrentEpoch returns current epoch number.
 func (b *EthAPIBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
 	return b.svc.store.GetEpoch()
@@ -614,3 +700,52 @@ func (b *EthAPIBackend) SealedEpochTiming(ctx context.Context) (start inter.Time
 	es := b.svc.store.GetEpochState()
 	return es.PrevEpochStart, es.EpochStart
 }
+
+// GetValidatorsHealth returns liveness summary of all the current epoch validators.
+func (b *EthAPIBackend) GetValidatorsHealth(ctx context.Context) (*ethapi.ValidatorsHealth, error) {
+	b.svc.engineMu.Lock() // lock because of vector clock access
+	// Note: loads bs and es atomically to avoid a race condition
+	bs, es := b.svc.store.GetBlockEpochState()
+	cheaters := b.svc.dagIndexer.Cheaters()
+	lasts := b.svc.store.GetLastEvents(es.Epoch)
+	b.svc.engineMu.Unlock()
+
+	lastEvents := make(map[idx.ValidatorID]hash.Event, es.Validators.Len())
+	if lasts != nil {
+		lasts.RLock()
+		for vid, id := range lasts.Val {
+			lastEvents[vid] = id
+		}
+		lasts.RUnlock()
+	}
+	isCheater := make(map[idx.ValidatorID]bool, len(cheaters))
+	for _, vid := range cheaters {
+		isCheater[vid] = true
+	}
+
+	res := &ethapi.ValidatorsHealth{
+		Epoch:         es.Epoch,
+		LastBlock:     bs.LastBlock.Idx,
+		LastBlockTime: bs.LastBlock.Time,
+		Validators:    make([]ethapi.ValidatorHealth, 0, es.Validators.Len()),
+	}
+	for _, vid := range es.Validators.SortedIDs() {
+		vs := bs.GetValidatorState(vid, es.Validators)
+		health := ethapi.ValidatorHealth{
+			ID:      vid,
+			Weight:  es.Validators.Get(vid),
+			Cheater: isCheater[vid],
+		}
+		if id, ok := lastEvents[vid]; ok {
+			health.LastEvent = b.svc.store.GetEvent(id)
+		}
+		if bs.LastBlock.Idx > vs.LastBlock {
+			health.BlocksMissed = bs.LastBlock.Idx - vs.LastBlock
+		}
+		if bs.LastBlock.Time > vs.LastOnlineTime {
+			health.MissedTime = bs.LastBlock.Time - vs.LastOnlineTime
+		}
+		res.Validators = append(res.Validators, health)
+	}
+	return res, nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/skyhighblockchain/skyhigh/logger"
 	"github.com/skyhighblockchain/skyhigh/topicsdb"
 	"github.com/skyhighblockchain/skyhigh/utils/adapters/kvdb2ethdb"
+	"github.com/skyhighblockchain/skyhigh/utils/ancient"
 	"github.com/skyhighblockchain/skyhigh/utils/rlpstore"
 )
 
@@ -35,6 +36,12 @@ type Store struct {
 		Receipts    kvdb.Store `table:"r"`
 		TxPositions kvdb.Store `table:"x"`
 		Txs         kvdb.Store `table:"X"`
+		// AncientTxs maps a non-event tx hash to the block number of the tx in the ancient store
+		AncientTxs kvdb.Store `table:"A"`
+		// AddressTxs maps an address to the positions of txs, which touched the address
+		AddressTxs kvdb.Store `table:"T"`
+		// Logs keeps the logs index
+		Logs kvdb.Store `table:"L"`
 
 		Evm      ethdb.Database
 		EvmState state.Database
@@ -48,6 +55,11 @@ type Store struct {
 		EvmBlocks   *wlru.Cache `cache:"-"` // store by pointer
 	}
 
+	ancient struct {
+		Receipts *ancient.Table
+		Txs      *ancient.Table
+	}
+
 	mutex struct {
 		Inc sync.Mutex
 	}
@@ -76,7 +88,7 @@ func NewStore(mainDB kvdb.Store, cfg StoreConfig) *Store {
 		Cache:     cfg.Cache.EvmDatabase / opt.MiB,
 		Preimages: cfg.EnablePreimageRecording,
 	})
-	s.table.EvmLogs = topicsdb.New(table.New(s.mainDB, []byte("L")))
+	s.table.EvmLogs = topicsdb.New(s.table.Logs)
 
 	s.initCache()
 
@@ -126,10 +138,38 @@ func (s *Store) IndexLogs(recs ...*types.Log) {
 	}
 }
 
+// DelLogs deletes EVM logs from the index
+func (s *Store) DelLogs(recs ...*types.Log) {
+	err := s.table.EvmLogs.Delete(recs...)
+	if err != nil {
+		s.Log.Crit("DB logs index error", "err", err)
+	}
+}
+
 func (s *Store) EvmKvdbTable() kvdb.Store {
 	return table.New(s.mainDB, []byte("M"))
 }
 
+// KvTables are the key-value tables of the store, which aren't managed by the EVM
+type KvTables struct {
+	Receipts    kvdb.Store
+	Logs        kvdb.Store
+	TxPositions kvdb.Store
+	Txs         kvdb.Store
+	AncientTxs  kvdb.Store
+}
+
+// KvTables returns the key-value tables of the store, e.g. to back them up
+func (s *Store) KvTables() KvTables {
+	return KvTables{
+		Receipts:    s.table.Receipts,
+		Logs:        s.table.Logs,
+		TxPositions: s.table.TxPositions,
+		Txs:         s.table.Txs,
+		AncientTxs:  s.table.AncientTxs,
+	}
+}
+
 func (s *Store) EvmTable() ethdb.Database {
 	return s.table.Evm
 }
@@ -0,0 +1,88 @@
+package evmstore
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/skyhighblockchain/push-base/common/bigendian"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+)
+
+// AddressTxFlags describe how a tx touched an address
+type AddressTxFlags uint8
+
+const (
+	// AddressTxFrom is set if the address is the tx sender
+	AddressTxFrom AddressTxFlags = 1 << iota
+	// AddressTxTo is set if the address is the tx recipient or the created contract
+	AddressTxTo
+	// AddressTxTransferFrom is set if the address is a sender of the value transfer logged in tx receipt
+	AddressTxTransferFrom
+	// AddressTxTransferTo is set if the address is a recipient of the value transfer logged in tx receipt
+	AddressTxTransferTo
+)
+
+const addressTxKeySize = common.AddressLength + 8 + 4
+
+// AddressTx is a position of tx, which touched an address
+type AddressTx struct {
+	Block       idx.Block
+	BlockOffset uint32
+	TxHash      common.Hash
+	Flags       AddressTxFlags
+}
+
+func addressTxKey(addr common.Address, n idx.Block, offset uint32) []byte {
+	key := make([]byte, 0, addressTxKeySize)
+	key = append(key, addr.Bytes()...)
+	key = append(key, n.Bytes()...)
+	key = append(key, bigendian.Uint32ToBytes(offset)...)
+	return key
+}
+
+// SetAddressTx stores the position of tx, which touched the address.
+func (s *Store) SetAddressTx(addr common.Address, tx AddressTx) {
+	val := make([]byte, 0, 1+common.HashLength)
+	val = append(val, byte(tx.Flags))
+	val = append(val, tx.TxHash.Bytes()...)
+	if err := s.table.AddressTxs.Put(addressTxKey(addr, tx.Block, tx.BlockOffset), val); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// DelAddressTx deletes the position of tx, which touched the address.
+func (s *Store) DelAddressTx(addr common.Address, n idx.Block, offset uint32) {
+	if err := s.table.AddressTxs.Delete(addressTxKey(addr, n, offset)); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+// ForEachAddressTx iterates over txs, which touched the address, starting from the given block position, in the execution order.
+func (s *Store) ForEachAddressTx(addr common.Address, from idx.Block, fromOffset uint32, onTx func(AddressTx) bool) {
+	start := addressTxKey(addr, from, fromOffset)
+	it := s.table.AddressTxs.NewIterator(addr.Bytes(), start[common.AddressLength:])
+	defer it.Release()
+	for it.Next() {
+		key, val := it.Key(), it.Value()
+		if len(key) != addressTxKeySize || len(val) != 1+common.HashLength {
+			s.Log.Crit("Address tx record has wrong size", "key", len(key), "value", len(val))
+		}
+		tx := AddressTx{
+			Block:       idx.BytesToBlock(key[common.AddressLength : common.AddressLength+8]),
+			BlockOffset: bigendian.BytesToUint32(key[common.AddressLength+8:]),
+			TxHash:      common.BytesToHash(val[1:]),
+			Flags:       AddressTxFlags(val[0]),
+		}
+		if !onTx(tx) {
+			break
+		}
+	}
+	if it.Error() != nil {
+		s.Log.Crit("Failed to iterate address txs", "err", it.Error())
+	}
+}
+
+// HasAddressTxs returns true if any address txs are indexed.
+func (s *Store) HasAddressTxs() bool {
+	it := s.table.AddressTxs.NewIterator(nil, nil)
+	defer it.Release()
+	return it.Next()
+}
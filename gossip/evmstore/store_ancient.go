@@ -0,0 +1,137 @@
+package evmstore
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/utils/ancient"
+)
+
+const (
+	// AncientReceiptsTable is a name of the ancient table of block receipts
+	AncientReceiptsTable = "receipts"
+	// AncientTxsTable is a name of the ancient table of non-event block txs
+	AncientTxsTable = "txs"
+)
+
+// SetAncient sets the ancient store, which serves receipts and non-event txs of old blocks.
+// The store must contain AncientReceiptsTable and AncientTxsTable.
+func (s *Store) SetAncient(a *ancient.Store) {
+	s.ancient.Receipts = a.Table(AncientReceiptsTable)
+	s.ancient.Txs = a.Table(AncientTxsTable)
+}
+
+// AppendAncient appends the block receipts and the non-event block txs to the ancient store.
+// Blocks must be appended sequentially, an already appended block is skipped.
+func (s *Store) AppendAncient(n idx.Block, txids []common.Hash) error {
+	if !isAppended(s.ancient.Receipts, n) {
+		receipts, err := s.table.Receipts.Get(n.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := s.ancient.Receipts.Append(uint64(n), receipts); err != nil {
+			return err
+		}
+	}
+	if !isAppended(s.ancient.Txs, n) {
+		txs := make([]rlp.RawValue, 0, len(txids))
+		for _, txid := range txids {
+			tx, err := s.table.Txs.Get(txid.Bytes())
+			if err != nil {
+				return err
+			}
+			if tx != nil {
+				txs = append(txs, tx)
+			}
+		}
+		txsRLP, err := rlp.EncodeToBytes(txs)
+		if err != nil {
+			return err
+		}
+		if err := s.ancient.Txs.Append(uint64(n), txsRLP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TruncateAncient drops the receipts and the non-event txs of the blocks since n from the ancient store.
+// Used to roll back the ancient store along with the key-value DB.
+func (s *Store) TruncateAncient(n idx.Block) error {
+	if s.ancient.Receipts == nil {
+		return nil
+	}
+	if err := s.ancient.Receipts.TruncateHead(uint64(n)); err != nil {
+		return err
+	}
+	return s.ancient.Txs.TruncateHead(uint64(n))
+}
+
+// isAppended returns true if the block was appended, but wasn't pruned from the key-value DB because of an interruption
+func isAppended(t *ancient.Table, n idx.Block) bool {
+	return t.Items() != 0 && uint64(n) < t.Head()
+}
+
+// PruneAncient deletes the receipts and the non-event txs of an appended block from the key-value DB.
+// Must be called after the ancient store is synced.
+func (s *Store) PruneAncient(n idx.Block, txids []common.Hash) {
+	for _, txid := range txids {
+		has, err := s.table.Txs.Has(txid.Bytes())
+		if err != nil {
+			s.Log.Crit("Failed to get key-value", "err", err)
+		}
+		if !has {
+			continue
+		}
+		if err := s.table.AncientTxs.Put(txid.Bytes(), n.Bytes()); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+		if err := s.table.Txs.Delete(txid.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+func (s *Store) getAncientReceiptsRLP(n idx.Block) []byte {
+	if s.ancient.Receipts == nil || !s.ancient.Receipts.Has(uint64(n)) {
+		return nil
+	}
+	buf, err := s.ancient.Receipts.Retrieve(uint64(n))
+	if err != nil {
+		s.Log.Crit("Failed to read ancient receipts", "block", n, "err", err)
+	}
+	return buf
+}
+
+func (s *Store) getAncientTx(txid common.Hash) *types.Transaction {
+	if s.ancient.Txs == nil {
+		return nil
+	}
+	nBytes, err := s.table.AncientTxs.Get(txid.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if nBytes == nil {
+		return nil
+	}
+	n := idx.BytesToBlock(nBytes)
+	buf, err := s.ancient.Txs.Retrieve(uint64(n))
+	if err != nil {
+		s.Log.Crit("Failed to read ancient txs", "block", n, "err", err)
+	}
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(buf, &txs); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(buf))
+	}
+	for _, tx := range txs {
+		if tx.Hash() == txid {
+			return tx
+		}
+	}
+	return nil
+}
@@ -40,6 +40,16 @@ func (s *Store) SetRawReceipts(n idx.Block, receipts []*types.ReceiptForStorage)
 	return len(buf)
 }
 
+// DelReceipts deletes transaction receipts.
+func (s *Store) DelReceipts(n idx.Block) {
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	// Remove from LRU cache.
+	s.cache.Receipts.Remove(n)
+}
+
 // GetReceipts returns stored transaction receipts.
 func (s *Store) GetReceipts(n idx.Block) types.Receipts {
 	// Get data from LRU cache first.
@@ -54,11 +64,23 @@ func (s *Store) GetReceipts(n idx.Block) types.Receipts {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
 	if buf == nil {
+		buf = s.getAncientReceiptsRLP(n)
+	}
+	if len(buf) == 0 {
 		return nil
 	}
 
+	receipts := s.decodeReceipts(buf)
+
+	// Add to LRU cache.
+	s.cache.Receipts.Add(n, receipts, uint(len(buf)))
+
+	return receipts
+}
+
+func (s *Store) decodeReceipts(buf []byte) types.Receipts {
 	var receiptsStorage *[]*types.ReceiptForStorage
-	err = rlp.DecodeBytes(buf, &receiptsStorage)
+	err := rlp.DecodeBytes(buf, &receiptsStorage)
 	if err != nil {
 		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(buf))
 	}
@@ -72,9 +94,5 @@ func (s *Store) GetReceipts(n idx.Block) types.Receipts {
 		}
 		receipts[i].GasUsed = receipts[i].CumulativeGasUsed - prev
 	}
-
-	// Add to LRU cache.
-	s.cache.Receipts.Add(n, receipts, uint(len(buf)))
-
 	return receipts
 }
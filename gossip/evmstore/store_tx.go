@@ -13,6 +13,9 @@ func (s *Store) SetTx(txid common.Hash, tx *types.Transaction) {
 // GetTx returns stored non-event transaction.
 func (s *Store) GetTx(txid common.Hash) *types.Transaction {
 	tx, _ := s.rlp.Get(s.table.Txs, txid.Bytes(), &types.Transaction{}).(*types.Transaction)
+	if tx == nil {
+		tx = s.getAncientTx(txid)
+	}
 
 	return tx
 }
@@ -32,6 +32,8 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/topicsdb"
 )
 
 var (
@@ -55,12 +57,16 @@ type Config struct {
 	IndexedLogsBlockRangeLimit idx.Block
 	// Block range limit for logs search (unindexed).
 	UnindexedLogsBlockRangeLimit idx.Block
+	// Max number of logs returned by a logs search (0 = no limit).
+	// It's also the max size of a logs page.
+	LogsResultsLimit int
 }
 
 func DefaultConfig() Config {
 	return Config{
 		IndexedLogsBlockRangeLimit:   999999999999999999,
 		UnindexedLogsBlockRangeLimit: 100,
+		LogsResultsLimit:             10000,
 	}
 }
 
@@ -338,31 +344,81 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getlogs
 func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
-	var filter *Filter
-	if crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(api.backend, api.config, *crit.BlockHash, crit.Addresses, crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if crit.FromBlock != nil {
-			begin = crit.FromBlock.Int64()
-		}
-		end := rpc.LatestBlockNumber.Int64()
-		if crit.ToBlock != nil {
-			end = crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(api.backend, api.config, begin, end, crit.Addresses, crit.Topics)
-	}
 	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
+	logs, err := api.newFilter(crit).Logs(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return returnLogs(logs), err
 }
 
+// LogsCursor points to the last log of a logs page.
+type LogsCursor struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	Index       hexutil.Uint   `json:"logIndex"`
+}
+
+// LogsPage is a page of logs, ordered by block number, tx hash and log index.
+// Next is nil if there're no more logs.
+type LogsPage struct {
+	Logs []*types.Log `json:"logs"`
+	Next *LogsCursor  `json:"next"`
+}
+
+// GetLogsPage returns up to limit logs matching the given argument, which are located after the cursor (if not nil).
+// The returned cursor may be used to get the next page.
+func (api *PublicFilterAPI) GetLogsPage(ctx context.Context, crit FilterCriteria, cursor *LogsCursor, limit *hexutil.Uint) (*LogsPage, error) {
+	pageLimit := api.config.LogsResultsLimit
+	if limit != nil && (pageLimit <= 0 || int(*limit) < pageLimit) {
+		pageLimit = int(*limit)
+	}
+	if pageLimit <= 0 {
+		pageLimit = DefaultConfig().LogsResultsLimit
+	}
+
+	var after *topicsdb.ID
+	if cursor != nil {
+		id := topicsdb.NewID(uint64(cursor.BlockNumber), cursor.TxHash, uint(cursor.Index))
+		after = &id
+	}
+	logs, next, err := api.newFilter(crit).LogsPage(ctx, after, pageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LogsPage{
+		Logs: returnLogs(logs),
+	}
+	if next != nil {
+		page.Next = &LogsCursor{
+			BlockNumber: hexutil.Uint64(next.BlockNumber()),
+			TxHash:      next.TxHash(),
+			Index:       hexutil.Uint(next.Index()),
+		}
+	}
+	return page, nil
+}
+
+// newFilter constructs a single-shot filter by the criteria.
+func (api *PublicFilterAPI) newFilter(crit FilterCriteria) *Filter {
+	if crit.BlockHash != nil {
+		// Block filter requested, construct a single-shot filter
+		return NewBlockFilter(api.backend, api.config, *crit.BlockHash, crit.Addresses, crit.Topics)
+	}
+	// Convert the RPC block numbers into internal representations
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	// Construct the range filter
+	return NewRangeFilter(api.backend, api.config, begin, end, crit.Addresses, crit.Topics)
+}
+
 // UninstallFilter removes the filter with the given filter id.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_uninstallfilter
@@ -393,23 +449,7 @@ func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ty
 		return nil, fmt.Errorf("filter not found")
 	}
 
-	var filter *Filter
-	if f.crit.BlockHash != nil {
-		// Block filter requested, construct a single-shot filter
-		filter = NewBlockFilter(api.backend, api.config, *f.crit.BlockHash, f.crit.Addresses, f.crit.Topics)
-	} else {
-		// Convert the RPC block numbers into internal representations
-		begin := rpc.LatestBlockNumber.Int64()
-		if f.crit.FromBlock != nil {
-			begin = f.crit.FromBlock.Int64()
-		}
-		end := rpc.LatestBlockNumber.Int64()
-		if f.crit.ToBlock != nil {
-			end = f.crit.ToBlock.Int64()
-		}
-		// Construct the range filter
-		filter = NewRangeFilter(api.backend, api.config, begin, end, f.crit.Addresses, f.crit.Topics)
-	}
+	filter := api.newFilter(f.crit)
 	// Run the filter and return all the logs
 	logs, err := filter.Logs(ctx)
 	if err != nil {
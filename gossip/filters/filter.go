@@ -17,12 +17,15 @@
 package filters
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -47,6 +50,39 @@ type Backend interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) notify.Subscription
 
 	EvmLogIndex() *topicsdb.Index
+	HistoryStart() idx.Block
+}
+
+// LimitError is returned if a logs search exceeds the configured limits.
+// From and To are the suggested blocks range, which fits into the limits.
+// Paginate is true if no blocks range fits into the limits, i.e. only the paginated query may return the logs.
+type LimitError struct {
+	Message  string
+	From, To idx.Block
+	Paginate bool
+}
+
+func (e *LimitError) Error() string {
+	if e.Paginate {
+		return e.Message
+	}
+	return fmt.Sprintf("%s, try with the blocks range [%d, %d]", e.Message, e.From, e.To)
+}
+
+// ErrorCode returns the JSON-RPC error code of exceeded limit.
+func (e *LimitError) ErrorCode() int {
+	return -32005
+}
+
+// ErrorData returns the suggested blocks range.
+func (e *LimitError) ErrorData() interface{} {
+	if e.Paginate {
+		return nil
+	}
+	return map[string]hexutil.Uint64{
+		"fromBlock": hexutil.Uint64(e.From),
+		"toBlock":   hexutil.Uint64(e.To),
+	}
 }
 
 // Filter can be used to retrieve and filter logs.
@@ -99,7 +135,52 @@ func newFilter(backend Backend, cfg Config, addresses []common.Address, topics [
 
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
+// Returns LimitError if the number of matching logs exceeds the configured limit.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
+	limit := f.config.LogsResultsLimit
+	if limit <= 0 {
+		return f.allLogs(ctx)
+	}
+
+	// next is the first block, which logs aren't fully returned
+	var next idx.Block
+	if f.block != common.Hash(hash.Zero) || f.unindexed() {
+		// the unindexed search is bounded by the blocks range, and it keeps the logs in the blocks order
+		logs, err := f.allLogs(ctx)
+		if err != nil || len(logs) <= limit {
+			return logs, err
+		}
+		next = idx.Block(logs[limit].BlockNumber)
+	} else {
+		logs, last, err := f.LogsPage(ctx, nil, limit)
+		if err != nil || last == nil {
+			return logs, err
+		}
+		next = idx.Block(last.BlockNumber())
+	}
+
+	limitErr := &LimitError{
+		Message:  fmt.Sprintf("query returned more than %d results, use the paginated query", limit),
+		Paginate: true,
+	}
+	if f.block == common.Hash(hash.Zero) {
+		begin, _, err := f.blocksRange(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if next > begin {
+			// suggest the blocks range, which logs are fully returned
+			limitErr.Message = fmt.Sprintf("query returned more than %d results, narrow the blocks range or use the paginated query", limit)
+			limitErr.From = begin
+			limitErr.To = next - 1
+			limitErr.Paginate = false
+		}
+	}
+	return nil, limitErr
+}
+
+// allLogs returns all the matching logs with no limit on the number of results.
+func (f *Filter) allLogs(ctx context.Context) ([]*types.Log, error) {
 	// If we're doing singleton block filtering, execute and return
 	if f.block != common.Hash(hash.Zero) {
 		header, err := f.backend.HeaderByHash(ctx, f.block)
@@ -111,38 +192,112 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		}
 		return f.blockLogs(ctx, header.Hash)
 	}
+	begin, end, err := f.blocksRange(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if begin > end {
+		return []*types.Log{}, nil
+	}
+
+	if f.unindexed() {
+		return f.unindexedLogs(ctx, begin, end)
+	} else {
+		return f.indexedLogs(ctx, begin, end)
+	}
+}
+
+// LogsPage returns up to limit matching logs, which IDs are greater than after (if not nil).
+// Logs are ordered by their topicsdb IDs, i.e. by block number, tx hash and log index.
+// Returns ID of the last returned log if there're more matching logs, which may be passed as after for the next page.
+func (f *Filter) LogsPage(ctx context.Context, after *topicsdb.ID, limit int) ([]*types.Log, *topicsdb.ID, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("page limit has to be positive")
+	}
+	// If we're doing singleton block filtering, execute and return
+	if f.block != common.Hash(hash.Zero) {
+		header, err := f.backend.HeaderByHash(ctx, f.block)
+		if err != nil {
+			return nil, nil, err
+		}
+		if header == nil {
+			return nil, nil, errors.New("unknown block")
+		}
+		logs, err := f.blockLogs(ctx, header.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pageOfLogs(logs, after, limit)
+	}
+	begin, end, err := f.blocksRange(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if after != nil && idx.Block(after.BlockNumber()) > begin {
+		begin = idx.Block(after.BlockNumber())
+	}
+	if begin > end {
+		return []*types.Log{}, nil, nil
+	}
+
+	if f.unindexed() {
+		return f.unindexedLogsPage(ctx, begin, end, after, limit)
+	}
+	if err := f.checkRange(begin, end, f.config.IndexedLogsBlockRangeLimit); err != nil {
+		return nil, nil, err
+	}
+	logs, more, err := f.backend.EvmLogIndex().FindInBlocksPage(ctx, begin, end, after, f.pattern(), limit)
+	if err != nil || !more {
+		return logs, nil, err
+	}
+	return logs, logID(logs[len(logs)-1]), nil
+}
+
+// blocksRange returns the blocks range of the range filter.
+func (f *Filter) blocksRange(ctx context.Context) (begin, end idx.Block, err error) {
 	// Figure out the limits of the filter range
 	header, _ := f.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
 	if header == nil {
-		return nil, nil
+		return 1, 0, nil
 	}
 	head := idx.Block(header.Number.Uint64())
 
-	begin := idx.Block(f.begin)
+	begin = idx.Block(f.begin)
 	if f.begin < 0 {
 		begin = head
 	}
-	end := idx.Block(f.end)
+	end = idx.Block(f.end)
 	if f.end < 0 {
 		end = head
 	}
 	if begin > end {
-		return []*types.Log{}, nil
+		return begin, end, nil
 	}
-
-	if isEmpty(f.topics) && len(f.addresses) == 0 {
-		return f.unindexedLogs(ctx, begin, end)
-	} else {
-		return f.indexedLogs(ctx, begin, end)
+	if start := f.backend.HistoryStart(); begin < start {
+		return 0, 0, fmt.Errorf("%w: first available block is %d", evmcore.ErrHistoryPruned, start)
 	}
+	return begin, end, nil
 }
 
-// indexedLogs returns the logs matching the filter criteria based on topics index.
-func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
-	if end-begin > f.config.IndexedLogsBlockRangeLimit {
-		return nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.IndexedLogsBlockRangeLimit)
+// checkRange returns LimitError if the blocks range is too wide.
+func (f *Filter) checkRange(begin, end, limit idx.Block) error {
+	if end-begin > limit {
+		return &LimitError{
+			Message: fmt.Sprintf("too wide blocks range, the limit is %d", limit),
+			From:    begin,
+			To:      begin + limit,
+		}
 	}
+	return nil
+}
+
+// unindexed returns true if the filter has no criteria, so the logs index can't be used.
+func (f *Filter) unindexed() bool {
+	return isEmpty(f.topics) && len(f.addresses) == 0
+}
 
+// pattern returns the topicsdb search pattern of the filter.
+func (f *Filter) pattern() [][]common.Hash {
 	addresses := make([]common.Hash, len(f.addresses))
 	for i, addr := range f.addresses {
 		addresses[i] = addr.Hash()
@@ -151,8 +306,16 @@ func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*type
 	pattern := make([][]common.Hash, 1, len(f.topics)+1)
 	pattern[0] = addresses
 	pattern = append(pattern, f.topics...)
+	return pattern
+}
+
+// indexedLogs returns the logs matching the filter criteria based on topics index.
+func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*types.Log, error) {
+	if err := f.checkRange(begin, end, f.config.IndexedLogsBlockRangeLimit); err != nil {
+		return nil, err
+	}
 
-	logs, err := f.backend.EvmLogIndex().FindInBlocks(ctx, begin, end, pattern)
+	logs, err := f.backend.EvmLogIndex().FindInBlocks(ctx, begin, end, f.pattern())
 
 	return logs, err
 }
@@ -160,8 +323,8 @@ func (f *Filter) indexedLogs(ctx context.Context, begin, end idx.Block) ([]*type
 // indexedLogs returns the logs matching the filter criteria based on raw block
 // iteration.
 func (f *Filter) unindexedLogs(ctx context.Context, begin, end idx.Block) (logs []*types.Log, err error) {
-	if end-begin > f.config.UnindexedLogsBlockRangeLimit {
-		return nil, fmt.Errorf("too wide blocks range, the limit is %d", f.config.UnindexedLogsBlockRangeLimit)
+	if err = f.checkRange(begin, end, f.config.UnindexedLogsBlockRangeLimit); err != nil {
+		return
 	}
 
 	var (
@@ -187,6 +350,71 @@ func (f *Filter) unindexedLogs(ctx context.Context, begin, end idx.Block) (logs
 	return
 }
 
+// unindexedLogsPage returns a page of the logs matching the filter criteria based on raw block iteration.
+func (f *Filter) unindexedLogsPage(ctx context.Context, begin, end idx.Block, after *topicsdb.ID, limit int) ([]*types.Log, *topicsdb.ID, error) {
+	if err := f.checkRange(begin, end, f.config.UnindexedLogsBlockRangeLimit); err != nil {
+		return nil, nil, err
+	}
+
+	logs := make([]*types.Log, 0, limit)
+	for n := begin; n <= end; n++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(n))
+		if err != nil {
+			return nil, nil, err
+		}
+		if header == nil {
+			break
+		}
+		found, err := f.blockLogs(ctx, header.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		page, next, err := pageOfLogs(found, after, limit-len(logs))
+		if err != nil {
+			return nil, nil, err
+		}
+		logs = append(logs, page...)
+		if next != nil {
+			return logs, next, nil
+		}
+		if len(logs) == limit {
+			// the page is full, more logs may be in the next blocks
+			if n < end {
+				return logs, logID(logs[len(logs)-1]), nil
+			}
+			break
+		}
+	}
+	return logs, nil, nil
+}
+
+// pageOfLogs returns up to limit logs, which IDs are greater than after (if not nil), in the order of IDs.
+func pageOfLogs(logs []*types.Log, after *topicsdb.ID, limit int) ([]*types.Log, *topicsdb.ID, error) {
+	sorted := make([]*types.Log, 0, len(logs))
+	for _, l := range logs {
+		if after == nil || bytes.Compare(logID(l).Bytes(), after.Bytes()) > 0 {
+			sorted = append(sorted, l)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(logID(sorted[i]).Bytes(), logID(sorted[j]).Bytes()) < 0
+	})
+	if len(sorted) > limit {
+		return sorted[:limit], logID(sorted[limit-1]), nil
+	}
+	return sorted, nil, nil
+}
+
+// logID returns topicsdb ID of the log.
+func logID(l *types.Log) *topicsdb.ID {
+	id := topicsdb.NewID(l.BlockNumber, l.TxHash, l.Index)
+	return &id
+}
+
 // blockLogs returns the logs matching the filter criteria within a single block.
 func (f *Filter) blockLogs(ctx context.Context, header common.Hash) ([]*types.Log, error) {
 	// Get the logs of the block
@@ -32,6 +32,7 @@ import (
 	notify "github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/skyhighblockchain/push-base/inter/idx"
 	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 
@@ -46,6 +47,8 @@ type testBackend struct {
 	blocksFeed *notify.Feed
 	txsFeed    *notify.Feed
 	logsFeed   *notify.Feed
+
+	historyStart idx.Block
 }
 
 func newTestBackend() *testBackend {
@@ -135,6 +138,10 @@ func (b *testBackend) EvmLogIndex() *topicsdb.Index {
 	return b.logIndex
 }
 
+func (b *testBackend) HistoryStart() idx.Block {
+	return b.historyStart
+}
+
 // TestBlockSubscription tests if a block subscription returns block hashes for posted chain notify.
 // It creates multiple subscriptions:
 // - one at the start and should receive all posted chain events and a second (blockHashes)
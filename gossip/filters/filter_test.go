@@ -18,6 +18,7 @@ package filters
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"math/big"
 	"os"
@@ -32,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/skyhighblockchain/push-base/kvdb/table"
 
+	"github.com/skyhighblockchain/skyhigh/evmcore"
 	"github.com/skyhighblockchain/skyhigh/topicsdb"
 	"github.com/skyhighblockchain/skyhigh/utils/adapters/ethdb2kvdb"
 )
@@ -264,4 +266,131 @@ func TestFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 
+	// logs of the pruned blocks aren't available
+	backend.historyStart = 900
+	filter = NewRangeFilter(backend, testConfig(), 1, -1, []common.Address{addr}, [][]common.Hash{{hash3}})
+	_, err = filter.Logs(context.Background())
+	if !errors.Is(err, evmcore.ErrHistoryPruned) {
+		t.Error("expected history pruned error, got", err)
+	}
+	filter = NewRangeFilter(backend, testConfig(), 900, -1, []common.Address{addr}, [][]common.Hash{{hash3}})
+	logs, err = filter.Logs(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	if len(logs) != 1 {
+		t.Error("expected 1 log, got", len(logs))
+	}
+}
+
+func TestFilterLimits(t *testing.T) {
+	var (
+		backend = newTestBackend()
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key1.PublicKey)
+		topic   = common.BytesToHash([]byte("topic"))
+	)
+
+	genesis := core.GenesisBlockForTesting(backend.db, addr, big.NewInt(1000000))
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), backend.db, 20, func(i int, gen *core.BlockGen) {
+		for j := 0; j < 2; j++ {
+			receipt := types.NewReceipt(nil, false, 0)
+			receipt.Logs = []*types.Log{{Address: addr, Topics: []common.Hash{topic}}}
+			gen.AddUncheckedReceipt(receipt)
+			gen.AddUncheckedTx(types.NewTransaction(uint64(i*2+j), common.HexToAddress("0x1"), big.NewInt(1), 1, big.NewInt(1), nil))
+		}
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(backend.db, block)
+		rawdb.WriteCanonicalHash(backend.db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(backend.db, block.Hash())
+		rawdb.WriteReceipts(backend.db, block.Hash(), block.NumberU64(), receipts[i])
+		for _, r := range rawdb.ReadReceipts(backend.db, block.Hash(), block.NumberU64(), params.TestChainConfig) {
+			backend.logIndex.MustPush(r.Logs...)
+		}
+	}
+
+	cfg := testConfig()
+	cfg.LogsResultsLimit = 5
+	for _, addresses := range [][]common.Address{{addr}, nil} {
+		// too many results
+		_, err := NewRangeFilter(backend, cfg, 1, -1, addresses, nil).Logs(context.Background())
+		limitErr, ok := err.(*LimitError)
+		if !ok {
+			t.Fatal("expected limit error, got", err)
+		}
+		if limitErr.From != 1 || limitErr.To != 2 {
+			t.Errorf("expected suggested range [1, 2], got [%d, %d]", limitErr.From, limitErr.To)
+		}
+		logs, err := NewRangeFilter(backend, cfg, int64(limitErr.From), int64(limitErr.To), addresses, nil).Logs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(logs) != 4 {
+			t.Error("expected 4 logs, got", len(logs))
+		}
+
+		// no blocks range fits into the limit
+		narrowCfg := cfg
+		narrowCfg.LogsResultsLimit = 1
+		_, err = NewRangeFilter(backend, narrowCfg, 3, -1, addresses, nil).Logs(context.Background())
+		if limitErr, ok := err.(*LimitError); !ok || !limitErr.Paginate {
+			t.Error("expected pagination suggestion, got", err)
+		}
+		_, err = NewBlockFilter(backend, narrowCfg, chain[2].Hash(), addresses, nil).Logs(context.Background())
+		if limitErr, ok := err.(*LimitError); !ok || !limitErr.Paginate {
+			t.Error("expected pagination suggestion, got", err)
+		}
+
+		// unindexed logs are in the blocks order
+		if addresses == nil {
+			wideCfg := cfg
+			wideCfg.LogsResultsLimit = 100
+			logs, err = NewRangeFilter(backend, wideCfg, 1, -1, addresses, nil).Logs(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 1; i < len(logs); i++ {
+				if logs[i-1].BlockNumber > logs[i].BlockNumber || logs[i-1].BlockNumber == logs[i].BlockNumber && logs[i-1].Index > logs[i].Index {
+					t.Fatal("logs aren't ordered", i)
+				}
+			}
+		}
+
+		// pagination
+		var (
+			got   []*types.Log
+			after *topicsdb.ID
+			pages int
+		)
+		for {
+			page, next, err := NewRangeFilter(backend, cfg, 1, -1, addresses, nil).LogsPage(context.Background(), after, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, page...)
+			pages++
+			if next == nil {
+				break
+			}
+			after = next
+		}
+		if len(got) != 40 {
+			t.Fatal("expected 40 logs, got", len(got))
+		}
+		if pages != 14 {
+			t.Error("expected 14 pages, got", pages)
+		}
+		seen := make(map[topicsdb.ID]bool)
+		for i, l := range got {
+			id := *logID(l)
+			if seen[id] {
+				t.Fatal("duplicated log", i)
+			}
+			seen[id] = true
+			if i > 0 && got[i-1].BlockNumber > l.BlockNumber {
+				t.Fatal("logs aren't ordered", i)
+			}
+		}
+	}
 }
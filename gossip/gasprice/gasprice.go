@@ -85,25 +85,45 @@ func sanitizeBigInt(val, min, max, _default *big.Int, name string) *big.Int {
 // NewOracle returns a new gasprice oracle which can recommend suitable
 // gasprice for newly created transaction.
 func NewOracle(backend Reader, params Config) *Oracle {
+	return &Oracle{
+		backend: backend,
+		cfg:     sanitizeConfig(params),
+	}
+}
+
+func sanitizeConfig(params Config) Config {
 	params.MaxPrice = sanitizeBigInt(params.MaxPrice, nil, nil, DefaultMaxPrice, "MaxPrice")
 	params.MinPrice = sanitizeBigInt(params.MinPrice, nil, nil, new(big.Int), "MinPrice")
 	params.GasPowerWallRatio = sanitizeBigInt(params.GasPowerWallRatio, big.NewInt(1), big.NewInt(DecimalUnit-2), big.NewInt(1), "GasPowerWallRatio")
 	params.MaxPriceMultiplierRatio = sanitizeBigInt(params.MaxPriceMultiplierRatio, DecimalUnitBn, nil, big.NewInt(10*DecimalUnit), "MaxPriceMultiplierRatio")
 	params.MiddlePriceMultiplierRatio = sanitizeBigInt(params.MiddlePriceMultiplierRatio, DecimalUnitBn, params.MaxPriceMultiplierRatio, big.NewInt(2*DecimalUnit), "MiddlePriceMultiplierRatio")
-	return &Oracle{
-		backend: backend,
-		cfg:     params,
-	}
+	return params
+}
+
+// SetConfig replaces the config of a running oracle. The cached price is dropped.
+func (gpo *Oracle) SetConfig(params Config) {
+	params = sanitizeConfig(params)
+	gpo.cacheLock.Lock()
+	defer gpo.cacheLock.Unlock()
+	gpo.cfg = params
+	gpo.lastHead = 0
+	gpo.lastPrice = nil
+}
+
+func (gpo *Oracle) config() Config {
+	gpo.cacheLock.RLock()
+	defer gpo.cacheLock.RUnlock()
+	return gpo.cfg
 }
 
-func (gpo *Oracle) minGasPrice() *big.Int {
+func (gpo *Oracle) minGasPrice(cfg Config) *big.Int {
 	minPrice := gpo.backend.GetRules().Economy.MinGasPrice
 	pendingMinPrice := gpo.backend.GetPendingRules().Economy.MinGasPrice
 	if minPrice.Cmp(pendingMinPrice) < 0 {
 		minPrice = pendingMinPrice
 	}
-	if minPrice.Cmp(gpo.cfg.MinPrice) < 0 {
-		minPrice = gpo.cfg.MinPrice
+	if minPrice.Cmp(cfg.MinPrice) < 0 {
+		minPrice = cfg.MinPrice
 	}
 	return new(big.Int).Set(minPrice)
 }
@@ -118,7 +138,7 @@ func (gpo *Oracle) maxTotalGasPower() *big.Int {
 	return maxTotalGasPowerBn
 }
 
-func (gpo *Oracle) suggestPrice() *big.Int {
+func (gpo *Oracle) suggestPrice(cfg Config) *big.Int {
 	max := gpo.maxTotalGasPower()
 
 	current := new(big.Int).SetUint64(gpo.backend.TotalGasPowerLeft())
@@ -133,15 +153,15 @@ func (gpo *Oracle) suggestPrice() *big.Int {
 	multiplierFn := piecefunc.NewFunc([]piecefunc.Dot{
 		{
 			X: 0,
-			Y: gpo.cfg.MaxPriceMultiplierRatio.Uint64(),
+			Y: cfg.MaxPriceMultiplierRatio.Uint64(),
 		},
 		{
-			X: gpo.cfg.GasPowerWallRatio.Uint64(),
-			Y: gpo.cfg.MaxPriceMultiplierRatio.Uint64(),
+			X: cfg.GasPowerWallRatio.Uint64(),
+			Y: cfg.MaxPriceMultiplierRatio.Uint64(),
 		},
 		{
-			X: gpo.cfg.GasPowerWallRatio.Uint64() + (DecimalUnit-gpo.cfg.GasPowerWallRatio.Uint64())/2,
-			Y: gpo.cfg.MiddlePriceMultiplierRatio.Uint64(),
+			X: cfg.GasPowerWallRatio.Uint64() + (DecimalUnit-cfg.GasPowerWallRatio.Uint64())/2,
+			Y: cfg.MiddlePriceMultiplierRatio.Uint64(),
 		},
 		{
 			X: DecimalUnit,
@@ -152,7 +172,7 @@ func (gpo *Oracle) suggestPrice() *big.Int {
 	multiplier := new(big.Int).SetUint64(multiplierFn(freeRatio))
 
 	// price = multiplier * min gas price
-	price := multiplier.Mul(multiplier, gpo.minGasPrice())
+	price := multiplier.Mul(multiplier, gpo.minGasPrice(cfg))
 	price.Div(price, DecimalUnitBn)
 	return price
 }
@@ -166,15 +186,16 @@ func (gpo *Oracle) SuggestPrice() *big.Int {
 	gpo.cacheLock.RLock()
 	lastHead, lastPrice := gpo.lastHead, gpo.lastPrice
 	gpo.cacheLock.RUnlock()
-	if head == lastHead {
+	if head == lastHead && lastPrice != nil {
 		return lastPrice
 	}
 
-	price := gpo.suggestPrice()
-	if price.Cmp(gpo.cfg.MaxPrice) > 0 {
-		price = new(big.Int).Set(gpo.cfg.MaxPrice)
+	cfg := gpo.config()
+	price := gpo.suggestPrice(cfg)
+	if price.Cmp(cfg.MaxPrice) > 0 {
+		price = new(big.Int).Set(cfg.MaxPrice)
 	}
-	minimum := gpo.minGasPrice()
+	minimum := gpo.minGasPrice(cfg)
 	if price.Cmp(minimum) < 0 {
 		price = minimum
 	}
@@ -152,3 +152,28 @@ func TestSuggestPrice(t *testing.T) {
 	require.Equal(t, "2000000001", gpo.SuggestPrice().String())
 	backend.block++
 }
+
+func TestSetConfig(t *testing.T) {
+	backend := &TestBackend{
+		block:             1,
+		totalGasPowerLeft: 0,
+		rules:             skyhigh.FakeNetRules(),
+		pendingRules:      skyhigh.FakeNetRules(),
+	}
+
+	gpo := NewOracle(backend, Config{})
+	require.Equal(t, "10000000000", gpo.SuggestPrice().String())
+
+	// the cached price is dropped
+	gpo.SetConfig(Config{
+		MaxPriceMultiplierRatio: big.NewInt(100 * DecimalUnit),
+	})
+	require.Equal(t, "100000000000", gpo.SuggestPrice().String())
+
+	// the new config is sanitized
+	gpo.SetConfig(Config{
+		MaxPrice: big.NewInt(5000000000),
+	})
+	require.Equal(t, big.NewInt(10*DecimalUnit).String(), gpo.cfg.MaxPriceMultiplierRatio.String())
+	require.Equal(t, "5000000000", gpo.SuggestPrice().String())
+}
@@ -0,0 +1,37 @@
+package gossip
+
+import (
+	"errors"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+)
+
+func (s *Service) validatorEmitter() (*emitter.Emitter, error) {
+	if len(s.emitters) == 0 {
+		return nil, emitter.ErrNotValidator
+	}
+	if len(s.emitters) != 1 {
+		return nil, errors.New("key rotation isn't supported with multiple validators")
+	}
+	return s.emitters[0], nil
+}
+
+// RotateValidatorKey schedules a switch of the validator signing key,
+// the switch happens at the first epoch in which the new key is the validator's pubkey.
+func (s *Service) RotateValidatorKey(pubkey validatorpk.PubKey) (emitter.KeyRotationStatus, error) {
+	em, err := s.validatorEmitter()
+	if err != nil {
+		return emitter.KeyRotationStatus{}, err
+	}
+	return em.RotateKey(pubkey)
+}
+
+// ValidatorKeyRotation returns the state of the validator key rotation.
+func (s *Service) ValidatorKeyRotation() (emitter.KeyRotationStatus, error) {
+	em, err := s.validatorEmitter()
+	if err != nil {
+		return emitter.KeyRotationStatus{}, err
+	}
+	return em.KeyRotationStatus(), nil
+}
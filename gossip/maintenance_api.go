@@ -0,0 +1,75 @@
+package gossip
+
+import (
+	"context"
+	"errors"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+)
+
+// PrivateMaintenanceAPI provides an API to pause and resume the events emission during a validator maintenance.
+// In development mode, all the emitters are paused and resumed at once.
+type PrivateMaintenanceAPI struct {
+	s *Service
+}
+
+// NewPrivateMaintenanceAPI creates a new maintenance API.
+func NewPrivateMaintenanceAPI(s *Service) *PrivateMaintenanceAPI {
+	return &PrivateMaintenanceAPI{s}
+}
+
+func (api *PrivateMaintenanceAPI) emitters() ([]*emitter.Emitter, error) {
+	if len(api.s.emitters) == 0 {
+		return nil, emitter.ErrNotValidator
+	}
+	return api.s.emitters, nil
+}
+
+// PauseEmitter stops the events emission, the paused state survives restarts.
+// If drain is true, it waits until the last emitted event is confirmed.
+func (api *PrivateMaintenanceAPI) PauseEmitter(ctx context.Context, drain bool) (emitter.MaintenanceStatus, error) {
+	emitters, err := api.emitters()
+	if err != nil {
+		return emitter.MaintenanceStatus{}, err
+	}
+	for _, em := range emitters {
+		if _, err := em.Pause(); err != nil {
+			return emitter.MaintenanceStatus{}, err
+		}
+	}
+	if drain {
+		for _, em := range emitters {
+			if err := em.WaitDrained(ctx); err != nil {
+				if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+					return emitters[0].MaintenanceStatus(), errors.New("emission is paused, but the last emitted event isn't confirmed yet")
+				}
+				return emitter.MaintenanceStatus{}, err
+			}
+		}
+	}
+	return emitters[0].MaintenanceStatus(), nil
+}
+
+// ResumeEmitter resumes the events emission. If another instance of the validator may be running,
+// then it fails unless acknowledge is true, and the emission starts only after the doublesign protection interval.
+func (api *PrivateMaintenanceAPI) ResumeEmitter(acknowledge bool) (emitter.MaintenanceStatus, error) {
+	emitters, err := api.emitters()
+	if err != nil {
+		return emitter.MaintenanceStatus{}, err
+	}
+	for _, em := range emitters {
+		if _, err := em.Resume(acknowledge); err != nil {
+			return em.MaintenanceStatus(), err
+		}
+	}
+	return emitters[0].MaintenanceStatus(), nil
+}
+
+// EmitterStatus returns the state of the maintenance mode.
+func (api *PrivateMaintenanceAPI) EmitterStatus() (emitter.MaintenanceStatus, error) {
+	emitters, err := api.emitters()
+	if err != nil {
+		return emitter.MaintenanceStatus{}, err
+	}
+	return emitters[0].MaintenanceStatus(), nil
+}
@@ -0,0 +1,161 @@
+package gossip
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/skyhighblockchain/skyhigh/evmcore"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/utils"
+)
+
+var (
+	prefetchEventsMeter  = metrics.NewRegisteredMeter("prefetch/events", nil)
+	prefetchDroppedMeter = metrics.NewRegisteredMeter("prefetch/dropped", nil)
+	prefetchTimer        = metrics.NewRegisteredTimer("prefetch/time", nil)
+
+	// txs of executed blocks, which were prefetched or not
+	prefetchHitMeter     = metrics.NewRegisteredMeter("prefetch/hit", nil)
+	prefetchMissMeter    = metrics.NewRegisteredMeter("prefetch/miss", nil)
+	prefetchHitRateGauge = metrics.NewRegisteredGaugeFloat64("prefetch/hitrate", nil)
+	// estimated time saved by block execution, in nanoseconds
+	prefetchSavedCounter = metrics.NewRegisteredCounter("prefetch/saved", nil)
+)
+
+// eventsPrefetcher speculatively executes transactions of connected events on top of the last finalized state,
+// before the events are confirmed. Results are discarded, the goal is to warm up trie and snapshot caches,
+// so the block execution doesn't have to read the state from disk.
+type eventsPrefetcher struct {
+	cfg    PrefetchConfig
+	store  *Store
+	reader *EvmStateReader
+
+	queue chan *inter.EventPayload
+	// prefetched maps a tx hash to its execution duration on cold caches
+	prefetched *lru.Cache
+
+	interrupt uint32
+	quit      chan struct{}
+	wg        sync.WaitGroup
+}
+
+func newEventsPrefetcher(cfg PrefetchConfig, store *Store, reader *EvmStateReader) *eventsPrefetcher {
+	prefetched, _ := lru.New(cfg.MaxTrackedTxs)
+	return &eventsPrefetcher{
+		cfg:        cfg,
+		store:      store,
+		reader:     reader,
+		queue:      make(chan *inter.EventPayload, cfg.MaxQueuedEvents),
+		prefetched: prefetched,
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start starts the prefetching workers
+func (p *eventsPrefetcher) Start() {
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.loop()
+	}
+}
+
+// Stop interrupts prefetching and waits until the workers are stopped
+func (p *eventsPrefetcher) Stop() {
+	atomic.StoreUint32(&p.interrupt, 1)
+	close(p.quit)
+	p.wg.Wait()
+}
+
+// Prefetch enqueues the event for prefetching. The event is dropped if the queue is full.
+func (p *eventsPrefetcher) Prefetch(e *inter.EventPayload) {
+	if p.cfg.Workers == 0 || e.Txs().Len() == 0 {
+		return
+	}
+	select {
+	case p.queue <- e:
+	default:
+		prefetchDroppedMeter.Mark(1)
+	}
+}
+
+func (p *eventsPrefetcher) loop() {
+	defer p.wg.Done()
+	for {
+		select {
+		case e := <-p.queue:
+			p.prefetch(e)
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *eventsPrefetcher) prefetch(e *inter.EventPayload) {
+	txs := make(types.Transactions, 0, e.Txs().Len())
+	for _, tx := range e.Txs() {
+		if !p.prefetched.Contains(tx.Hash()) {
+			txs = append(txs, tx)
+		}
+	}
+	if len(txs) == 0 {
+		return
+	}
+
+	bs := p.store.GetBlockState()
+	statedb, err := p.store.evm.StateDB(bs.FinalizedStateRoot)
+	if err != nil {
+		return
+	}
+	block := evmcore.NewEvmBlock(&evmcore.EvmHeader{
+		Number:   utils.U64toBig(uint64(bs.LastBlock.Idx + 1)),
+		Hash:     common.Hash(e.ID()),
+		Time:     e.MedianTime(),
+		GasLimit: math.MaxUint64,
+	}, txs)
+
+	start := time.Now()
+	evmcore.NewStatePrefetcher(p.store.GetRules().EvmChainConfig(), p.reader).Prefetch(block, statedb, skyhigh.DefaultVMConfig, &p.interrupt)
+	elapsed := time.Since(start)
+	prefetchEventsMeter.Mark(1)
+	prefetchTimer.Update(elapsed)
+
+	perTx := elapsed / time.Duration(len(txs))
+	for _, tx := range txs {
+		p.prefetched.Add(tx.Hash(), perTx)
+	}
+}
+
+// OnBlockExecuted updates the metrics of prefetching efficiency, after the block txs were executed for elapsed time
+func (p *eventsPrefetcher) OnBlockExecuted(txs types.Transactions, elapsed time.Duration) {
+	if p.cfg.Workers == 0 || len(txs) == 0 {
+		return
+	}
+	hits := 0
+	var cold time.Duration
+	for _, tx := range txs {
+		v, ok := p.prefetched.Peek(tx.Hash())
+		if !ok {
+			continue
+		}
+		p.prefetched.Remove(tx.Hash())
+		hits++
+		cold += v.(time.Duration)
+	}
+	prefetchHitMeter.Mark(int64(hits))
+	prefetchMissMeter.Mark(int64(len(txs) - hits))
+	prefetchHitRateGauge.Update(float64(hits) / float64(len(txs)))
+
+	// estimate time saved as a difference between cold and warm execution of prefetched txs
+	warm := elapsed * time.Duration(hits) / time.Duration(len(txs))
+	if saved := cold - warm; saved > 0 {
+		prefetchSavedCounter.Inc(int64(saved))
+	}
+}
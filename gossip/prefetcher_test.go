@@ -0,0 +1,57 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/utils"
+)
+
+func TestEventsPrefetcher(t *testing.T) {
+	require := require.New(t)
+
+	env := newTestEnv()
+	defer env.Close()
+
+	cfg := PrefetchConfig{
+		Workers:         1,
+		MaxQueuedEvents: 1,
+		MaxTrackedTxs:   100,
+	}
+	p := newEventsPrefetcher(cfg, env.store, env.GetEvmStateReader())
+
+	tx1 := env.Transfer(1, 2, utils.ToSkh(100))
+	tx2 := env.Transfer(2, 3, utils.ToSkh(100))
+
+	me := &inter.MutableEventPayload{}
+	me.SetTxs(types.Transactions{tx1})
+	p.prefetch(me.Build())
+	require.True(p.prefetched.Contains(tx1.Hash()))
+	require.False(p.prefetched.Contains(tx2.Hash()))
+
+	// prefetching doesn't modify the finalized state
+	require.Equal(env.lastState, env.store.GetBlockState().FinalizedStateRoot)
+
+	p.OnBlockExecuted(types.Transactions{tx1, tx2}, time.Millisecond)
+	require.Equal(0, p.prefetched.Len())
+
+	// events are dropped if the queue is full, events without txs aren't enqueued
+	p.Prefetch(me.Build())
+	p.Prefetch(me.Build())
+	require.Len(p.queue, 1)
+	p.Prefetch((&inter.MutableEventPayload{}).Build())
+	require.Len(p.queue, 1)
+
+	p.Start()
+	p.Stop()
+
+	// disabled prefetcher
+	cfg.Workers = 0
+	p = newEventsPrefetcher(cfg, env.store, env.GetEvmStateReader())
+	p.Prefetch(me.Build())
+	require.Len(p.queue, 0)
+}
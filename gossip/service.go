@@ -5,6 +5,8 @@ import (
 	"math/big"
 	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/core"
@@ -104,7 +106,13 @@ func DefaultBlockProc(g skyhigh.Genesis) BlockProc {
 
 // Service implements go-ethereum/node.Service interface.
 type Service struct {
+	// config is the config which the node was started with, it isn't changed after the start
 	config Config
+	// running is the config with the applied reloads, i.e. the reloadable sub-configs of the running node
+	running  atomic.Value // Config
+	configMu sync.Mutex   // serializes the reloads
+	// resolvePath resolves relative paths of the config, such as the txpool journal
+	resolvePath func(string) string
 
 	wg   sync.WaitGroup
 	done chan struct{}
@@ -120,7 +128,8 @@ type Service struct {
 	engine              push.Consensus
 	dagIndexer          *vecmt.Index
 	engineMu            *sync.RWMutex
-	emitter             *emitter.Emitter
+	emitters            []*emitter.Emitter
+	devGroup            *emitter.DevGroup
 	txpool              *evmcore.TxPool
 	heavyCheckReader    HeavyCheckReader
 	gasPowerCheckReader GasPowerCheckReader
@@ -130,6 +139,8 @@ type Service struct {
 	// version watcher
 	verWatcher *verwatcher.VerWarcher
 
+	prefetcher *eventsPrefetcher
+
 	blockProcWg        sync.WaitGroup
 	blockProcTasks     *workers.Workers
 	blockProcTasksDone chan struct{}
@@ -171,6 +182,7 @@ func NewService(stack *node.Node, config Config, store *Store, signer valkeystor
 
 	svc.p2pServer = stack.Server()
 	svc.accountManager = stack.AccountManager()
+	svc.resolvePath = stack.ResolvePath
 	// Create the net API service
 	svc.netRPCService = ethapi.NewPublicNetAPI(svc.p2pServer, store.GetRules().NetworkID)
 
@@ -191,6 +203,7 @@ func newService(config Config, store *Store, signer valkeystore.SignerI, blockPr
 		uniqueEventIDs:     uniqueID{new(big.Int)},
 		Instance:           logger.MakeInstance(),
 	}
+	svc.running.Store(config)
 
 	svc.blockProcTasks = workers.New(new(sync.WaitGroup), svc.blockProcTasksDone, 1)
 
@@ -231,10 +244,12 @@ func newService(config Config, store *Store, signer valkeystore.SignerI, blockPr
 	// create API backend
 	svc.EthAPI = &EthAPIBackend{config.ExtRPCEnabled, svc, stateReader, config.AllowUnprotectedTxs}
 
-	svc.emitter = svc.makeEmitter(signer)
+	svc.emitters = svc.makeEmitters(signer)
 
 	svc.verWatcher = verwatcher.New(config.VersionWatcher, verwatcher.NewStore(store.table.NetworkVersion))
 
+	svc.prefetcher = newEventsPrefetcher(config.Prefetch, store, stateReader)
+
 	return svc, nil
 }
 
@@ -255,10 +270,10 @@ func makeCheckers(heavyCheckCfg heavycheck.Config, chainID *big.Int, heavyCheckR
 	}
 }
 
-func (s *Service) makeEmitter(signer valkeystore.SignerI) *emitter.Emitter {
+func (s *Service) makeEmitterWorld(signer valkeystore.SignerI) emitter.World {
 	txSigner := gsignercache.Wrap(types.NewEIP2930Signer(s.store.GetRules().EvmChainConfig().ChainID))
 
-	return emitter.NewEmitter(s.config.Emitter, emitter.World{
+	return emitter.World{
 		External: &emitterWorld{
 			s:       s,
 			Store:   s.store,
@@ -267,7 +282,20 @@ func (s *Service) makeEmitter(signer valkeystore.SignerI) *emitter.Emitter {
 		TxPool:   s.txpool,
 		Signer:   signer,
 		TxSigner: txSigner,
-	})
+	}
+}
+
+// makeEmitters makes an emitter of the validator, or emitters of all the validators in development mode
+func (s *Service) makeEmitters(signer valkeystore.SignerI) []*emitter.Emitter {
+	if len(s.config.DevValidators) == 0 {
+		return []*emitter.Emitter{emitter.NewEmitter(s.config.Emitter, s.makeEmitterWorld(signer))}
+	}
+	s.devGroup = emitter.NewDevGroup()
+	emitters := make([]*emitter.Emitter, len(s.config.DevValidators))
+	for i, validator := range s.config.DevValidators {
+		emitters[i] = s.devGroup.NewEmitter(emitter.DevConfig(validator), s.makeEmitterWorld(signer))
+	}
+	return emitters
 }
 
 // MakeProtocols constructs the P2P protocol definitions for `skyhigh`.
@@ -317,6 +345,7 @@ func (s *Service) Protocols() []p2p.Protocol {
 // APIs returns api methods the service wants to expose on rpc channels.
 func (s *Service) APIs() []rpc.API {
 	apis := ethapi.GetAPIs(s.EthAPI)
+	filterAPI := filters.NewPublicFilterAPI(s.EthAPI, s.config.FilterAPI)
 
 	apis = append(apis, []rpc.API{
 		{
@@ -327,8 +356,26 @@ func (s *Service) APIs() []rpc.API {
 		}, {
 			Namespace: "eth",
 			Version:   "1.0",
-			Service:   filters.NewPublicFilterAPI(s.EthAPI, s.config.FilterAPI),
+			Service:   filterAPI,
+			Public:    true,
+		}, {
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicABIDecodingAPI(s, filterAPI),
+			Public:    true,
+		}, {
+			Namespace: "skh",
+			Version:   "1.0",
+			Service:   NewPublicAddressTxsAPI(s),
 			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateABIRegistryAPI(s),
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateMaintenanceAPI(s),
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -337,6 +384,15 @@ func (s *Service) APIs() []rpc.API {
 		},
 	}...)
 
+	if s.devGroup != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "evm",
+			Version:   "1.0",
+			Service:   NewPublicDevAPI(s),
+			Public:    true,
+		})
+	}
+
 	return apis
 }
 
@@ -353,13 +409,86 @@ func (s *Service) Start() error {
 
 	s.pm.Start(s.p2pServer.MaxPeers)
 
-	s.emitter.Start()
+	for _, em := range s.emitters {
+		em.Start()
+	}
 
 	s.verWatcher.Start()
 
+	s.prefetcher.Start()
+
+	if s.store.ancient != nil && s.store.cfg.Ancient.Epochs != 0 && s.store.cfg.Ancient.Period > 0 {
+		s.wg.Add(1)
+		go s.storeTaskLoop(s.store.cfg.Ancient.Period, s.migrateToAncient)
+	}
+
+	if (s.store.cfg.History.Blocks != 0 || s.store.cfg.History.Epochs != 0) && s.store.cfg.History.Period > 0 {
+		s.wg.Add(1)
+		go s.storeTaskLoop(s.store.cfg.History.Period, s.pruneHistory)
+	}
+
 	return nil
 }
 
+// storeTaskLoop periodically runs the batched store task until it's done
+func (s *Service) storeTaskLoop(period time.Duration, task func() bool) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			for !task() {
+				select {
+				case <-s.done:
+					return
+				default:
+				}
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// migrateToAncient migrates a batch of old data into the ancient store. Returns true if nothing left to migrate.
+func (s *Service) migrateToAncient() bool {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	s.blockProcWg.Wait()
+
+	done, err := s.store.MigrateToAncient(s.store.cfg.Ancient.MaxBatch)
+	if err != nil {
+		s.Log.Error("Failed to migrate data into ancient store", "err", err)
+		return true
+	}
+	if s.store.IsCommitNeeded(false) {
+		if err := s.store.Commit(); err != nil {
+			s.Log.Error("Failed to commit DB", "err", err)
+		}
+	}
+	return done
+}
+
+// pruneHistory prunes a batch of old history. Returns true if nothing left to prune.
+func (s *Service) pruneHistory() bool {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	s.blockProcWg.Wait()
+
+	done, err := s.store.PruneHistory(s.store.cfg.History.MaxBatch)
+	if err != nil {
+		s.Log.Error("Failed to prune history", "err", err)
+		return true
+	}
+	if s.store.IsCommitNeeded(false) {
+		if err := s.store.Commit(); err != nil {
+			s.Log.Error("Failed to commit DB", "err", err)
+		}
+	}
+	return done
+}
+
 // WaitBlockEnd waits until parallel block processing is complete (if any)
 func (s *Service) WaitBlockEnd() {
 	s.blockProcWg.Wait()
@@ -369,8 +498,11 @@ func (s *Service) WaitBlockEnd() {
 func (s *Service) Stop() error {
 	defer log.Info("Skyhigh service stopped")
 	s.verWatcher.Stop()
+	s.prefetcher.Stop()
 	close(s.done)
-	s.emitter.Stop()
+	for _, em := range s.emitters {
+		em.Stop()
+	}
 	s.pm.Stop()
 	s.wg.Wait()
 	s.feed.scope.Close()
@@ -0,0 +1,697 @@
+package gossip
+
+import (
+	"bytes"
+	"container/heap"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	notify "github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/skyhighblockchain/push-base/abft"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/dag"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/utils/cachescale"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/evmcore"
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+	"github.com/skyhighblockchain/skyhigh/integration/makegenesis"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/utils"
+	"github.com/skyhighblockchain/skyhigh/utils/adapters/vecmt2dagidx"
+	"github.com/skyhighblockchain/skyhigh/valkeystore"
+	"github.com/skyhighblockchain/skyhigh/vecmt"
+)
+
+// The simulation runs gossip services of all the validators in one goroutine, against a virtual clock.
+// Events are delivered by a simulated network with latency, loss and partitions.
+// All the randomness is seeded, so a simulation with the same config produces the same DAG and blocks.
+
+type simBehaviour int
+
+const (
+	simHonest simBehaviour = iota
+	// simSilent validator never emits events
+	simSilent
+	// simSlow validator emits events simSlowdown times less often than others
+	simSlow
+	// simForker validator runs two instances, which emit conflicting events
+	simForker
+)
+
+const (
+	simSlowdown = 5
+	simAccounts = 10
+)
+
+// simPartition splits the network into groups of validators, which cannot communicate during [From, To)
+type simPartition struct {
+	From, To time.Duration
+	Groups   [][]idx.ValidatorID
+}
+
+type simConfig struct {
+	Seed       int64
+	Validators int
+	Byzantine  map[idx.ValidatorID]simBehaviour
+
+	// latency of every message is random in [MinLatency, MaxLatency]
+	MinLatency time.Duration
+	MaxLatency time.Duration
+	// Loss is a probability of a message loss
+	Loss       float64
+	Partitions []simPartition
+
+	EmitInterval time.Duration
+	// TickInterval, if not 0, makes the emitters decide the emission timing by themselves, as the started emitters do.
+	// The emitters are ticked with the period, and EmitInterval isn't used
+	TickInterval time.Duration
+	// SyncInterval is a period of requesting missing events from a random peer
+	SyncInterval time.Duration
+	// TxInterval is a period of transfers submission, 0 disables transfers
+	TxInterval    time.Duration
+	Accounts      int
+	EpochDuration time.Duration
+	Duration      time.Duration
+
+	// Rules and Emitter modify the network rules and the emitters config, if not nil
+	Rules   func(rules *skyhigh.Rules)
+	Emitter func(cfg *emitter.Config)
+}
+
+func defaultSimConfig(validators int) simConfig {
+	return simConfig{
+		Seed:          1,
+		Validators:    validators,
+		MinLatency:    10 * time.Millisecond,
+		MaxLatency:    100 * time.Millisecond,
+		EmitInterval:  200 * time.Millisecond,
+		SyncInterval:  250 * time.Millisecond,
+		TxInterval:    100 * time.Millisecond,
+		Accounts:      simAccounts,
+		EpochDuration: 10 * time.Second,
+		Duration:      20 * time.Second,
+	}
+}
+
+type simAction struct {
+	at  time.Duration
+	seq uint64
+	fn  func()
+}
+
+// simQueue is a priority queue of actions, ordered by time and then by scheduling order
+type simQueue []*simAction
+
+func (q simQueue) Len() int { return len(q) }
+func (q simQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+func (q simQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *simQueue) Push(x interface{}) { *q = append(*q, x.(*simAction)) }
+func (q *simQueue) Pop() interface{} {
+	old := *q
+	a := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return a
+}
+
+type simulation struct {
+	cfg     simConfig
+	rand    *rand.Rand
+	start   time.Time
+	now     time.Duration
+	queue   simQueue
+	seq     uint64
+	started bool
+
+	nodes []*simNode
+
+	accounts []*ecdsa.PrivateKey
+	nonces   []uint64
+	txs      []*types.Transaction
+	senders  map[common.Hash]common.Address
+	// submitted is the submission time of every transaction
+	submitted map[common.Hash]time.Duration
+	txSigner  types.Signer
+	gasPrice  *big.Int
+
+	// emitted is the first emitted event of each validator's (epoch, seq), to count forks
+	emitted map[simEventPos]hash.Event
+	forks   int
+}
+
+type simEventPos struct {
+	creator idx.ValidatorID
+	epoch   idx.Epoch
+	seq     idx.Event
+}
+
+type simNode struct {
+	sim       *simulation
+	validator idx.ValidatorID
+	behaviour simBehaviour
+
+	svc     *Service
+	emitter *emitter.Emitter
+
+	pending map[hash.Event]*inter.EventPayload
+	// order of the connected events, which are served to peers
+	order    []*inter.EventPayload
+	rejected []error
+}
+
+func simKey(kind string, n int) *ecdsa.PrivateKey {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("simulation %s %d", kind, n))))
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func newSimulation(t testing.TB, cfg simConfig) *simulation {
+	require := require.New(t)
+
+	rules := skyhigh.FakeNetRules()
+	rules.Epochs.MaxEpochDuration = inter.Timestamp(cfg.EpochDuration)
+	rules.Blocks.MaxEmptyBlockSkipPeriod = 0
+	if cfg.Rules != nil {
+		cfg.Rules(&rules)
+	}
+	spec := &makegenesis.Spec{
+		Rules: rules,
+		Time:  uint64(makegenesis.FakeGenesisTime.Unix()),
+	}
+	validatorKeys := make([]*ecdsa.PrivateKey, cfg.Validators)
+	for i := range validatorKeys {
+		validatorKeys[i] = simKey("validator", i+1)
+		addr := crypto.PubkeyToAddress(validatorKeys[i].PublicKey)
+		spec.Validators = append(spec.Validators, makegenesis.ValidatorSpec{
+			ID:      idx.ValidatorID(i + 1),
+			Address: addr,
+			PubKey:  simPubKey(validatorKeys[i]),
+			Stake:   utils.ToSkh(genesisStake),
+		})
+		spec.Accounts = append(spec.Accounts, makegenesis.AccountSpec{
+			Address: addr,
+			Balance: utils.ToSkh(genesisBalance),
+		})
+	}
+	accounts := make([]*ecdsa.PrivateKey, cfg.Accounts)
+	for i := range accounts {
+		accounts[i] = simKey("account", i)
+		spec.Accounts = append(spec.Accounts, makegenesis.AccountSpec{
+			Address: crypto.PubkeyToAddress(accounts[i].PublicKey),
+			Balance: utils.ToSkh(genesisBalance),
+		})
+	}
+	genStore, err := makegenesis.BuildGenesisStore(spec)
+	require.NoError(err)
+	genesis := genStore.GetGenesis()
+
+	s := &simulation{
+		cfg:       cfg,
+		rand:      rand.New(rand.NewSource(cfg.Seed)),
+		start:     genesis.Time.Time().Add(time.Second),
+		accounts:  accounts,
+		nonces:    make([]uint64, len(accounts)),
+		senders:   make(map[common.Hash]common.Address),
+		submitted: make(map[common.Hash]time.Duration),
+		txSigner:  types.NewEIP2930Signer(rules.EvmChainConfig().ChainID),
+		gasPrice:  new(big.Int).Mul(rules.Economy.MinGasPrice, big.NewInt(100)),
+		emitted:   make(map[simEventPos]hash.Event),
+	}
+	for i, key := range validatorKeys {
+		id := idx.ValidatorID(i + 1)
+		behaviour := cfg.Byzantine[id]
+		s.nodes = append(s.nodes, s.newNode(t, genesis, id, key, behaviour))
+		if behaviour == simForker {
+			s.nodes = append(s.nodes, s.newNode(t, genesis, id, key, behaviour))
+		}
+	}
+	return s
+}
+
+func simPubKey(key *ecdsa.PrivateKey) validatorpk.PubKey {
+	return validatorpk.PubKey{
+		Raw:  crypto.FromECDSAPub(&key.PublicKey),
+		Type: validatorpk.Types.Secp256k1,
+	}
+}
+
+func (s *simulation) newNode(t testing.TB, genesis skyhigh.Genesis, id idx.ValidatorID, key *ecdsa.PrivateKey, behaviour simBehaviour) *simNode {
+	require := require.New(t)
+	crit := func(err error) {
+		panic(err)
+	}
+
+	store := NewMemStore()
+	blockProc := DefaultBlockProc(genesis)
+	_, err := store.ApplyGenesis(blockProc, genesis)
+	require.NoError(err)
+	cdb := abft.NewMemStore()
+	require.NoError(cdb.ApplyGenesis(&abft.Genesis{
+		Epoch:      store.GetEpoch(),
+		Validators: store.GetValidators(),
+	}))
+	vecClock := vecmt.NewIndex(crit, vecmt.LiteConfig())
+	engine := abft.NewPush(cdb, &simGossipStoreAdapter{store}, vecmt2dagidx.Wrap(vecClock), crit, abft.LiteConfig())
+
+	pubkey := simPubKey(key)
+	keystore := valkeystore.NewDefaultMemKeystore()
+	require.NoError(keystore.Add(pubkey, crypto.FromECDSA(key), validatorpk.FakePassword))
+	require.NoError(keystore.Unlock(pubkey, validatorpk.FakePassword))
+	signer := valkeystore.NewSigner(keystore)
+
+	config := DefaultConfig(cachescale.Identity)
+	config.Prefetch.Workers = 0
+	config.TxPool.Journal = ""
+	svc, err := newService(config, store, signer, blockProc, engine, vecClock)
+	require.NoError(err)
+
+	n := &simNode{
+		sim:       s,
+		validator: id,
+		behaviour: behaviour,
+		svc:       svc,
+		pending:   make(map[hash.Event]*inter.EventPayload),
+	}
+
+	emConfig := emitter.DefaultConfig()
+	emConfig.Validator = emitter.ValidatorConfig{
+		ID:     id,
+		PubKey: pubkey,
+	}
+	emConfig.EmitIntervals.DoublesignProtection = 0
+	emConfig.EmitIntervals.ParallelInstanceProtection = 0
+	if s.cfg.Emitter != nil {
+		s.cfg.Emitter(&emConfig)
+	}
+	world := svc.makeEmitterWorld(signer)
+	world.TxPool = &simTxPool{n}
+	r := rand.New(rand.NewSource(s.cfg.Seed + int64(len(s.nodes))))
+	if s.cfg.TickInterval != 0 {
+		n.emitter = emitter.NewTickedEmitter(emConfig, world, s.clock, r)
+	} else {
+		n.emitter = emitter.NewManualEmitter(emConfig, world, s.clock, r)
+	}
+	svc.emitters = []*emitter.Emitter{n.emitter}
+	// the emitters are notified by the consensus callbacks, so they're set before the bootstrap
+	require.NoError(engine.Bootstrap(svc.GetConsensusCallbacks()))
+
+	svc.blockProcTasks.Start(1)
+	n.emitter.Start()
+	return n
+}
+
+func (s *simulation) Close() {
+	for _, n := range s.nodes {
+		n.emitter.Stop()
+		n.svc.blockProcWg.Wait()
+		close(n.svc.blockProcTasksDone)
+		n.svc.txpool.Stop()
+		n.svc.feed.scope.Close()
+		n.svc.store.Close()
+	}
+}
+
+func (s *simulation) clock() time.Time {
+	return s.start.Add(s.now)
+}
+
+func (s *simulation) schedule(after time.Duration, fn func()) {
+	s.seq++
+	heap.Push(&s.queue, &simAction{
+		at:  s.now + after,
+		seq: s.seq,
+		fn:  fn,
+	})
+}
+
+// jitter returns a random duration in [d, 1.5*d)
+func (s *simulation) jitter(d time.Duration) time.Duration {
+	return d + time.Duration(s.rand.Int63n(int64(d)/2+1))
+}
+
+// Run runs the simulation until the configured duration is passed.
+// It may be called again with a greater duration to continue the simulation.
+func (s *simulation) Run() {
+	if !s.started {
+		s.started = true
+		for _, n := range s.nodes {
+			switch {
+			case n.behaviour == simSilent:
+				// never emits
+			case s.cfg.TickInterval != 0:
+				s.schedule(s.jitter(s.cfg.TickInterval), n.tick)
+			default:
+				s.schedule(s.jitter(n.emitInterval()), n.emit)
+			}
+			s.schedule(s.jitter(s.cfg.SyncInterval), n.sync)
+		}
+		if s.cfg.TxInterval != 0 {
+			s.schedule(s.cfg.TxInterval, s.submitTx)
+		}
+	}
+	for s.queue.Len() != 0 && s.queue[0].at <= s.cfg.Duration {
+		a := heap.Pop(&s.queue).(*simAction)
+		s.now = a.at
+		a.fn()
+	}
+}
+
+func (s *simulation) partitioned(a, b idx.ValidatorID) bool {
+	for _, p := range s.cfg.Partitions {
+		if s.now < p.From || s.now >= p.To {
+			continue
+		}
+		groupOf := func(v idx.ValidatorID) int {
+			for i, group := range p.Groups {
+				for _, member := range group {
+					if member == v {
+						return i
+					}
+				}
+			}
+			return -1
+		}
+		if groupOf(a) != groupOf(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// send delivers the message to the node after a random latency, unless it's lost or the nodes are partitioned
+func (s *simulation) send(from, to *simNode, deliver func()) {
+	if s.partitioned(from.validator, to.validator) {
+		return
+	}
+	if s.cfg.Loss != 0 && s.rand.Float64() < s.cfg.Loss {
+		return
+	}
+	latency := s.cfg.MinLatency
+	if s.cfg.MaxLatency > s.cfg.MinLatency {
+		latency += time.Duration(s.rand.Int63n(int64(s.cfg.MaxLatency - s.cfg.MinLatency)))
+	}
+	s.schedule(latency, deliver)
+}
+
+func (s *simulation) submitTx() {
+	i := len(s.txs) % len(s.accounts)
+	to := common.BigToAddress(big.NewInt(s.rand.Int63()))
+	tx, err := types.SignTx(types.NewTransaction(s.nonces[i], to, big.NewInt(1), params.TxGas, s.gasPrice, nil), s.txSigner, s.accounts[i])
+	if err != nil {
+		panic(err)
+	}
+	s.nonces[i]++
+	s.txs = append(s.txs, tx)
+	s.senders[tx.Hash()] = crypto.PubkeyToAddress(s.accounts[i].PublicKey)
+	s.submitted[tx.Hash()] = s.now
+	s.schedule(s.cfg.TxInterval, s.submitTx)
+}
+
+func (s *simulation) recordEmitted(e *inter.EventPayload) {
+	pos := simEventPos{e.Creator(), e.Epoch(), e.Seq()}
+	if prev, ok := s.emitted[pos]; ok && prev != e.ID() {
+		s.forks++
+		return
+	}
+	s.emitted[pos] = e.ID()
+}
+
+// correctNodes returns nodes of validators, which don't fork
+func (s *simulation) correctNodes() []*simNode {
+	nodes := make([]*simNode, 0, len(s.nodes))
+	for _, n := range s.nodes {
+		if n.behaviour != simForker {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// minBlock returns the lowest latest block among correct nodes
+func (s *simulation) minBlock() idx.Block {
+	var min idx.Block
+	for i, n := range s.correctNodes() {
+		if latest := n.svc.store.GetLatestBlockIndex(); i == 0 || latest < min {
+			min = latest
+		}
+	}
+	return min
+}
+
+// checkSafety checks that all the correct nodes have decided the same blocks, with the same state
+func (s *simulation) checkSafety(t testing.TB) {
+	nodes := s.correctNodes()
+	for _, n := range nodes {
+		require.Empty(t, n.rejected, "validator %d", n.validator)
+	}
+	for b := idx.Block(1); b <= s.minBlock(); b++ {
+		expected := nodes[0].svc.store.GetBlock(b)
+		require.NotNil(t, expected, "block %d", b)
+		for _, n := range nodes[1:] {
+			got := n.svc.store.GetBlock(b)
+			require.NotNil(t, got, "block %d of validator %d", b, n.validator)
+			require.Equal(t, expected.Atropos, got.Atropos, "atropos of block %d of validator %d", b, n.validator)
+			require.Equal(t, expected.Root, got.Root, "state root of block %d of validator %d", b, n.validator)
+			require.Equal(t, expected.Txs, got.Txs, "txs of block %d of validator %d", b, n.validator)
+		}
+	}
+}
+
+// blocks returns atropos of every block of the node
+func (n *simNode) blocks() []hash.Event {
+	var res []hash.Event
+	for b := idx.Block(1); b <= n.svc.store.GetLatestBlockIndex(); b++ {
+		res = append(res, n.svc.store.GetBlock(b).Atropos)
+	}
+	return res
+}
+
+func (n *simNode) emitInterval() time.Duration {
+	if n.behaviour == simSlow {
+		return n.sim.cfg.EmitInterval * simSlowdown
+	}
+	return n.sim.cfg.EmitInterval
+}
+
+func (n *simNode) emit() {
+	n.emitted(n.emitter.EmitEvent())
+	n.sim.schedule(n.sim.jitter(n.emitInterval()), n.emit)
+}
+
+// tick lets the emitter decide whether to emit an event
+func (n *simNode) tick() {
+	n.emitted(n.emitter.Tick())
+	n.sim.schedule(n.sim.cfg.TickInterval, n.tick)
+}
+
+func (n *simNode) emitted(e *inter.EventPayload) {
+	n.svc.blockProcWg.Wait()
+	if e != nil {
+		n.order = append(n.order, e)
+		n.sim.recordEmitted(e)
+		n.broadcast(e)
+		n.processPending()
+	}
+}
+
+func (n *simNode) broadcast(e *inter.EventPayload) {
+	for _, peer := range n.sim.nodes {
+		if peer == n {
+			continue
+		}
+		peer := peer
+		n.sim.send(n, peer, func() {
+			peer.receive([]*inter.EventPayload{e})
+		})
+	}
+}
+
+// sync requests the events, which are missing on this node, from a random peer
+func (n *simNode) sync() {
+	peer := n.sim.nodes[n.sim.rand.Intn(len(n.sim.nodes))]
+	if peer != n {
+		n.sim.send(n, peer, func() {
+			missing := peer.missingOn(n)
+			if len(missing) != 0 {
+				n.sim.send(peer, n, func() {
+					n.receive(missing)
+				})
+			}
+		})
+	}
+	n.sim.schedule(n.sim.jitter(n.sim.cfg.SyncInterval), n.sync)
+}
+
+func (n *simNode) known(id hash.Event) bool {
+	return n.pending[id] != nil || n.svc.store.HasEvent(id)
+}
+
+// missingOn returns the connected events, which are unknown to the other node
+func (n *simNode) missingOn(other *simNode) []*inter.EventPayload {
+	epoch := other.svc.store.GetEpoch()
+	var missing []*inter.EventPayload
+	for _, e := range n.order {
+		if e.Epoch() >= epoch && !other.known(e.ID()) {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+func (n *simNode) receive(events []*inter.EventPayload) {
+	epoch := n.svc.store.GetEpoch()
+	for _, e := range events {
+		if e.Epoch() >= epoch && !n.known(e.ID()) {
+			n.pending[e.ID()] = e
+		}
+	}
+	n.processPending()
+}
+
+// processPending connects the pending events, whose parents are connected
+func (n *simNode) processPending() {
+	for progress := true; progress; {
+		progress = false
+		epoch := n.svc.store.GetEpoch()
+		sorted := make([]*inter.EventPayload, 0, len(n.pending))
+		for _, e := range n.pending {
+			sorted = append(sorted, e)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			a, b := sorted[i], sorted[j]
+			if a.Epoch() != b.Epoch() {
+				return a.Epoch() < b.Epoch()
+			}
+			if a.Lamport() != b.Lamport() {
+				return a.Lamport() < b.Lamport()
+			}
+			return bytes.Compare(a.ID().Bytes(), b.ID().Bytes()) < 0
+		})
+		for _, e := range sorted {
+			if e.Epoch() < epoch {
+				delete(n.pending, e.ID())
+				continue
+			}
+			if e.Epoch() > epoch || !n.parentsConnected(e) {
+				continue
+			}
+			delete(n.pending, e.ID())
+			n.process(e)
+			progress = true
+			if n.svc.store.GetEpoch() != epoch {
+				break
+			}
+		}
+	}
+}
+
+func (n *simNode) parentsConnected(e *inter.EventPayload) bool {
+	for _, p := range e.Parents() {
+		if !n.svc.store.HasEvent(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *simNode) process(e *inter.EventPayload) {
+	parents := make(inter.Events, len(e.Parents()))
+	for i, p := range e.Parents() {
+		parents[i] = n.svc.store.GetEvent(p)
+	}
+	err := n.svc.checkers.Validate(e, parents.Interfaces())
+	if err == nil {
+		n.svc.engineMu.Lock()
+		err = n.svc.processEvent(e)
+		n.svc.engineMu.Unlock()
+		n.svc.blockProcWg.Wait()
+	}
+	if err != nil {
+		n.rejected = append(n.rejected, fmt.Errorf("event %s: %v", e.ID().String(), err))
+		return
+	}
+	n.order = append(n.order, e)
+}
+
+func (n *simNode) state() *state.StateDB {
+	statedb, err := n.svc.store.evm.StateDB(n.svc.store.GetBlockState().FinalizedStateRoot)
+	if err != nil {
+		panic(err)
+	}
+	return statedb
+}
+
+// simTxPool contains the submitted transactions, which aren't applied on the node yet
+type simTxPool struct {
+	n *simNode
+}
+
+func (p *simTxPool) Pending() (map[common.Address]types.Transactions, error) {
+	statedb := p.n.state()
+	pending := make(map[common.Address]types.Transactions)
+	for _, tx := range p.n.sim.txs {
+		sender := p.n.sim.senders[tx.Hash()]
+		if tx.Nonce() >= statedb.GetNonce(sender) {
+			pending[sender] = append(pending[sender], tx)
+		}
+	}
+	return pending, nil
+}
+
+func (p *simTxPool) Has(h common.Hash) bool {
+	sender, ok := p.n.sim.senders[h]
+	if !ok {
+		return false
+	}
+	for _, tx := range p.n.sim.txs {
+		if tx.Hash() == h {
+			return tx.Nonce() >= p.n.state().GetNonce(sender)
+		}
+	}
+	return false
+}
+
+func (p *simTxPool) Count() int {
+	pending, _ := p.Pending()
+	count := 0
+	for _, txs := range pending {
+		count += len(txs)
+	}
+	return count
+}
+
+func (p *simTxPool) SubscribeNewTxsNotify(ch chan<- evmcore.NewTxsNotify) notify.Subscription {
+	return new(notify.Feed).Subscribe(ch)
+}
+
+type simGossipStoreAdapter struct {
+	*Store
+}
+
+func (g *simGossipStoreAdapter) GetEvent(id hash.Event) dag.Event {
+	e := g.Store.GetEvent(id)
+	if e == nil {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,166 @@
+package gossip
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/emitter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+// simEmitterReport is a summary of the emitters behaviour during a simulation
+type simEmitterReport struct {
+	emitter.Stats
+	// Included is a number of the submitted transactions, which are included into blocks
+	Included int
+	// Latency is a mean and 95th percentile of the time from a transaction submission to its block
+	Latency    time.Duration
+	LatencyP95 time.Duration
+}
+
+// emitterReport sums up the emitters stats, and measures the tx inclusion latency by blocks of the first node.
+// GasPowerLeft is the lowest gas power left among the validators.
+func (s *simulation) emitterReport() simEmitterReport {
+	var r simEmitterReport
+	for i, n := range s.correctNodes() {
+		stats := n.emitter.Stats()
+		r.Events += stats.Events
+		r.Txs += stats.Txs
+		r.GasPowerUsed += stats.GasPowerUsed
+		if i == 0 || stats.GasPowerLeft < r.GasPowerLeft {
+			r.GasPowerLeft = stats.GasPowerLeft
+		}
+		r.LimitedTps += stats.LimitedTps
+		r.NoTxs += stats.NoTxs
+		r.Emergency += stats.Emergency
+	}
+
+	n := s.nodes[0]
+	var latencies []time.Duration
+	for _, tx := range s.txs {
+		position := n.svc.store.EvmStore().GetTxPosition(tx.Hash())
+		if position == nil {
+			continue
+		}
+		block := n.svc.store.GetBlock(position.Block)
+		latency := block.Time.Time().Sub(s.start.Add(s.submitted[tx.Hash()]))
+		if latency < 0 {
+			latency = 0
+		}
+		latencies = append(latencies, latency)
+	}
+	r.Included = len(latencies)
+	if len(latencies) != 0 {
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+		var sum time.Duration
+		for _, l := range latencies {
+			sum += l
+		}
+		r.Latency = sum / time.Duration(len(latencies))
+		r.LatencyP95 = latencies[len(latencies)*95/100]
+	}
+	return r
+}
+
+func defaultEmitterSimConfig(validators int) simConfig {
+	cfg := defaultSimConfig(validators)
+	cfg.TickInterval = 20 * time.Millisecond
+	return cfg
+}
+
+// mainnetGasPower replaces the fakenet gas power, which is too high for the emitter thresholds to matter
+func mainnetGasPower(rules *skyhigh.Rules) {
+	rules.Economy.ShortGasPower = skyhigh.DefaultShortGasPowerRules()
+	rules.Economy.LongGasPower = skyhigh.DefaulLongGasPowerRules()
+}
+
+func TestSimulation_TickedEmitters(t *testing.T) {
+	cfg := defaultEmitterSimConfig(4)
+	cfg.Duration = 10 * time.Second
+
+	sim := runSimulation(t, cfg)
+	require.NotZero(t, sim.minBlock())
+
+	r := sim.emitterReport()
+	require.NotZero(t, r.Events)
+	require.NotZero(t, r.GasPowerUsed)
+	require.NotZero(t, r.Included)
+	require.LessOrEqual(t, uint64(r.Included), r.Txs)
+	require.True(t, r.Latency > 0 && r.Latency <= r.LatencyP95, "latency %v, p95 %v", r.Latency, r.LatencyP95)
+	// fakenet gas power is too high for the thresholds to kick in
+	require.Zero(t, r.LimitedTps+r.NoTxs+r.Emergency)
+}
+
+func TestSimulation_EmitterThresholds(t *testing.T) {
+	cfg := defaultEmitterSimConfig(4)
+	cfg.Duration = 10 * time.Second
+	cfg.Rules = mainnetGasPower
+
+	// gas power of a fresh validator is below LimitedTpsThreshold
+	sim := runSimulation(t, cfg)
+	r := sim.emitterReport()
+	require.NotZero(t, r.LimitedTps)
+	require.NotZero(t, r.Included)
+}
+
+// BenchmarkEmitter runs simulations of the emitters under different tx loads and gas power, and reports
+// the emission rate, gas power consumption, tx inclusion latency and how often the thresholds of emitter.Config kick in.
+// The reported values are per a simulated second, so they don't depend on the simulation speed. Run with:
+//
+//	go test ./gossip -run=^$ -bench=BenchmarkEmitter -benchtime=1x
+func BenchmarkEmitter(b *testing.B) {
+	for _, gasPower := range []string{"fakenet", "mainnet"} {
+		for _, tps := range []int{10, 50, 150} {
+			b.Run(fmt.Sprintf("gaspower=%s/tps=%d", gasPower, tps), func(b *testing.B) {
+				cfg := defaultEmitterSimConfig(5)
+				cfg.TxInterval = time.Second / time.Duration(tps)
+				cfg.Accounts = tps * 2
+				if gasPower == "mainnet" {
+					cfg.Rules = mainnetGasPower
+				}
+				benchmarkEmitter(b, cfg)
+			})
+		}
+	}
+}
+
+func benchmarkEmitter(b *testing.B, cfg simConfig) {
+	var total simEmitterReport
+	for i := 0; i < b.N; i++ {
+		cfg.Seed = int64(i + 1)
+		sim := newSimulation(b, cfg)
+		sim.Run()
+		sim.checkSafety(b)
+		r := sim.emitterReport()
+		sim.Close()
+
+		total.Events += r.Events
+		total.Txs += r.Txs
+		total.GasPowerUsed += r.GasPowerUsed
+		total.GasPowerLeft += r.GasPowerLeft
+		total.LimitedTps += r.LimitedTps
+		total.NoTxs += r.NoTxs
+		total.Emergency += r.Emergency
+		total.Included += r.Included
+		total.Latency += r.Latency
+		total.LatencyP95 += r.LatencyP95
+	}
+	seconds := float64(b.N) * cfg.Duration.Seconds()
+	runs := float64(b.N)
+	b.ReportMetric(float64(total.Events)/seconds, "events/s")
+	b.ReportMetric(float64(total.Txs)/seconds, "originated-txs/s")
+	b.ReportMetric(float64(total.Included)/seconds, "included-txs/s")
+	b.ReportMetric(float64(total.GasPowerUsed)/seconds, "gaspower/s")
+	b.ReportMetric(float64(total.GasPowerLeft)/runs, "min-gaspower-left")
+	b.ReportMetric(float64(total.Latency.Milliseconds())/runs, "latency-ms")
+	b.ReportMetric(float64(total.LatencyP95.Milliseconds())/runs, "latency-p95-ms")
+	b.ReportMetric(float64(total.LimitedTps)/seconds, "limited-tps/s")
+	b.ReportMetric(float64(total.NoTxs)/seconds, "no-txs/s")
+	b.ReportMetric(float64(total.Emergency)/seconds, "emergency/s")
+}
@@ -0,0 +1,99 @@
+package gossip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+func runSimulation(t *testing.T, cfg simConfig) *simulation {
+	sim := newSimulation(t, cfg)
+	t.Cleanup(sim.Close)
+	sim.Run()
+	sim.checkSafety(t)
+	return sim
+}
+
+func TestSimulation_Deterministic(t *testing.T) {
+	cfg := defaultSimConfig(4)
+	cfg.Loss = 0.05
+	cfg.Duration = 10 * time.Second
+
+	a := runSimulation(t, cfg)
+	b := runSimulation(t, cfg)
+	require.NotZero(t, a.minBlock())
+	for i := range a.nodes {
+		require.Equal(t, a.nodes[i].blocks(), b.nodes[i].blocks(), "validator %d", a.nodes[i].validator)
+	}
+}
+
+func TestSimulation_LatencyAndLoss(t *testing.T) {
+	cfg := defaultSimConfig(5)
+	cfg.MinLatency = 50 * time.Millisecond
+	cfg.MaxLatency = 500 * time.Millisecond
+	cfg.Loss = 0.2
+	cfg.Duration = 30 * time.Second
+
+	sim := runSimulation(t, cfg)
+	require.Greater(t, uint64(sim.minBlock()), uint64(5))
+	// epochs are sealed by time
+	for _, n := range sim.nodes {
+		require.Greater(t, uint64(n.svc.store.GetEpoch()), uint64(2))
+	}
+	// transfers are applied
+	statedb := sim.nodes[0].state()
+	applied := uint64(0)
+	for _, key := range sim.accounts {
+		applied += statedb.GetNonce(crypto.PubkeyToAddress(key.PublicKey))
+	}
+	require.Greater(t, applied, uint64(len(sim.txs)/2))
+}
+
+func TestSimulation_Partition(t *testing.T) {
+	cfg := defaultSimConfig(4)
+	cfg.Duration = 30 * time.Second
+	cfg.Partitions = []simPartition{{
+		From:   5 * time.Second,
+		To:     15 * time.Second,
+		Groups: [][]idx.ValidatorID{{1, 2}, {3, 4}},
+	}}
+
+	sim := newSimulation(t, cfg)
+	defer sim.Close()
+
+	// no side has a quorum during the partition
+	sim.cfg.Duration = 7 * time.Second
+	sim.Run()
+	sim.checkSafety(t)
+	before := sim.minBlock()
+	require.NotZero(t, before)
+
+	sim.cfg.Duration = 14 * time.Second
+	sim.Run()
+	sim.checkSafety(t)
+	for _, n := range sim.nodes {
+		require.LessOrEqual(t, uint64(n.svc.store.GetLatestBlockIndex()), uint64(before)+1)
+	}
+
+	// the network recovers after the partition is healed
+	sim.cfg.Duration = cfg.Duration
+	sim.Run()
+	sim.checkSafety(t)
+	require.Greater(t, uint64(sim.minBlock()), uint64(before)+5)
+}
+
+func TestSimulation_Byzantine(t *testing.T) {
+	cfg := defaultSimConfig(7)
+	cfg.Byzantine = map[idx.ValidatorID]simBehaviour{
+		2: simForker,
+		4: simSilent,
+		6: simSlow,
+	}
+
+	sim := runSimulation(t, cfg)
+	require.NotZero(t, sim.forks)
+	require.Greater(t, uint64(sim.minBlock()), uint64(10))
+}
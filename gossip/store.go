@@ -15,6 +15,7 @@ import (
 	"github.com/skyhighblockchain/skyhigh/gossip/evmstore"
 	"github.com/skyhighblockchain/skyhigh/gossip/sfcapi"
 	"github.com/skyhighblockchain/skyhigh/logger"
+	"github.com/skyhighblockchain/skyhigh/utils/ancient"
 	"github.com/skyhighblockchain/skyhigh/utils/rlpstore"
 )
 
@@ -44,10 +45,28 @@ type Store struct {
 		NetworkVersion kvdb.Store `table:"V"`
 
 		// API-only
-		BlockHashes kvdb.Store `table:"B"`
-		SfcAPI      kvdb.Store `table:"S"`
+		BlockHashes   kvdb.Store `table:"B"`
+		SfcAPI        kvdb.Store `table:"S"`
+		HistoryEpochs kvdb.Store `table:"h"`
+
+		// AncientEvents maps an event ID to the event item in the ancient store
+		AncientEvents kvdb.Store `table:"a"`
+		// AncientHeads keeps the heads of ancient tables, which items are indexed in the key-value DB
+		AncientHeads kvdb.Store `table:"H"`
+		// HistoryPruning keeps the boundaries of the pruned history
+		HistoryPruning kvdb.Store `table:"p"`
+		// Reindex keeps the checkpoints of interrupted reindexing
+		Reindex kvdb.Store `table:"i"`
+		// ContractABIs keeps the registered ABIs of contracts
+		ContractABIs kvdb.Store `table:"c"`
 	}
 
+	// ancient is nil if the ancient store is disabled
+	ancient *ancient.Store
+
+	// migrationBackup is opened only to back up or restore the tables modified by migrations
+	migrationBackup kvdb.Store
+
 	prevFlushTime time.Time
 
 	epochStore atomic.Value
@@ -76,8 +95,20 @@ func NewMemStore() *Store {
 	return NewStore(dbs, cfg)
 }
 
-// NewStore creates store over key-value db.
+// NewStore creates store over key-value db, and applies the pending migrations.
 func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
+	s := NewUnmigratedStore(dbs, cfg)
+
+	if err := s.migrateData(); err != nil {
+		s.Log.Crit("Failed to migrate Gossip DB", "err", err)
+	}
+
+	return s
+}
+
+// NewUnmigratedStore creates store over key-value db without applying the pending migrations.
+// It's used to inspect and apply the migrations explicitly, see PendingMigrations and Migrate.
+func NewUnmigratedStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
 	mainDB, err := dbs.OpenDB("gossip")
 	if err != nil {
 		log.Crit("Filed to open DB", "name", "gossip", "err", err)
@@ -102,8 +133,13 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
 	s.evm = evmstore.NewStore(s.mainDB, cfg.EVM)
 	s.sfcapi = sfcapi.NewStore(s.table.SfcAPI)
 
-	if err := s.migrateData(); err != nil {
-		s.Log.Crit("Failed to migrate Gossip DB", "err", err)
+	if len(cfg.Ancient.Dir) != 0 {
+		s.ancient, err = ancient.Open(cfg.Ancient.Dir, ancientBlocksTable, ancientEventsTable, evmstore.AncientReceiptsTable, evmstore.AncientTxsTable)
+		if err != nil {
+			s.Log.Crit("Failed to open ancient store", "dir", cfg.Ancient.Dir, "err", err)
+		}
+		s.repairAncientEvents()
+		s.evm.SetAncient(s.ancient)
 	}
 
 	return s
@@ -135,6 +171,12 @@ func (s *Store) Close() {
 	s.async.Close()
 	s.sfcapi.Close()
 	_ = s.closeEpochStore()
+	if s.ancient != nil {
+		_ = s.ancient.Close()
+	}
+	if s.migrationBackup != nil {
+		_ = s.migrationBackup.Close()
+	}
 }
 
 func (s *Store) IsCommitNeeded(epochSealing bool) bool {
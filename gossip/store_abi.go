@@ -0,0 +1,49 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/driver100"
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/driverauth100"
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/netinit100"
+	"github.com/skyhighblockchain/skyhigh/gossip/contract/sfc100"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/driver"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/driverauth"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/netinit"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/sfc"
+)
+
+// builtinContractABIs are ABIs of the system contracts, which are known without registration
+var builtinContractABIs = map[common.Address]string{
+	sfc.ContractAddress:        sfc100.ContractABI,
+	driver.ContractAddress:     driver100.ContractABI,
+	driverauth.ContractAddress: driverauth100.ContractABI,
+	netinit.ContractAddress:    netinit100.ContractABI,
+}
+
+// SetContractABI registers ABI JSON of the contract
+func (s *Store) SetContractABI(addr common.Address, abiJSON string) {
+	if err := s.table.ContractABIs.Put(addr.Bytes(), []byte(abiJSON)); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// DelContractABI unregisters ABI of the contract.
+// ABIs of the system contracts remain known after the deletion.
+func (s *Store) DelContractABI(addr common.Address) {
+	if err := s.table.ContractABIs.Delete(addr.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+// GetContractABI returns ABI JSON of the contract, or empty string if contract ABI isn't known
+func (s *Store) GetContractABI(addr common.Address) string {
+	buf, err := s.table.ContractABIs.Get(addr.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf != nil {
+		return string(buf)
+	}
+	return builtinContractABIs[addr]
+}
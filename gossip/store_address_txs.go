@@ -0,0 +1,115 @@
+package gossip
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/evmstore"
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// transferEventID is the topic of ERC20 and ERC721 Transfer(address,address,uint256) events
+var transferEventID = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// forEachAddressTx calls onAddressTx for each address touched by the block txs.
+// Txs and receipts are expected to be not skipped txs of the block, in the execution order.
+func forEachAddressTx(signer types.Signer, n idx.Block, txs types.Transactions, receipts types.Receipts, onAddressTx func(common.Address, evmstore.AddressTx)) {
+	for i, tx := range txs {
+		touched := make(map[common.Address]evmstore.AddressTxFlags)
+		touch := func(addr common.Address, flag evmstore.AddressTxFlags) {
+			// internal txs are sent from zero address
+			if addr != (common.Address{}) {
+				touched[addr] |= flag
+			}
+		}
+
+		// sender of an internal tx can't be recovered, as internal txs aren't signed
+		if from, err := types.Sender(signer, tx); err == nil {
+			touch(from, evmstore.AddressTxFrom)
+		}
+		if tx.To() != nil {
+			touch(*tx.To(), evmstore.AddressTxTo)
+		}
+		if i < len(receipts) {
+			r := receipts[i]
+			if tx.To() == nil {
+				touch(r.ContractAddress, evmstore.AddressTxTo)
+			}
+			for _, l := range r.Logs {
+				if len(l.Topics) < 3 || l.Topics[0] != transferEventID {
+					continue
+				}
+				touch(common.BytesToAddress(l.Topics[1].Bytes()), evmstore.AddressTxTransferFrom)
+				touch(common.BytesToAddress(l.Topics[2].Bytes()), evmstore.AddressTxTransferTo)
+			}
+		}
+
+		for addr, flags := range touched {
+			onAddressTx(addr, evmstore.AddressTx{
+				Block:       n,
+				BlockOffset: uint32(i),
+				TxHash:      tx.Hash(),
+				Flags:       flags,
+			})
+		}
+	}
+}
+
+func (s *Store) txSigner() types.Signer {
+	return types.LatestSignerForChainID(s.GetRules().EvmChainConfig().ChainID)
+}
+
+// IndexAddressTxs indexes the addresses touched by the block txs
+func (s *Store) IndexAddressTxs(n idx.Block, txs types.Transactions, receipts types.Receipts) {
+	forEachAddressTx(s.txSigner(), n, txs, receipts, s.evm.SetAddressTx)
+}
+
+// unindexAddressTxs deletes the index of addresses touched by the block txs
+func (s *Store) unindexAddressTxs(n idx.Block, block *inter.Block, receipts types.Receipts) {
+	// skip the senders recovery if the index is disabled
+	if !s.evm.HasAddressTxs() {
+		return
+	}
+	txs := s.getBlockTxs(block)
+	forEachAddressTx(s.txSigner(), n, txs, receipts, func(addr common.Address, tx evmstore.AddressTx) {
+		s.evm.DelAddressTx(addr, tx.Block, tx.BlockOffset)
+	})
+}
+
+// getBlockTxs returns not skipped block txs, in the execution order.
+// Returns nil if some of non-event txs or event payloads aren't found, as positions of the txs are unknown then.
+func (s *Store) getBlockTxs(block *inter.Block) types.Transactions {
+	txs := make(types.Transactions, 0, len(block.InternalTxs)+len(block.Txs)+len(block.Events)*10)
+	for _, ids := range [][]common.Hash{block.InternalTxs, block.Txs} {
+		for _, txid := range ids {
+			tx := s.evm.GetTx(txid)
+			if tx == nil {
+				return nil
+			}
+			txs = append(txs, tx)
+		}
+	}
+	for _, id := range block.Events {
+		e := s.GetEventPayload(id)
+		if e == nil {
+			return nil
+		}
+		txs = append(txs, e.Txs()...)
+	}
+
+	if len(block.SkippedTxs) == 0 {
+		return txs
+	}
+	skipCount := 0
+	res := make(types.Transactions, 0, len(txs))
+	for i, tx := range txs {
+		if skipCount < len(block.SkippedTxs) && block.SkippedTxs[skipCount] == uint32(i) {
+			skipCount++
+		} else {
+			res = append(res, tx)
+		}
+	}
+	return res
+}
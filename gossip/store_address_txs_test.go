@@ -0,0 +1,155 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+	"github.com/skyhighblockchain/skyhigh/gossip/evmstore"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+func TestStore_IndexAddressTxs(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+	rules := skyhigh.FakeNetRules()
+	store.SetBlockEpochState(blockproc.BlockState{DirtyRules: rules}, blockproc.EpochState{Rules: rules})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	signer := types.LatestSignerForChainID(rules.EvmChainConfig().ChainID)
+	var (
+		recipient = common.Address{1}
+		contract  = common.Address{2}
+		holder    = common.Address{3}
+		driver    = common.Address{4}
+	)
+
+	internalTx := types.NewTransaction(0, driver, big.NewInt(0), 0, big.NewInt(0), nil)
+	transferTx, err := types.SignTx(types.NewTransaction(0, recipient, big.NewInt(1), 21000, big.NewInt(1), nil), signer, key)
+	require.NoError(err)
+	createTx, err := types.SignTx(types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(1), nil), signer, key)
+	require.NoError(err)
+	tokenTx, err := types.SignTx(types.NewTransaction(2, contract, big.NewInt(0), 100000, big.NewInt(1), nil), signer, key)
+	require.NoError(err)
+
+	txs := types.Transactions{internalTx, transferTx, createTx, tokenTx}
+	receipts := types.Receipts{
+		{},
+		{},
+		{ContractAddress: contract},
+		{Logs: []*types.Log{{
+			Address: contract,
+			Topics:  []common.Hash{transferEventID, sender.Hash(), holder.Hash(), {}},
+		}}},
+	}
+	store.IndexAddressTxs(5, txs, receipts)
+
+	collect := func(addr common.Address, from idx.Block, fromOffset uint32) []evmstore.AddressTx {
+		var res []evmstore.AddressTx
+		store.evm.ForEachAddressTx(addr, from, fromOffset, func(tx evmstore.AddressTx) bool {
+			res = append(res, tx)
+			return true
+		})
+		return res
+	}
+
+	require.Equal([]evmstore.AddressTx{
+		{Block: 5, BlockOffset: 1, TxHash: transferTx.Hash(), Flags: evmstore.AddressTxFrom},
+		{Block: 5, BlockOffset: 2, TxHash: createTx.Hash(), Flags: evmstore.AddressTxFrom},
+		{Block: 5, BlockOffset: 3, TxHash: tokenTx.Hash(), Flags: evmstore.AddressTxFrom | evmstore.AddressTxTransferFrom},
+	}, collect(sender, 0, 0))
+	require.Equal([]evmstore.AddressTx{
+		{Block: 5, BlockOffset: 3, TxHash: tokenTx.Hash(), Flags: evmstore.AddressTxFrom | evmstore.AddressTxTransferFrom},
+	}, collect(sender, 5, 3))
+	require.Empty(collect(sender, 6, 0))
+
+	require.Equal([]evmstore.AddressTx{
+		{Block: 5, BlockOffset: 0, TxHash: internalTx.Hash(), Flags: evmstore.AddressTxTo},
+	}, collect(driver, 0, 0))
+	require.Equal([]evmstore.AddressTx{
+		{Block: 5, BlockOffset: 2, TxHash: createTx.Hash(), Flags: evmstore.AddressTxTo},
+		{Block: 5, BlockOffset: 3, TxHash: tokenTx.Hash(), Flags: evmstore.AddressTxTo},
+	}, collect(contract, 0, 0))
+	require.Equal([]evmstore.AddressTx{
+		{Block: 5, BlockOffset: 3, TxHash: tokenTx.Hash(), Flags: evmstore.AddressTxTransferTo},
+	}, collect(holder, 0, 0))
+	// internal txs are sent from zero address, which isn't indexed
+	require.Empty(collect(common.Address{}, 0, 0))
+	require.True(store.evm.HasAddressTxs())
+}
+
+func TestStore_UnindexAddressTxsOfPrunedEvents(t *testing.T) {
+	require := require.New(t)
+
+	store := NewMemStore()
+	defer store.Close()
+	rules := skyhigh.FakeNetRules()
+	store.SetBlockEpochState(blockproc.BlockState{DirtyRules: rules}, blockproc.EpochState{Rules: rules})
+
+	recipient := common.Address{1}
+	block := &inter.Block{}
+	var txs types.Transactions
+	for i := 0; i < 2; i++ {
+		tx := types.NewTransaction(uint64(i), recipient, big.NewInt(1), 21000, big.NewInt(1), nil)
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(1)
+		me.SetCreator(1)
+		me.SetSeq(idx.Event(i + 1))
+		me.SetLamport(idx.Lamport(i + 1))
+		me.SetParents(hash.Events{})
+		me.SetTxs(types.Transactions{tx})
+		e := me.Build()
+		store.SetEvent(e)
+		block.Events = append(block.Events, e.ID())
+		txs = append(txs, tx)
+	}
+	receipts := types.Receipts{{}, {}}
+	store.IndexAddressTxs(5, txs, receipts)
+
+	collect := func() []uint32 {
+		var res []uint32
+		store.evm.ForEachAddressTx(recipient, 0, 0, func(tx evmstore.AddressTx) bool {
+			res = append(res, tx.BlockOffset)
+			return true
+		})
+		return res
+	}
+	require.Equal([]uint32{0, 1}, collect())
+
+	// positions of the txs are unknown, so the index isn't touched
+	pruned := store.GetEventPayload(block.Events[0])
+	store.DelEvent(block.Events[0])
+	store.unindexAddressTxs(5, block, receipts)
+	require.Equal([]uint32{0, 1}, collect())
+
+	store.SetEvent(pruned)
+	store.unindexAddressTxs(5, block, receipts)
+	require.Empty(collect())
+}
+
+func TestAddressTxDirectionFlags(t *testing.T) {
+	require := require.New(t)
+
+	from, err := addressTxDirectionFlags("from")
+	require.NoError(err)
+	to, err := addressTxDirectionFlags("to")
+	require.NoError(err)
+	any, err := addressTxDirectionFlags("")
+	require.NoError(err)
+	require.Equal(any, from|to)
+	require.Zero(from & to)
+	_, err = addressTxDirectionFlags("sideways")
+	require.Error(err)
+}
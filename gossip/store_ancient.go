@@ -0,0 +1,275 @@
+package gossip
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/skyhighblockchain/push-base/common/bigendian"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/utils/migration"
+)
+
+const (
+	ancientBlocksTable = "blocks"
+	ancientEventsTable = "events"
+)
+
+// getAncientBlock returns a block from the ancient store
+func (s *Store) getAncientBlock(n idx.Block) *inter.Block {
+	if s.ancient == nil || !s.ancient.Table(ancientBlocksTable).Has(uint64(n)) {
+		return nil
+	}
+	buf, err := s.ancient.Table(ancientBlocksTable).Retrieve(uint64(n))
+	if err != nil {
+		s.Log.Crit("Failed to read ancient block", "block", n, "err", err)
+	}
+	block := &inter.Block{}
+	if err := rlp.DecodeBytes(buf, block); err != nil {
+		s.Log.Crit("Failed to decode block", "err", err)
+	}
+	return block
+}
+
+// forEachAncientBlock iterates the blocks in the ancient store, which are already pruned from the key-value DB
+func (s *Store) forEachAncientBlock(fn func(index idx.Block, block *inter.Block)) {
+	if s.ancient == nil {
+		return
+	}
+	blocks := s.ancient.Table(ancientBlocksTable)
+	for n := blocks.Tail(); n < blocks.Head(); n++ {
+		if has, _ := s.table.Blocks.Has(idx.Block(n).Bytes()); has {
+			// appended but not pruned yet
+			continue
+		}
+		fn(idx.Block(n), s.getAncientBlock(idx.Block(n)))
+	}
+}
+
+// getAncientEventRLP returns a serialized event from the ancient store
+func (s *Store) getAncientEventRLP(id hash.Event) rlp.RawValue {
+	if s.ancient == nil {
+		return nil
+	}
+	item, err := s.table.AncientEvents.Get(id.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if item == nil {
+		return nil
+	}
+	return s.retrieveAncientEvent(bigendian.BytesToUint64(item))
+}
+
+func (s *Store) retrieveAncientEvent(item uint64) rlp.RawValue {
+	buf, err := s.ancient.Table(ancientEventsTable).Retrieve(item)
+	if err != nil {
+		s.Log.Crit("Failed to read ancient event", "item", item, "err", err)
+	}
+	return buf
+}
+
+// getAncientEventPayload returns an event from the ancient store
+func (s *Store) getAncientEventPayload(id hash.Event) *inter.EventPayload {
+	buf := s.getAncientEventRLP(id)
+	if buf == nil {
+		return nil
+	}
+	e := &inter.EventPayload{}
+	if err := rlp.DecodeBytes(buf, e); err != nil {
+		s.Log.Crit("Failed to decode event", "err", err)
+	}
+	return e
+}
+
+// forEachAncientEventRLP iterates the ancient events in the same order as the key-value table of events.
+// All ancient events belong to older epochs than events in the key-value DB, so the ancient events go first.
+// Returns false if the iteration was stopped by onEvent.
+func (s *Store) forEachAncientEventRLP(prefix, start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) bool {
+	if s.ancient == nil {
+		return true
+	}
+	it := s.table.AncientEvents.NewIterator(prefix, start)
+	defer it.Release()
+	for it.Next() {
+		if !onEvent(hash.BytesToEvent(it.Key()), s.retrieveAncientEvent(bigendian.BytesToUint64(it.Value()))) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Store) getAncientHead(name string) (uint64, bool) {
+	buf, err := s.table.AncientHeads.Get([]byte(name))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 0, false
+	}
+	return bigendian.BytesToUint64(buf), true
+}
+
+func (s *Store) setAncientHead(name string, head uint64) {
+	if err := s.table.AncientHeads.Put([]byte(name), bigendian.Uint64ToBytes(head)); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// repairAncientEvents drops the ancient events, which were appended but not indexed in the key-value DB before an interruption.
+// Such events are still in the key-value DB, so they are appended again by the next migration.
+func (s *Store) repairAncientEvents() {
+	table := s.ancient.Table(ancientEventsTable)
+	head, ok := s.getAncientHead(ancientEventsTable)
+	if !ok {
+		it := s.table.AncientEvents.NewIterator(nil, nil)
+		indexed := it.Next()
+		it.Release()
+		if indexed {
+			return
+		}
+		head = table.Tail()
+	}
+	if head >= table.Head() {
+		return
+	}
+	s.Log.Warn("Dropping unindexed ancient events", "head", table.Head(), "indexed", head)
+	if err := table.TruncateHead(head); err != nil {
+		s.Log.Crit("Failed to truncate ancient events", "err", err)
+	}
+}
+
+// MigrateToAncient moves up to limit blocks (with their txs and receipts) and up to limit events
+// from the key-value DB into the ancient store. Only data of epochs, which are older than
+// the configured number of recent epochs, is moved.
+// Returns true if there's nothing left to migrate.
+func (s *Store) MigrateToAncient(limit int) (bool, error) {
+	if s.ancient == nil || s.cfg.Ancient.Epochs == 0 {
+		return true, nil
+	}
+	if limit <= 0 {
+		return false, fmt.Errorf("migration limit has to be positive")
+	}
+	epoch := s.GetEpoch()
+	if epoch <= s.cfg.Ancient.Epochs {
+		return true, nil
+	}
+	cut := epoch - s.cfg.Ancient.Epochs
+
+	blocks, err := s.appendAncientBlocks(cut, limit)
+	if err != nil {
+		return false, err
+	}
+	events, err := s.appendAncientEvents(cut, limit)
+	if err != nil {
+		return false, err
+	}
+	// the ancient store must be flushed before the data is deleted from the key-value DB
+	if err := s.ancient.Sync(); err != nil {
+		return false, err
+	}
+
+	for _, b := range blocks {
+		if err := s.table.Blocks.Delete(b.n.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+		s.evm.PruneAncient(b.n, b.txs)
+	}
+	for _, e := range events {
+		if err := s.table.AncientEvents.Put(e.id.Bytes(), bigendian.Uint64ToBytes(e.item)); err != nil {
+			s.Log.Crit("Failed to put key-value", "err", err)
+		}
+		if err := s.table.Events.Delete(e.id.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+	if len(events) != 0 {
+		s.setAncientHead(ancientEventsTable, s.ancient.Table(ancientEventsTable).Head())
+	}
+
+	return len(blocks) < limit && len(events) < limit, nil
+}
+
+type ancientBlock struct {
+	n   idx.Block
+	txs []common.Hash
+}
+
+// appendAncientBlocks appends the first blocks of the key-value DB, which Atroposes are older than the cut epoch.
+func (s *Store) appendAncientBlocks(cut idx.Epoch, limit int) ([]ancientBlock, error) {
+	table := s.ancient.Table(ancientBlocksTable)
+	res := make([]ancientBlock, 0, limit)
+
+	it := s.table.Blocks.NewIterator(nil, nil)
+	defer it.Release()
+	for len(res) < limit && it.Next() {
+		n := idx.BytesToBlock(it.Key())
+		var block inter.Block
+		if err := rlp.DecodeBytes(it.Value(), &block); err != nil {
+			return nil, err
+		}
+		if block.Atropos.Epoch() >= cut {
+			break
+		}
+		if table.Items() == 0 || uint64(n) == table.Head() {
+			if err := table.Append(uint64(n), it.Value()); err != nil {
+				return nil, err
+			}
+		} else if uint64(n) > table.Head() {
+			return nil, fmt.Errorf("ancient blocks gap: block %d, ancient head %d", n, table.Head())
+		}
+		// else the block is already appended, but wasn't deleted from the key-value DB before an interruption
+		txs := append(append(make([]common.Hash, 0, len(block.InternalTxs)+len(block.Txs)), block.InternalTxs...), block.Txs...)
+		if err := s.evm.AppendAncient(n, txs); err != nil {
+			return nil, err
+		}
+		res = append(res, ancientBlock{n, txs})
+	}
+	return res, it.Error()
+}
+
+type ancientEvent struct {
+	id   hash.Event
+	item uint64
+}
+
+// appendAncientEvents appends the first events of the key-value DB, which are older than the cut epoch.
+func (s *Store) appendAncientEvents(cut idx.Epoch, limit int) ([]ancientEvent, error) {
+	table := s.ancient.Table(ancientEventsTable)
+	res := make([]ancientEvent, 0, limit)
+
+	it := s.table.Events.NewIterator(nil, nil)
+	defer it.Release()
+	for len(res) < limit && it.Next() {
+		id := hash.BytesToEvent(it.Key())
+		if id.Epoch() >= cut {
+			break
+		}
+		item := table.Head()
+		if err := table.Append(item, it.Value()); err != nil {
+			return nil, err
+		}
+		res = append(res, ancientEvent{id, item})
+	}
+	return res, it.Error()
+}
+
+// migrateToAncientAll moves all the old data into the ancient store.
+// The migration is resumed after an interruption without a checkpoint, because the moved data is deleted from the key-value DB.
+func (s *Store) migrateToAncientAll(p *migration.Progress) error {
+	for {
+		done, err := s.MigrateToAncient(s.cfg.Ancient.MaxBatch)
+		if err != nil || done {
+			return err
+		}
+		p.Report(s.ancient.Table(ancientBlocksTable).Head())
+		if s.IsCommitNeeded(false) {
+			if err := s.Commit(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package gossip
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb/flushable"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+func TestStore_MigrateToAncient(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.Ancient.Dir = t.TempDir()
+	cfg.Ancient.Epochs = 2
+	// receipts of only the last block are cached
+	cfg.EVM.Cache.ReceiptsBlocks = 1
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	defer store.Close()
+
+	const epochs = 6
+	var (
+		events []*inter.EventPayload
+		txs    []*types.Transaction
+	)
+	for epoch := idx.Epoch(1); epoch <= epochs; epoch++ {
+		var atropos hash.Event
+		for creator := idx.ValidatorID(1); creator <= 3; creator++ {
+			me := &inter.MutableEventPayload{}
+			me.SetEpoch(epoch)
+			me.SetCreator(creator)
+			me.SetSeq(1)
+			me.SetLamport(idx.Lamport(creator))
+			me.SetParents(hash.Events{})
+			me.SetTxs(types.Transactions{})
+			e := me.Build()
+			store.SetEvent(e)
+			events = append(events, e)
+			atropos = e.ID()
+		}
+
+		n := idx.Block(epoch)
+		tx := types.NewTransaction(uint64(n), common.Address{1}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		store.EvmStore().SetTx(tx.Hash(), tx)
+		txs = append(txs, tx)
+		store.EvmStore().SetReceipts(n, types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(n), Logs: []*types.Log{}}})
+		store.SetBlock(n, &inter.Block{
+			Atropos:     atropos,
+			InternalTxs: []common.Hash{tx.Hash()},
+		})
+	}
+	store.SetBlockEpochState(blockproc.BlockState{DirtyRules: skyhigh.FakeNetRules()}, blockproc.EpochState{Epoch: epochs, Rules: skyhigh.FakeNetRules()})
+
+	// the migration is done in batches
+	done, err := store.MigrateToAncient(5)
+	require.NoError(err)
+	require.False(done)
+
+	// simulate an interruption after the events are appended, but before they're indexed
+	ancientEvents := store.ancient.Table(ancientEventsTable)
+	head := ancientEvents.Head()
+	require.NoError(ancientEvents.Append(head, []byte("unindexed")))
+	store.repairAncientEvents()
+	require.Equal(head, ancientEvents.Head())
+
+	done, err = store.MigrateToAncient(5)
+	require.NoError(err)
+	require.True(done)
+
+	// data of the first epochs is moved, data of the recent epochs is kept in the key-value DB
+	cut := idx.Block(epochs - cfg.Ancient.Epochs)
+	for n := idx.Block(1); n <= epochs; n++ {
+		has, err := store.table.Blocks.Has(n.Bytes())
+		require.NoError(err)
+		require.Equal(n >= cut, has, n)
+		has, err = store.mainDB.Has(append([]byte("X"), txs[n-1].Hash().Bytes()...))
+		require.NoError(err)
+		require.Equal(n >= cut, has, n)
+	}
+	for _, e := range events {
+		has, err := store.table.Events.Has(e.ID().Bytes())
+		require.NoError(err)
+		require.Equal(e.Epoch() >= idx.Epoch(cut), has, e.ID().String())
+	}
+
+	// check the getters on non-cached store
+	store.initCache()
+	for n := idx.Block(1); n <= epochs; n++ {
+		block := store.GetBlock(n)
+		require.NotNil(block, n)
+		require.Equal(idx.Epoch(n), block.Atropos.Epoch())
+		require.Equal(txs[n-1].Hash(), store.EvmStore().GetTx(txs[n-1].Hash()).Hash())
+		receipts := store.EvmStore().GetReceipts(n)
+		require.Len(receipts, 1)
+		require.Equal(uint64(n), receipts[0].CumulativeGasUsed)
+	}
+	for _, e := range events {
+		require.True(store.HasEvent(e.ID()))
+		require.Equal(e.ID(), store.GetEvent(e.ID()).ID())
+		require.Equal(e.ID(), store.GetEventPayload(e.ID()).ID())
+		expected, _ := rlp.EncodeToBytes(e)
+		require.Equal(expected, []byte(store.GetEventPayloadRLP(e.ID())))
+	}
+	require.Nil(store.GetBlock(epochs + 1))
+	// events of the first 3 epochs are moved without duplicates
+	require.Equal(uint64(3*3), ancientEvents.Items())
+
+	// iteration returns the ancient data first
+	var blocks []idx.Block
+	store.ForEachBlock(func(n idx.Block, block *inter.Block) {
+		blocks = append(blocks, n)
+	})
+	require.Equal([]idx.Block{1, 2, 3, 4, 5, 6}, blocks)
+
+	var got hash.Events
+	store.ForEachEvent(1, func(e *inter.EventPayload) bool {
+		got = append(got, e.ID())
+		return true
+	})
+	require.Len(got, len(events))
+	for i := 1; i < len(got); i++ {
+		require.Equal(-1, bytes.Compare(got[i-1].Bytes(), got[i].Bytes()))
+	}
+	got = got[:0]
+	store.ForEachEventRLP(idx.Epoch(2).Bytes(), func(id hash.Event, _ rlp.RawValue) bool {
+		got = append(got, id)
+		return len(got) < 4
+	})
+	require.Len(got, 4)
+	require.Equal(idx.Epoch(2), got[0].Epoch())
+	require.Equal(idx.Epoch(3), got[3].Epoch())
+
+	got = got[:0]
+	store.ForEachEpochEvent(1, func(e *inter.EventPayload) bool {
+		got = append(got, e.ID())
+		return true
+	})
+	require.Len(got, 3)
+	require.Equal(hash.Events{events[1].ID()}, store.FindEventHashes(1, 2, nil))
+	require.Equal(hash.Events{events[len(events)-1].ID()}, store.FindEventHashes(epochs, 3, nil))
+}
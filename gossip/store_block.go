@@ -1,6 +1,8 @@
 package gossip
 
 import (
+	"sort"
+
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/skyhighblockchain/push-base/hash"
 	"github.com/skyhighblockchain/push-base/inter/idx"
@@ -43,6 +45,9 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 	}
 
 	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
+	if block == nil {
+		block = s.getAncientBlock(n)
+	}
 
 	// Add to LRU cache.
 	if block != nil {
@@ -53,6 +58,8 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 }
 
 func (s *Store) ForEachBlock(fn func(index idx.Block, block *inter.Block)) {
+	s.forEachAncientBlock(fn)
+
 	it := s.table.Blocks.NewIterator(nil, nil)
 	defer it.Release()
 	for it.Next() {
@@ -130,3 +137,34 @@ func (s *Store) GetGenesisTime() inter.Timestamp {
 	}
 	return block.Time
 }
+
+// GetEpochBlocks returns a range of blocks which Atroposes belong to the epoch.
+// Blocks are ordered by epochs, so the range is found by a binary search.
+func (s *Store) GetEpochBlocks(epoch idx.Epoch) (first, last idx.Block, ok bool) {
+	genesis := s.GetGenesisBlockIndex()
+	if genesis == nil {
+		return 0, 0, false
+	}
+	latest := s.GetLatestBlockIndex()
+	blockEpoch := func(n idx.Block) idx.Epoch {
+		block := s.GetBlock(n)
+		if block == nil {
+			return 0
+		}
+		return block.Atropos.Epoch()
+	}
+	start := *genesis + 1
+	if start > latest {
+		return 0, 0, false
+	}
+	first = start + idx.Block(sort.Search(int(latest-start+1), func(i int) bool {
+		return blockEpoch(start+idx.Block(i)) >= epoch
+	}))
+	last = start + idx.Block(sort.Search(int(latest-start+1), func(i int) bool {
+		return blockEpoch(start+idx.Block(i)) > epoch
+	}))
+	if first == last {
+		return 0, 0, false
+	}
+	return first, last - 1, true
+}
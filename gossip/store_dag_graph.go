@@ -0,0 +1,82 @@
+package gossip
+
+import (
+	"errors"
+
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/dagexport"
+)
+
+// ErrTooManyEvents is returned if DAG subgraph exceeds the limit of events.
+var ErrTooManyEvents = errors.New("too many events, narrow the range")
+
+// GetDagGraph returns a subgraph of the epoch DAG with events within [from, to] Lamport range.
+// to=0 means no upper bound, limit=0 means no limit of events.
+// Each event is supplied with root/Atropos flags and the block which confirmed it.
+func (s *Store) GetDagGraph(epoch idx.Epoch, from, to idx.Lamport, limit int) (*dagexport.Graph, error) {
+	g := &dagexport.Graph{
+		Epoch: epoch,
+		Nodes: make([]dagexport.Node, 0, 1000),
+	}
+	positions := make(map[hash.Event]int)
+
+	var err error
+	s.ForEachEpochEventFrom(epoch, from, func(e *inter.EventPayload) bool {
+		if to != 0 && e.Lamport() > to {
+			return false
+		}
+		if limit != 0 && len(g.Nodes) >= limit {
+			err = ErrTooManyEvents
+			return false
+		}
+		n := dagexport.NewNode(e)
+		if e.SelfParent() == nil {
+			n.Root = true
+		} else if selfParent := s.GetEvent(*e.SelfParent()); selfParent != nil {
+			n.Root = selfParent.Frame() < e.Frame()
+		}
+		positions[n.ID] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, n)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first, last, ok := s.GetEpochBlocks(epoch)
+	if !ok {
+		return g, nil
+	}
+	// event is confirmed by the first block which Atropos observes it
+	confirmed := make(map[hash.Event]bool)
+	for n := first; n <= last; n++ {
+		block := n
+		atropos := s.GetBlock(n).Atropos
+		if pos, ok := positions[atropos]; ok {
+			g.Nodes[pos].Atropos = true
+		}
+		stack := hash.Events{atropos}
+		for len(stack) != 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			// ancestors of events before the range cannot be within the range
+			if confirmed[id] || id.Lamport() < from {
+				continue
+			}
+			confirmed[id] = true
+			if pos, ok := positions[id]; ok {
+				g.Nodes[pos].Block = &block
+			}
+			e := s.GetEvent(id)
+			if e == nil {
+				// the event is pruned
+				continue
+			}
+			stack = append(stack, e.Parents()...)
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,113 @@
+package gossip
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/evmcore"
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+func TestStore_GetDagGraph(t *testing.T) {
+	store := NewMemStore()
+
+	newEvent := func(creator idx.ValidatorID, seq idx.Event, lamport idx.Lamport, frame idx.Frame, parents ...*inter.EventPayload) *inter.EventPayload {
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(1)
+		me.SetCreator(creator)
+		me.SetSeq(seq)
+		me.SetLamport(lamport)
+		me.SetFrame(frame)
+		ids := hash.Events{}
+		for _, p := range parents {
+			ids.Add(p.ID())
+		}
+		me.SetParents(ids)
+		me.SetTxs(types.Transactions{})
+		e := me.Build()
+		store.SetEvent(e)
+		return e
+	}
+
+	a1 := newEvent(1, 1, 1, 1)
+	b1 := newEvent(2, 1, 1, 1)
+	a2 := newEvent(1, 2, 2, 1, a1, b1)
+	b2 := newEvent(2, 2, 3, 2, b1, a2)
+	a3 := newEvent(1, 3, 4, 2, a2, b2)
+	b3 := newEvent(2, 3, 5, 2, b2, a3)
+
+	store.SetGenesisBlockIndex(0)
+	store.SetBlock(0, &inter.Block{})
+	store.SetBlock(1, &inter.Block{Atropos: a2.ID()})
+	store.SetBlock(2, &inter.Block{Atropos: a3.ID()})
+	store.SetBlockEpochState(blockproc.BlockState{
+		LastBlock:  blockproc.BlockCtx{Idx: 2},
+		DirtyRules: skyhigh.FakeNetRules(),
+	}, blockproc.EpochState{
+		Epoch: 1,
+		Rules: skyhigh.FakeNetRules(),
+	})
+
+	g, err := store.GetDagGraph(1, 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, g.Nodes, 6)
+	nodes := make(map[hash.Event]int)
+	for i, n := range g.Nodes {
+		nodes[n.ID] = i
+	}
+	block := func(e *inter.EventPayload) *idx.Block {
+		return g.Nodes[nodes[e.ID()]].Block
+	}
+	for _, e := range []*inter.EventPayload{a1, b1, a2} {
+		require.Equal(t, idx.Block(1), *block(e))
+	}
+	for _, e := range []*inter.EventPayload{b2, a3} {
+		require.Equal(t, idx.Block(2), *block(e))
+	}
+	require.Nil(t, block(b3))
+	require.True(t, g.Nodes[nodes[a2.ID()]].Atropos)
+	require.True(t, g.Nodes[nodes[a3.ID()]].Atropos)
+	require.False(t, g.Nodes[nodes[b2.ID()]].Atropos)
+	require.True(t, g.Nodes[nodes[a1.ID()]].Root)
+	require.False(t, g.Nodes[nodes[a2.ID()]].Root)
+	require.True(t, g.Nodes[nodes[b2.ID()]].Root)
+	require.False(t, g.Nodes[nodes[b3.ID()]].Root)
+
+	// Lamport range
+	g, err = store.GetDagGraph(1, 2, 4, 0)
+	require.NoError(t, err)
+	require.Len(t, g.Nodes, 3)
+	require.Equal(t, a2.ID(), g.Nodes[0].ID)
+	require.Equal(t, a3.ID(), g.Nodes[2].ID)
+	require.Equal(t, idx.Block(1), *g.Nodes[0].Block)
+
+	_, err = store.GetDagGraph(1, 0, 0, 5)
+	require.Equal(t, ErrTooManyEvents, err)
+
+	// the epoch is pruned partially
+	store.DelEvent(a1.ID())
+	store.DelEvent(b1.ID())
+	store.DelEvent(a2.ID())
+	g, err = store.GetDagGraph(1, 0, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, g.Nodes, 3)
+	require.Equal(t, b2.ID(), g.Nodes[0].ID)
+	require.False(t, g.Nodes[0].Root)
+	require.Equal(t, idx.Block(2), *g.Nodes[0].Block)
+
+	// the pruned epoch isn't available through the API
+	store.setHistoryEpochStart(2)
+	backend := &EthAPIBackend{svc: &Service{store: store, engineMu: new(sync.RWMutex)}}
+	_, err = backend.GetDagGraph(context.Background(), 1, 0, 0, 0)
+	require.ErrorIs(t, err, evmcore.ErrHistoryPruned)
+	_, err = backend.GetDoubleSigns(context.Background(), 1)
+	require.ErrorIs(t, err, evmcore.ErrHistoryPruned)
+}
@@ -0,0 +1,46 @@
+package gossip
+
+import (
+	"sort"
+
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// FindDoubleSigns scans all the epoch events and returns pairs of distinct events
+// with the same creator and seq. Each pair is a proof of creator's fork.
+// Result is sorted by creator and seq.
+func (s *Store) FindDoubleSigns(epoch idx.Epoch) []inter.DoubleSign {
+	type creatorSeq struct {
+		creator idx.ValidatorID
+		seq     idx.Event
+	}
+	firsts := make(map[creatorSeq]hash.Event)
+	res := make([]inter.DoubleSign, 0)
+
+	s.ForEachEpochEvent(epoch, func(e *inter.EventPayload) bool {
+		key := creatorSeq{e.Creator(), e.Seq()}
+		first, ok := firsts[key]
+		if !ok {
+			firsts[key] = e.ID()
+			return true
+		}
+		fixEventTxHashes(e)
+		res = append(res, inter.DoubleSign{
+			First:  s.GetEventPayload(first),
+			Second: e,
+		})
+		return true
+	})
+
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Creator() != b.Creator() {
+			return a.Creator() < b.Creator()
+		}
+		return a.Seq() < b.Seq()
+	})
+	return res
+}
@@ -0,0 +1,52 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+func TestStore_FindDoubleSigns(t *testing.T) {
+	store := NewMemStore()
+
+	newEvent := func(epoch idx.Epoch, creator idx.ValidatorID, seq idx.Event, lamport idx.Lamport, extra byte) *inter.EventPayload {
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(epoch)
+		me.SetCreator(creator)
+		me.SetSeq(seq)
+		me.SetLamport(lamport)
+		me.SetParents(hash.Events{})
+		me.SetExtra([]byte{extra})
+		me.SetTxs(types.Transactions{})
+		e := me.Build()
+		store.SetEvent(e)
+		return e
+	}
+
+	newEvent(1, 1, 1, 1, 0)
+	newEvent(1, 2, 1, 1, 0)
+	a := newEvent(1, 2, 2, 2, 0)
+	b := newEvent(1, 2, 2, 3, 1)
+	newEvent(1, 3, 1, 1, 0)
+	// events of other epochs aren't taken into account
+	newEvent(2, 3, 1, 1, 0)
+	c := newEvent(2, 3, 1, 2, 1)
+
+	res := store.FindDoubleSigns(1)
+	require.Len(t, res, 1)
+	require.Equal(t, idx.ValidatorID(2), res[0].Creator())
+	require.Equal(t, idx.Event(2), res[0].Seq())
+	require.Equal(t, a.ID(), res[0].First.ID())
+	require.Equal(t, b.ID(), res[0].Second.ID())
+
+	res = store.FindDoubleSigns(2)
+	require.Len(t, res, 1)
+	require.Equal(t, c.ID(), res[0].Second.ID())
+
+	require.Empty(t, store.FindDoubleSigns(3))
+}
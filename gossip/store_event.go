@@ -50,6 +50,9 @@ func (s *Store) GetEventPayload(id hash.Event) *inter.EventPayload {
 
 	key := id.Bytes()
 	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
+	if w == nil {
+		w = s.getAncientEventPayload(id)
+	}
 
 	if w != nil {
 		fixEventTxHashes(w)
@@ -74,6 +77,9 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 
 	key := id.Bytes()
 	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
+	if w == nil {
+		w = s.getAncientEventPayload(id)
+	}
 	if w == nil {
 		return nil
 	}
@@ -102,19 +108,50 @@ func (s *Store) forEachEvent(it ethdb.Iterator, onEvent func(event *inter.EventP
 	}
 }
 
+// forEachAncientEvent iterates the ancient events. Returns false if the iteration was stopped by onEvent.
+func (s *Store) forEachAncientEvent(prefix, start []byte, onEvent func(event *inter.EventPayload) bool) bool {
+	return s.forEachAncientEventRLP(prefix, start, func(_ hash.Event, raw rlp.RawValue) bool {
+		event := &inter.EventPayload{}
+		err := rlp.DecodeBytes(raw, event)
+		if err != nil {
+			s.Log.Crit("Failed to decode event", "err", err)
+		}
+		return onEvent(event)
+	})
+}
+
 func (s *Store) ForEachEpochEvent(epoch idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
+	if !s.forEachAncientEvent(epoch.Bytes(), nil, onEvent) {
+		return
+	}
 	it := s.table.Events.NewIterator(epoch.Bytes(), nil)
 	defer it.Release()
 	s.forEachEvent(it, onEvent)
 }
 
+// ForEachEpochEventFrom iterates the epoch events starting from the given Lamport time.
+func (s *Store) ForEachEpochEventFrom(epoch idx.Epoch, lamport idx.Lamport, onEvent func(event *inter.EventPayload) bool) {
+	if !s.forEachAncientEvent(epoch.Bytes(), lamport.Bytes(), onEvent) {
+		return
+	}
+	it := s.table.Events.NewIterator(epoch.Bytes(), lamport.Bytes())
+	defer it.Release()
+	s.forEachEvent(it, onEvent)
+}
+
 func (s *Store) ForEachEvent(start idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
+	if !s.forEachAncientEvent(nil, start.Bytes(), onEvent) {
+		return
+	}
 	it := s.table.Events.NewIterator(nil, start.Bytes())
 	defer it.Release()
 	s.forEachEvent(it, onEvent)
 }
 
 func (s *Store) ForEachEventRLP(start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
+	if !s.forEachAncientEventRLP(nil, start, onEvent) {
+		return
+	}
 	it := s.table.Events.NewIterator(nil, start)
 	defer it.Release()
 	for it.Next() {
@@ -130,6 +167,14 @@ func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix
 	prefix.Write(hashPrefix)
 	res := make(hash.Events, 0, 10)
 
+	if s.ancient != nil {
+		ancientIt := s.table.AncientEvents.NewIterator(prefix.Bytes(), nil)
+		for ancientIt.Next() {
+			res = append(res, hash.BytesToEvent(ancientIt.Key()))
+		}
+		ancientIt.Release()
+	}
+
 	it := s.table.Events.NewIterator(prefix.Bytes(), nil)
 	defer it.Release()
 	for it.Next() {
@@ -147,12 +192,18 @@ func (s *Store) GetEventPayloadRLP(id hash.Event) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if data == nil {
+		data = s.getAncientEventRLP(id)
+	}
 	return data
 }
 
 // HasEvent returns true if event exists.
 func (s *Store) HasEvent(h hash.Event) bool {
 	has, _ := s.table.Events.Has(h.Bytes())
+	if !has && s.ancient != nil {
+		has, _ = s.table.AncientEvents.Has(h.Bytes())
+	}
 	return has
 }
 
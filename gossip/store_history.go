@@ -0,0 +1,247 @@
+package gossip
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// GetHistoryStart returns the first block, which receipts, logs index and txs index aren't pruned
+func (s *Store) GetHistoryStart() idx.Block {
+	buf, err := s.table.HistoryPruning.Get([]byte("b"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 0
+	}
+	return idx.BytesToBlock(buf)
+}
+
+func (s *Store) setHistoryStart(n idx.Block) {
+	if err := s.table.HistoryPruning.Put([]byte("b"), n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// GetHistoryEpochStart returns the first epoch, which event payloads aren't pruned
+func (s *Store) GetHistoryEpochStart() idx.Epoch {
+	buf, err := s.table.HistoryPruning.Get([]byte("e"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 0
+	}
+	return idx.BytesToEpoch(buf)
+}
+
+func (s *Store) setHistoryEpochStart(epoch idx.Epoch) {
+	if err := s.table.HistoryPruning.Put([]byte("e"), epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// getEventsPruningStart returns the first epoch, which events aren't pruned completely
+func (s *Store) getEventsPruningStart() idx.Epoch {
+	buf, err := s.table.HistoryPruning.Get([]byte("c"))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return s.GetHistoryEpochStart()
+	}
+	return idx.BytesToEpoch(buf)
+}
+
+func (s *Store) setEventsPruningStart(epoch idx.Epoch) {
+	if err := s.table.HistoryPruning.Put([]byte("c"), epoch.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// historyTarget returns the first block, which history has to be kept according to the config
+func (s *Store) historyTarget() (idx.Block, bool) {
+	genesis := s.GetGenesisBlockIndex()
+	if genesis == nil {
+		return 0, false
+	}
+	latest := s.GetLatestBlockIndex()
+
+	var (
+		target idx.Block
+		ok     bool
+	)
+	keep := func(n idx.Block) {
+		if !ok || n < target {
+			target = n
+		}
+		ok = true
+	}
+	if s.cfg.History.Blocks != 0 {
+		if latest < s.cfg.History.Blocks {
+			return 0, false
+		}
+		keep(latest - s.cfg.History.Blocks + 1)
+	}
+	if s.cfg.History.Epochs != 0 {
+		epoch := s.GetEpoch()
+		if epoch <= s.cfg.History.Epochs {
+			return 0, false
+		}
+		cut := epoch - s.cfg.History.Epochs
+		// blocks are ordered by epochs, so the first block of the cut epoch is found by a binary search
+		start := *genesis + 1
+		if start > latest {
+			return 0, false
+		}
+		keep(start + idx.Block(sort.Search(int(latest-start+1), func(i int) bool {
+			block := s.GetBlock(start + idx.Block(i))
+			return block == nil || block.Atropos.Epoch() >= cut
+		})))
+	}
+	// the latest block is always kept
+	if target > latest {
+		target = latest
+	}
+	return target, ok
+}
+
+// PruneHistory deletes receipts, logs index and txs index of up to limit old blocks, and up to limit old events.
+// Blocks are kept. Events are pruned only if all the blocks, which include them, are pruned.
+// Returns true if there's nothing left to prune.
+func (s *Store) PruneHistory(limit int) (bool, error) {
+	if s.cfg.History.Blocks == 0 && s.cfg.History.Epochs == 0 {
+		return true, nil
+	}
+	if limit <= 0 {
+		return false, fmt.Errorf("pruning limit has to be positive")
+	}
+	target, ok := s.historyTarget()
+	if !ok {
+		return true, nil
+	}
+
+	n := s.GetHistoryStart()
+	if genesis := s.GetGenesisBlockIndex(); n < *genesis {
+		n = *genesis
+	}
+	blocks := 0
+	for ; n < target && blocks < limit; n++ {
+		s.pruneBlockHistory(n)
+		blocks++
+	}
+	s.setHistoryStart(n)
+
+	// events of the epoch of the first non-pruned block may be still needed
+	epochsCut := s.GetBlock(n).Atropos.Epoch()
+	events := s.pruneEvents(epochsCut, limit)
+
+	return blocks < limit && events < limit, nil
+}
+
+// pruneBlockHistory deletes receipts, logs index and txs index of the block
+func (s *Store) pruneBlockHistory(n idx.Block) {
+	block := s.GetBlock(n)
+	if block == nil {
+		return
+	}
+	txs := s.getBlockTxHashes(block)
+
+	receipts := s.evm.GetReceipts(n)
+	s.unindexAddressTxs(n, block, receipts)
+	var logIndex uint
+	for i, r := range receipts {
+		if i >= len(txs) {
+			break
+		}
+		for _, l := range r.Logs {
+			// logs are stored without the derived fields
+			rec := *l
+			rec.BlockNumber = uint64(n)
+			rec.TxHash = txs[i]
+			rec.Index = logIndex
+			logIndex++
+			s.evm.DelLogs(&rec)
+		}
+	}
+	s.evm.DelReceipts(n)
+
+	for _, txid := range txs {
+		s.evm.DelTxPosition(txid)
+	}
+}
+
+// getBlockTxHashes returns hashes of not skipped block txs, in the execution order
+func (s *Store) getBlockTxHashes(block *inter.Block) []common.Hash {
+	txs := make([]common.Hash, 0, len(block.InternalTxs)+len(block.Txs)+len(block.Events)*10)
+	txs = append(txs, block.InternalTxs...)
+	txs = append(txs, block.Txs...)
+	for _, id := range block.Events {
+		e := s.GetEventPayload(id)
+		if e == nil {
+			continue
+		}
+		for _, tx := range e.Txs() {
+			txs = append(txs, tx.Hash())
+		}
+	}
+
+	if len(block.SkippedTxs) == 0 {
+		return txs
+	}
+	skipCount := 0
+	res := make([]common.Hash, 0, len(txs))
+	for i, tx := range txs {
+		if skipCount < len(block.SkippedTxs) && block.SkippedTxs[skipCount] == uint32(i) {
+			skipCount++
+		} else {
+			res = append(res, tx)
+		}
+	}
+	return res
+}
+
+// pruneEvents deletes up to limit events, which are older than the cut epoch. Returns number of deleted events.
+// An epoch, which events are pruned partially, is considered as pruned.
+func (s *Store) pruneEvents(cut idx.Epoch, limit int) int {
+	start := s.getEventsPruningStart()
+	if start >= cut {
+		return 0
+	}
+
+	ids := make(hash.Events, 0, limit)
+	s.ForEachEventRLP(start.Bytes(), func(id hash.Event, _ rlp.RawValue) bool {
+		if id.Epoch() >= cut || len(ids) >= limit {
+			return false
+		}
+		ids = append(ids, id)
+		return true
+	})
+
+	for _, id := range ids {
+		s.DelEvent(id)
+		if s.ancient != nil {
+			if err := s.table.AncientEvents.Delete(id.Bytes()); err != nil {
+				s.Log.Crit("Failed to delete key", "err", err)
+			}
+		}
+	}
+
+	if len(ids) < limit {
+		s.setEventsPruningStart(cut)
+		s.setHistoryEpochStart(cut)
+	} else {
+		// the last epoch may be pruned partially
+		last := ids[len(ids)-1].Epoch()
+		s.setEventsPruningStart(last)
+		s.setHistoryEpochStart(last + 1)
+	}
+	return len(ids)
+}
@@ -0,0 +1,22 @@
+package gossip
+
+import (
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+)
+
+// SetHistoryEpochState stores the epoch state as it was at the beginning of the epoch
+func (s *Store) SetHistoryEpochState(es blockproc.EpochState) {
+	s.rlp.Set(s.table.HistoryEpochs, es.Epoch.Bytes(), &es)
+}
+
+// GetHistoryEpochState returns the epoch state as it was at the beginning of the epoch,
+// or nil if the epoch state wasn't recorded
+func (s *Store) GetHistoryEpochState(epoch idx.Epoch) *blockproc.EpochState {
+	if es := s.GetEpochState(); es.Epoch == epoch {
+		return &es
+	}
+	es, _ := s.rlp.Get(s.table.HistoryEpochs, epoch.Bytes(), &blockproc.EpochState{}).(*blockproc.EpochState)
+	return es
+}
@@ -0,0 +1,127 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb/flushable"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+	"github.com/skyhighblockchain/skyhigh/gossip/evmstore"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+func TestStore_PruneHistory(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.History.Blocks = 2
+	// receipts of only the last block are cached
+	cfg.EVM.Cache.ReceiptsBlocks = 1
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	defer store.Close()
+
+	const epochs = 6
+	var (
+		events []*inter.EventPayload
+		txs    []*types.Transaction
+		addr   = common.Address{1}
+	)
+	store.SetGenesisBlockIndex(0)
+	for epoch := idx.Epoch(1); epoch <= epochs; epoch++ {
+		var atropos hash.Event
+		for creator := idx.ValidatorID(1); creator <= 3; creator++ {
+			me := &inter.MutableEventPayload{}
+			me.SetEpoch(epoch)
+			me.SetCreator(creator)
+			me.SetSeq(1)
+			me.SetLamport(idx.Lamport(creator))
+			me.SetParents(hash.Events{})
+			me.SetTxs(types.Transactions{})
+			e := me.Build()
+			store.SetEvent(e)
+			events = append(events, e)
+			atropos = e.ID()
+		}
+
+		n := idx.Block(epoch)
+		tx := types.NewTransaction(uint64(n), addr, big.NewInt(1), 21000, big.NewInt(1), nil)
+		store.EvmStore().SetTx(tx.Hash(), tx)
+		store.EvmStore().SetTxPosition(tx.Hash(), evmstore.TxPosition{Block: n})
+		txs = append(txs, tx)
+		l := &types.Log{
+			Address:     addr,
+			Topics:      []common.Hash{{byte(n)}},
+			BlockNumber: uint64(n),
+			TxHash:      tx.Hash(),
+		}
+		store.EvmStore().IndexLogs(l)
+		receipts := types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{l}}}
+		store.EvmStore().SetReceipts(n, receipts)
+		store.SetBlock(n, &inter.Block{
+			Atropos:     atropos,
+			InternalTxs: []common.Hash{tx.Hash()},
+		})
+		store.SetBlockIndex(atropos, n)
+	}
+	store.SetBlockEpochState(blockproc.BlockState{LastBlock: blockproc.BlockCtx{Idx: epochs}, DirtyRules: skyhigh.FakeNetRules()}, blockproc.EpochState{Epoch: epochs, Rules: skyhigh.FakeNetRules()})
+	for n := idx.Block(1); n <= epochs; n++ {
+		store.IndexAddressTxs(n, types.Transactions{txs[n-1]}, store.EvmStore().GetReceipts(n))
+	}
+
+	// the pruning is done in batches
+	done, err := store.PruneHistory(2)
+	require.NoError(err)
+	require.False(done)
+	for !done {
+		// events of a partially pruned epoch aren't available
+		for _, e := range events {
+			if e.Epoch() >= store.GetHistoryEpochStart() {
+				require.True(store.HasEvent(e.ID()), e.ID().String())
+			}
+		}
+		done, err = store.PruneHistory(2)
+		require.NoError(err)
+	}
+	require.Equal(idx.Block(epochs-1), store.GetHistoryStart())
+	require.Equal(idx.Epoch(epochs-1), store.GetHistoryEpochStart())
+
+	// history of the old blocks is deleted, blocks themselves are kept
+	store.initCache()
+	for n := idx.Block(1); n <= epochs; n++ {
+		kept := n >= epochs-1
+		require.NotNil(store.GetBlock(n), n)
+		require.Equal(kept, store.EvmStore().GetReceipts(n) != nil, n)
+		require.Equal(kept, store.EvmStore().GetTxPosition(txs[n-1].Hash()) != nil, n)
+
+		logs, err := store.EvmStore().EvmLogs().FindInBlocks(context.Background(), n, n, [][]common.Hash{{addr.Hash()}})
+		require.NoError(err)
+		if kept {
+			require.Len(logs, 1, n)
+		} else {
+			require.Empty(logs, n)
+		}
+	}
+	for _, e := range events {
+		require.Equal(e.Epoch() >= epochs-1, store.HasEvent(e.ID()), e.ID().String())
+	}
+	var addressTxs []idx.Block
+	store.EvmStore().ForEachAddressTx(addr, 0, 0, func(tx evmstore.AddressTx) bool {
+		addressTxs = append(addressTxs, tx.Block)
+		return true
+	})
+	require.Equal([]idx.Block{epochs - 1, epochs}, addressTxs)
+
+	// nothing left to prune
+	done, err = store.PruneHistory(2)
+	require.NoError(err)
+	require.True(done)
+}
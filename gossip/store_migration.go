@@ -19,9 +19,32 @@ func isEmptyDB(db kvdb.Iteratee) bool {
 	return !it.Next()
 }
 
+// migrationCheckpointPeriod is a number of blocks between the checkpoints of long migrations
+const migrationCheckpointPeriod = 10000
+
+// names of the tables, which are modified by the migrations
+const (
+	versionTableName       = "version"
+	receiptsTableName      = "receipts"
+	logsTableName          = "logs"
+	txPositionsTableName   = "txPositions"
+	txsTableName           = "txs"
+	ancientTxsTableName    = "ancientTxs"
+	blocksTableName        = "blocks"
+	eventsTableName        = "events"
+	ancientEventsTableName = "ancientEvents"
+	ancientHeadsTableName  = "ancientHeads"
+	headsTableName         = "heads"
+	lastEventsTableName    = "lastEvents"
+)
+
+func (s *Store) isEmpty() bool {
+	return isEmptyDB(s.mainDB) && isEmptyDB(s.async.mainDB)
+}
+
 func (s *Store) migrateData() error {
 	versions := migration.NewKvdbIDStore(s.table.Version)
-	if isEmptyDB(s.mainDB) && isEmptyDB(s.async.mainDB) {
+	if s.isEmpty() {
 		// short circuit if empty DB
 		versions.SetID(s.migrations().ID())
 		return nil
@@ -31,26 +54,118 @@ func (s *Store) migrateData() error {
 	return err
 }
 
+// PendingMigration is a migration, which isn't applied to the DB yet.
+type PendingMigration struct {
+	Name string
+	// Estimate is an estimated number of blocks to process, zero if it's unknown
+	Estimate uint64
+	// Interrupted is true if the migration was interrupted, and it'll be resumed from a checkpoint
+	Interrupted bool
+	// Tables are the tables, which are modified by the migration
+	Tables []string
+}
+
+// PendingMigrations returns the migrations, which aren't applied to the DB yet, in the order of execution.
+func (s *Store) PendingMigrations() ([]PendingMigration, error) {
+	if s.isEmpty() {
+		return nil, nil
+	}
+	versions := migration.NewKvdbIDStore(s.table.Version)
+	pending, err := s.migrations().Pending(versions)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]PendingMigration, len(pending))
+	for i, m := range pending {
+		estimate, _ := m.Estimate()
+		res[i] = PendingMigration{
+			Name:        m.Name(),
+			Estimate:    estimate,
+			Interrupted: m.Checkpoint(versions) != nil,
+			Tables:      m.Tables(),
+		}
+	}
+	return res, nil
+}
+
+// Migrate applies the pending migrations.
+// If backup is true, the tables, which are modified by the pending migrations, are copied beforehand,
+// so the DB may be restored with RestoreMigrationBackup.
+func (s *Store) Migrate(backup bool) error {
+	if backup {
+		pending, err := s.PendingMigrations()
+		if err != nil {
+			return err
+		}
+		var tables []string
+		for _, m := range pending {
+			tables = append(tables, m.Tables...)
+		}
+		if len(pending) != 0 {
+			if err := s.backupMigrationTables(tables); err != nil {
+				return err
+			}
+		}
+	}
+	return s.migrateData()
+}
+
 func (s *Store) migrations() *migration.Migration {
 	return migration.
 		Begin("skyhigh-gossip-store").
-		Next("used gas recovery", s.recoverUsedGas).
-		Next("tx hashes recovery", s.recoverTxHashes).
-		Next("DAG heads recovery", s.recoverHeadsStorage).
-		Next("DAG last events recovery", s.recoverLastEventsStorage)
+		NextStep("used gas recovery", migration.Step{
+			Exec:     s.recoverUsedGas,
+			Estimate: s.estimateBlocksSinceGenesis,
+			Tables:   []string{receiptsTableName},
+		}).
+		NextStep("tx hashes recovery", migration.Step{
+			Exec:   func(*migration.Progress) error { return s.recoverTxHashes() },
+			Tables: []string{logsTableName, txPositionsTableName},
+		}).
+		NextStep("DAG heads recovery", migration.Step{
+			Exec:   func(*migration.Progress) error { return s.recoverHeadsStorage() },
+			Tables: []string{headsTableName},
+		}).
+		NextStep("DAG last events recovery", migration.Step{
+			Exec:   func(*migration.Progress) error { return s.recoverLastEventsStorage() },
+			Tables: []string{lastEventsTableName},
+		}).
+		NextStep("ancient store migration", migration.Step{
+			Exec:   s.migrateToAncientAll,
+			Tables: []string{blocksTableName, eventsTableName, ancientEventsTableName, ancientHeadsTableName, receiptsTableName, txsTableName, ancientTxsTableName},
+		})
 }
 
-func (s *Store) recoverUsedGas() error {
+// estimateBlocksSinceGenesis returns the number of blocks since the genesis block
+func (s *Store) estimateBlocksSinceGenesis() uint64 {
+	start := s.GetGenesisBlockIndex()
+	if start == nil || s.GetLatestBlockIndex() < *start {
+		return 0
+	}
+	return uint64(s.GetLatestBlockIndex()-*start) + 1
+}
+
+func (s *Store) recoverUsedGas(p *migration.Progress) error {
 	start := s.GetGenesisBlockIndex()
 	if start == nil {
 		return fmt.Errorf("genesis block index is not set")
 	}
 
-	for n := *start; true; n++ {
+	from := *start
+	if checkpoint := p.Checkpoint(); checkpoint != nil {
+		from = idx.BytesToBlock(checkpoint)
+	}
+	for n := from; true; n++ {
+		if n != from && n%migrationCheckpointPeriod == 0 {
+			if err := p.SetCheckpoint(n.Bytes()); err != nil {
+				return err
+			}
+		}
 		b := s.GetBlock(n)
 		if b == nil {
 			break
 		}
+		p.Report(uint64(n-*start) + 1)
 
 		var (
 			rr                 = s.EvmStore().GetReceipts(n)
@@ -0,0 +1,227 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb"
+	"github.com/skyhighblockchain/push-base/kvdb/table"
+)
+
+// migrationBackupDB is a name of the DB, which keeps a copy of the tables modified by the last migrations
+const migrationBackupDB = "gossip-migration-backup"
+
+var (
+	backupTablesKey = []byte("#tables")
+	backupEpochKey  = []byte("#epoch")
+	backupBlockKey  = []byte("#block")
+
+	errNoMigrationBackup = errors.New("migration backup isn't found")
+)
+
+// openMigrationBackup opens the backup DB, which is kept open until the store is closed, because it's flushed with the store
+func (s *Store) openMigrationBackup() (kvdb.Store, error) {
+	if s.migrationBackup != nil {
+		return s.migrationBackup, nil
+	}
+	db, err := s.dbs.OpenDB(migrationBackupDB)
+	if err != nil {
+		return nil, err
+	}
+	s.migrationBackup = db
+	return db, nil
+}
+
+// migrationTables returns the tables, which may be modified by the migrations, by names
+func (s *Store) migrationTables() map[string]kvdb.Store {
+	s.loadEpochStore(s.GetEpoch())
+	es := s.getAnyEpochStore()
+	evm := s.evm.KvTables()
+	return map[string]kvdb.Store{
+		versionTableName: s.table.Version,
+		// tables of evmstore
+		receiptsTableName:    evm.Receipts,
+		logsTableName:        evm.Logs,
+		txPositionsTableName: evm.TxPositions,
+		txsTableName:         evm.Txs,
+		ancientTxsTableName:  evm.AncientTxs,
+		// tables of gossip store
+		blocksTableName:        s.table.Blocks,
+		eventsTableName:        s.table.Events,
+		ancientEventsTableName: s.table.AncientEvents,
+		ancientHeadsTableName:  s.table.AncientHeads,
+		// tables of epoch store
+		headsTableName:      es.table.Heads,
+		lastEventsTableName: es.table.LastEvents,
+	}
+}
+
+// backupMigrationTables copies the tables and the version of the DB into the backup DB, replacing the previous backup.
+// Flat files of the ancient store aren't copied, because they're append-only, they're truncated on restore instead.
+func (s *Store) backupMigrationTables(names []string) error {
+	tables := s.migrationTables()
+	unique := map[string]bool{}
+	backup := []string{}
+	for _, name := range append([]string{versionTableName}, names...) {
+		if _, ok := tables[name]; !ok {
+			return fmt.Errorf("unknown table %s", name)
+		}
+		if !unique[name] {
+			unique[name] = true
+			backup = append(backup, name)
+		}
+	}
+
+	db, err := s.openMigrationBackup()
+	if err != nil {
+		return err
+	}
+	if err := eraseTable(db); err != nil {
+		return err
+	}
+	// flush the cached heads and last events into the tables
+	if err := s.Commit(); err != nil {
+		return err
+	}
+
+	for _, name := range backup {
+		keys, err := copyTable(table.New(db, []byte(name+"/")), tables[name])
+		if err != nil {
+			return err
+		}
+		s.Log.Info("Backed up table", "table", name, "keys", keys)
+	}
+	manifest, _ := rlp.EncodeToBytes(backup)
+	if err := db.Put(backupTablesKey, manifest); err != nil {
+		return err
+	}
+	if err := db.Put(backupEpochKey, s.GetEpoch().Bytes()); err != nil {
+		return err
+	}
+	if err := db.Put(backupBlockKey, s.GetLatestBlockIndex().Bytes()); err != nil {
+		return err
+	}
+	return s.Commit()
+}
+
+// RestoreMigrationBackup restores the tables, which were copied before the last migrations.
+// The DB must not be changed by the node after the backup. The store has to be reopened after the restore.
+func (s *Store) RestoreMigrationBackup() error {
+	db, err := s.openMigrationBackup()
+	if err != nil {
+		return err
+	}
+
+	manifest, err := db.Get(backupTablesKey)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return errNoMigrationBackup
+	}
+	var names []string
+	if err := rlp.DecodeBytes(manifest, &names); err != nil {
+		return err
+	}
+	epoch, err := db.Get(backupEpochKey)
+	if err != nil {
+		return err
+	}
+	block, err := db.Get(backupBlockKey)
+	if err != nil {
+		return err
+	}
+	if idx.BytesToEpoch(epoch) != s.GetEpoch() || idx.BytesToBlock(block) != s.GetLatestBlockIndex() {
+		return fmt.Errorf("DB is changed after the backup: backup epoch %d, block %d, DB epoch %d, block %d",
+			idx.BytesToEpoch(epoch), idx.BytesToBlock(block), s.GetEpoch(), s.GetLatestBlockIndex())
+	}
+
+	tables := s.migrationTables()
+	for _, name := range names {
+		dst, ok := tables[name]
+		if !ok {
+			return fmt.Errorf("unknown table %s", name)
+		}
+		if err := eraseTable(dst); err != nil {
+			return err
+		}
+		keys, err := copyTable(dst, table.New(db, []byte(name+"/")))
+		if err != nil {
+			return err
+		}
+		s.Log.Info("Restored table", "table", name, "keys", keys)
+	}
+	if err := s.truncateAncient(); err != nil {
+		return err
+	}
+	// reload the cached heads and last events, so they don't overwrite the restored tables
+	es := s.getAnyEpochStore()
+	s.epochStore.Store(newEpochStore(es.epoch, es.db))
+	return s.Commit()
+}
+
+// truncateAncient drops the items of the ancient store, which were appended after the restored tables were backed up
+func (s *Store) truncateAncient() error {
+	if s.ancient == nil {
+		return nil
+	}
+	s.repairAncientEvents()
+
+	// blocks, which are restored in the key-value DB, were appended by the migrations
+	it := s.table.Blocks.NewIterator(nil, nil)
+	defer it.Release()
+	if !it.Next() {
+		return it.Error()
+	}
+	first := idx.BytesToBlock(it.Key())
+	if err := s.ancient.Table(ancientBlocksTable).TruncateHead(uint64(first)); err != nil {
+		return err
+	}
+	return s.evm.TruncateAncient(first)
+}
+
+func copyTable(dst, src kvdb.Store) (int, error) {
+	keys := 0
+	batch := dst.NewBatch()
+	it := src.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		keys++
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return keys, err
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return keys, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return keys, err
+	}
+	return keys, batch.Write()
+}
+
+func eraseTable(t kvdb.Store) error {
+	batch := t.NewBatch()
+	it := t.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
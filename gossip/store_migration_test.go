@@ -0,0 +1,136 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb/flushable"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/utils/migration"
+)
+
+func TestStore_Migrate(t *testing.T) {
+	require := require.New(t)
+
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), LiteStoreConfig())
+	defer store.Close()
+
+	const blocks = 3
+	store.SetGenesisBlockIndex(0)
+	for n := idx.Block(0); n < blocks; n++ {
+		store.SetBlock(n, &inter.Block{Atropos: hash.Event{byte(n + 1)}})
+		store.EvmStore().SetReceipts(n, types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, GasUsed: 21000, Logs: []*types.Log{}}})
+	}
+	store.SetBlockEpochState(blockproc.BlockState{LastBlock: blockproc.BlockCtx{Idx: blocks - 1}, DirtyRules: skyhigh.FakeNetRules()}, blockproc.EpochState{Epoch: 1, Rules: skyhigh.FakeNetRules()})
+	// pretend that the DB is created before the migrations
+	migration.NewKvdbIDStore(store.table.Version).SetID(store.migrations().IDs()[0])
+	require.NoError(store.Commit())
+
+	pending, err := store.PendingMigrations()
+	require.NoError(err)
+	require.Len(pending, 5)
+	require.Equal("used gas recovery", pending[0].Name)
+	require.Equal(uint64(blocks), pending[0].Estimate)
+	require.False(pending[0].Interrupted)
+	require.Equal([]string{receiptsTableName}, pending[0].Tables)
+
+	require.NoError(store.Migrate(true))
+	pending, err = store.PendingMigrations()
+	require.NoError(err)
+	require.Empty(pending)
+
+	// the backed up tables are restored, including the version
+	receiptsKey := append([]byte("r"), idx.Block(1).Bytes()...)
+	original, err := store.mainDB.Get(receiptsKey)
+	require.NoError(err)
+	require.NoError(store.mainDB.Put(receiptsKey, []byte("modified")))
+	require.NoError(store.RestoreMigrationBackup())
+	restored, err := store.mainDB.Get(receiptsKey)
+	require.NoError(err)
+	require.Equal(original, restored)
+	pending, err = store.PendingMigrations()
+	require.NoError(err)
+	require.Len(pending, 5)
+
+	// the backup can't be restored after the DB is changed
+	store.SetBlockEpochState(blockproc.BlockState{LastBlock: blockproc.BlockCtx{Idx: blocks}, DirtyRules: skyhigh.FakeNetRules()}, blockproc.EpochState{Epoch: 1, Rules: skyhigh.FakeNetRules()})
+	require.Error(store.RestoreMigrationBackup())
+}
+
+func TestStore_RestoreAncientMigration(t *testing.T) {
+	require := require.New(t)
+
+	cfg := LiteStoreConfig()
+	cfg.Ancient.Dir = t.TempDir()
+	cfg.Ancient.Epochs = 1
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	defer store.Close()
+
+	const epochs = 4
+	var events []*inter.EventPayload
+	store.SetGenesisBlockIndex(1)
+	for epoch := idx.Epoch(1); epoch <= epochs; epoch++ {
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(epoch)
+		me.SetCreator(1)
+		me.SetSeq(1)
+		me.SetLamport(1)
+		me.SetParents(hash.Events{})
+		me.SetTxs(types.Transactions{})
+		e := me.Build()
+		store.SetEvent(e)
+		events = append(events, e)
+
+		n := idx.Block(epoch)
+		store.EvmStore().SetReceipts(n, types.Receipts{{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(n), Logs: []*types.Log{}}})
+		store.SetBlock(n, &inter.Block{Atropos: e.ID()})
+	}
+	store.SetBlockEpochState(blockproc.BlockState{LastBlock: blockproc.BlockCtx{Idx: epochs}, DirtyRules: skyhigh.FakeNetRules()}, blockproc.EpochState{Epoch: epochs, Rules: skyhigh.FakeNetRules()})
+	// pretend that only the ancient store migration is pending
+	ids := store.migrations().IDs()
+	migration.NewKvdbIDStore(store.table.Version).SetID(ids[len(ids)-2])
+	require.NoError(store.Commit())
+
+	// data of the epochs before the cut epoch epochs-1 is moved
+	const moved = epochs - 2
+	ancientBlocks := store.ancient.Table(ancientBlocksTable)
+	ancientEvents := store.ancient.Table(ancientEventsTable)
+	require.NoError(store.Migrate(true))
+	require.Equal(uint64(moved), ancientBlocks.Items())
+	require.Equal(uint64(moved), ancientEvents.Items())
+
+	// the ancient store is rolled back along with the key-value DB
+	require.NoError(store.RestoreMigrationBackup())
+	require.Zero(ancientBlocks.Items())
+	require.Zero(ancientEvents.Items())
+	has, err := store.table.Blocks.Has(idx.Block(1).Bytes())
+	require.NoError(err)
+	require.True(has)
+
+	// the migration is applied again with no duplicates
+	require.NoError(store.Migrate(false))
+	require.Equal(uint64(moved), ancientBlocks.Items())
+	require.Equal(uint64(moved), ancientEvents.Items())
+	store.initCache()
+	for n := idx.Block(1); n <= epochs; n++ {
+		require.Equal(events[n-1].ID(), store.GetBlock(n).Atropos, n)
+		require.Equal(uint64(n), store.EvmStore().GetReceipts(n)[0].CumulativeGasUsed, n)
+	}
+	for _, e := range events {
+		require.Equal(e.ID(), store.GetEventPayload(e.ID()).ID())
+	}
+}
+
+func TestStore_MigrateWithoutBackup(t *testing.T) {
+	store := NewMemStore()
+	defer store.Close()
+
+	require.Equal(t, errNoMigrationBackup, store.RestoreMigrationBackup())
+}
@@ -0,0 +1,181 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc"
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc/evmmodule"
+	"github.com/skyhighblockchain/skyhigh/gossip/evmstore"
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// GetReindexCheckpoint returns the next block to reindex, if the reindexing of the kind was interrupted
+func (s *Store) GetReindexCheckpoint(kind string) (idx.Block, bool) {
+	buf, err := s.table.Reindex.Get([]byte(kind))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	if buf == nil {
+		return 0, false
+	}
+	return idx.BytesToBlock(buf), true
+}
+
+// SetReindexCheckpoint stores the next block to reindex
+func (s *Store) SetReindexCheckpoint(kind string, n idx.Block) {
+	if err := s.table.Reindex.Put([]byte(kind), n.Bytes()); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// DelReindexCheckpoint deletes the checkpoint of a finished reindexing
+func (s *Store) DelReindexCheckpoint(kind string) {
+	if err := s.table.Reindex.Delete([]byte(kind)); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+// ReindexTxs rebuilds the txs positions of the block
+func (s *Store) ReindexTxs(n idx.Block) error {
+	block := s.GetBlock(n)
+	if block == nil {
+		return fmt.Errorf("block %d isn't found", n)
+	}
+
+	// memorize event position of each tx, if tx was met in multiple events, then assign to first ordered event
+	positions := make(map[common.Hash]evmstore.TxPosition)
+	for _, id := range block.Events {
+		e := s.GetEventPayload(id)
+		if e == nil {
+			return fmt.Errorf("event %s of block %d isn't found", id.String(), n)
+		}
+		for i, tx := range e.Txs() {
+			if _, ok := positions[tx.Hash()]; ok {
+				continue
+			}
+			positions[tx.Hash()] = evmstore.TxPosition{
+				Event:       id,
+				EventOffset: uint32(i),
+			}
+		}
+	}
+	// memorize block position of each not skipped tx
+	for i, txid := range s.getBlockTxHashes(block) {
+		position := positions[txid]
+		position.Block = n
+		position.BlockOffset = uint32(i)
+		s.evm.SetTxPosition(txid, position)
+	}
+	return nil
+}
+
+// ReindexLogs rebuilds the logs index of the block.
+// If receipts of the block are missing, the block is re-executed to restore them.
+func (s *Store) ReindexLogs(n idx.Block) error {
+	block := s.GetBlock(n)
+	if block == nil {
+		return fmt.Errorf("block %d isn't found", n)
+	}
+	for _, id := range block.Events {
+		if !s.HasEvent(id) {
+			return fmt.Errorf("event %s of block %d isn't found", id.String(), n)
+		}
+	}
+	txs := s.getBlockTxHashes(block)
+	if len(txs) == 0 {
+		return nil
+	}
+
+	receipts := s.evm.GetReceipts(n)
+	if receipts == nil {
+		var err error
+		receipts, err = s.reexecuteBlock(n, block)
+		if err != nil {
+			return fmt.Errorf("receipts of block %d aren't found and can't be restored: %v", n, err)
+		}
+		s.evm.SetReceipts(n, receipts)
+	}
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("receipts of block %d don't match the txs: %d receipts, %d txs", n, len(receipts), len(txs))
+	}
+
+	var logIndex uint
+	for i, r := range receipts {
+		for _, l := range r.Logs {
+			l.BlockNumber = uint64(n)
+			l.BlockHash = common.Hash(block.Atropos)
+			l.TxHash = txs[i]
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			logIndex++
+		}
+		s.evm.IndexLogs(r.Logs...)
+	}
+	return nil
+}
+
+// reexecuteBlock executes txs of the block on top of the previous block's state, and returns the receipts.
+// The state of the previous block has to be present.
+func (s *Store) reexecuteBlock(n idx.Block, block *inter.Block) (types.Receipts, error) {
+	if n == 0 {
+		return nil, errors.New("genesis block can't be re-executed")
+	}
+	prev := s.GetBlock(n - 1)
+	if prev == nil {
+		return nil, fmt.Errorf("block %d isn't found", n-1)
+	}
+	statedb, err := s.evm.StateDB(prev.Root)
+	if err != nil {
+		return nil, fmt.Errorf("state of block %d isn't available: %v", n-1, err)
+	}
+	es := s.GetHistoryEpochState(block.Atropos.Epoch())
+	if es == nil {
+		return nil, fmt.Errorf("state of epoch %d isn't available", block.Atropos.Epoch())
+	}
+
+	getTxs := func(ids []common.Hash) (types.Transactions, error) {
+		txs := make(types.Transactions, 0, len(ids))
+		for _, id := range ids {
+			tx := s.evm.GetTx(id)
+			if tx == nil {
+				return nil, fmt.Errorf("tx %s isn't found", id.String())
+			}
+			txs = append(txs, tx)
+		}
+		return txs, nil
+	}
+	internalTxs, err := getTxs(block.InternalTxs)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := getTxs(block.Txs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range block.Events {
+		txs = append(txs, s.GetEventPayload(id).Txs()...)
+	}
+
+	blockCtx := blockproc.BlockCtx{
+		Idx:     n,
+		Time:    block.Time,
+		Atropos: block.Atropos,
+	}
+	evmProcessor := evmmodule.New().Start(blockCtx, statedb, &EvmStateReader{store: s}, func(*types.Log) {}, es.Rules)
+	evmProcessor.Execute(internalTxs, true)
+	evmProcessor.Execute(txs, false)
+	evmBlock, skippedTxs, receipts := evmProcessor.Finalize()
+
+	if evmBlock.Root != common.Hash(block.Root) {
+		return nil, fmt.Errorf("re-executed state root mismatch: expected %s, got %s", block.Root.String(), evmBlock.Root.String())
+	}
+	if len(skippedTxs) != len(block.SkippedTxs) {
+		return nil, fmt.Errorf("re-executed skipped txs mismatch: expected %d, got %d", len(block.SkippedTxs), len(skippedTxs))
+	}
+	return receipts, nil
+}
@@ -0,0 +1,102 @@
+package gossip
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb/flushable"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+func TestStore_Reindex(t *testing.T) {
+	require := require.New(t)
+
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), LiteStoreConfig())
+	defer store.Close()
+
+	const blocks = 3
+	var (
+		txs  []*types.Transaction
+		addr = common.Address{1}
+	)
+	store.SetGenesisBlockIndex(0)
+	for n := idx.Block(1); n <= blocks; n++ {
+		internalTx := types.NewTransaction(uint64(n), addr, big.NewInt(1), 21000, big.NewInt(1), nil)
+		eventTx := types.NewTransaction(uint64(n), addr, big.NewInt(2), 21000, big.NewInt(1), nil)
+		store.EvmStore().SetTx(internalTx.Hash(), internalTx)
+
+		me := &inter.MutableEventPayload{}
+		me.SetEpoch(1)
+		me.SetCreator(1)
+		me.SetSeq(idx.Event(n))
+		me.SetLamport(idx.Lamport(n))
+		me.SetParents(hash.Events{})
+		me.SetTxs(types.Transactions{eventTx})
+		e := me.Build()
+		store.SetEvent(e)
+
+		store.EvmStore().SetReceipts(n, types.Receipts{
+			{Status: types.ReceiptStatusSuccessful},
+			{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{
+				Address: addr,
+				Topics:  []common.Hash{{byte(n)}},
+			}}},
+		})
+		store.SetBlock(n, &inter.Block{
+			Atropos:     e.ID(),
+			Events:      hash.Events{e.ID()},
+			InternalTxs: []common.Hash{internalTx.Hash()},
+		})
+		txs = append(txs, internalTx, eventTx)
+	}
+
+	// checkpoints
+	_, ok := store.GetReindexCheckpoint("txs")
+	require.False(ok)
+	store.SetReindexCheckpoint("txs", 2)
+	checkpoint, ok := store.GetReindexCheckpoint("txs")
+	require.True(ok)
+	require.Equal(idx.Block(2), checkpoint)
+	store.DelReindexCheckpoint("txs")
+	_, ok = store.GetReindexCheckpoint("txs")
+	require.False(ok)
+
+	for n := idx.Block(1); n <= blocks; n++ {
+		require.NoError(store.ReindexTxs(n))
+		require.NoError(store.ReindexLogs(n))
+	}
+
+	for i, tx := range txs {
+		n := idx.Block(i/2 + 1)
+		position := store.EvmStore().GetTxPosition(tx.Hash())
+		require.NotNil(position, i)
+		require.Equal(n, position.Block, i)
+		require.Equal(uint32(i%2), position.BlockOffset, i)
+		if i%2 == 1 {
+			require.Equal(store.GetBlock(n).Events[0], position.Event, i)
+		}
+	}
+
+	logs, err := store.EvmStore().EvmLogs().FindInBlocks(context.Background(), 1, blocks, [][]common.Hash{{addr.Hash()}})
+	require.NoError(err)
+	require.Len(logs, blocks)
+	for i, l := range logs {
+		n := idx.Block(i + 1)
+		require.Equal(uint64(n), l.BlockNumber)
+		require.Equal(common.Hash(store.GetBlock(n).Atropos), l.BlockHash)
+		require.Equal(txs[2*i+1].Hash(), l.TxHash)
+		require.Equal(uint(0), l.Index)
+	}
+
+	// missing block
+	require.Error(store.ReindexTxs(blocks + 1))
+	require.Error(store.ReindexLogs(blocks + 1))
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/skyhighblockchain/push-base/kvdb/flushable"
 
 	"github.com/skyhighblockchain/skyhigh/gossip"
+	"github.com/skyhighblockchain/skyhigh/gossip/blockproc/evmmodule"
 	"github.com/skyhighblockchain/skyhigh/skyhigh"
 	"github.com/skyhighblockchain/skyhigh/skyhigh/genesisstore"
 	"github.com/skyhighblockchain/skyhigh/utils/adapters/vecmt2dagidx"
@@ -82,6 +83,9 @@ func rawApplyGenesis(gdb *gossip.Store, cdb *abft.Store, g skyhigh.Genesis, cfg
 
 func rawMakeEngine(gdb *gossip.Store, cdb *abft.Store, g skyhigh.Genesis, cfg Configs, applyGenesis bool) (*abft.Push, *vecmt.Index, gossip.BlockProc, error) {
 	blockProc := gossip.DefaultBlockProc(g)
+	if cfg.Skyhigh.EVMWorkers > 1 {
+		blockProc.EVMModule = evmmodule.NewParallel(cfg.Skyhigh.EVMWorkers)
+	}
 
 	if applyGenesis {
 		_, err := gdb.ApplyGenesis(blockProc, g)
@@ -7,24 +7,11 @@ import (
 	"github.com/skyhighblockchain/push-base/hash"
 	"github.com/skyhighblockchain/push-base/inter/dag"
 	"github.com/skyhighblockchain/push-base/kvdb"
-	"github.com/skyhighblockchain/push-base/kvdb/leveldb"
-	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
-	"github.com/skyhighblockchain/push-base/utils/cachescale"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 
 	"github.com/skyhighblockchain/skyhigh/gossip"
 )
 
-func DBProducer(chaindataDir string, scale cachescale.Func) kvdb.IterableDBProducer {
-	if chaindataDir == "inmemory" || chaindataDir == "" {
-		return memorydb.NewProducer("")
-	}
-
-	return leveldb.NewProducer(chaindataDir, func(name string) int {
-		return dbCacheSize(name, scale.I)
-	})
-}
-
 func CheckDBList(names []string) error {
 	if len(names) == 0 {
 		return nil
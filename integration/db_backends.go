@@ -0,0 +1,158 @@
+package integration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/skyhighblockchain/push-base/kvdb"
+	"github.com/skyhighblockchain/push-base/kvdb/leveldb"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/skyhighblockchain/push-base/utils/cachescale"
+
+	"github.com/skyhighblockchain/skyhigh/utils/pebbledb"
+)
+
+// Key-value backends of the databases
+const (
+	LevelDBBackend = "leveldb"
+	PebbleBackend  = "pebble"
+	// MemoryBackend keeps all the databases in memory, it's used by tests
+	MemoryBackend = "memory"
+)
+
+// DBsConfig selects the key-value backends of the databases.
+// The choice affects only new databases, as existing databases are opened with the backend they were created with.
+type DBsConfig struct {
+	// Backend of the databases
+	Backend string
+	// Backends overrides Backend for the databases by name.
+	// A name ending with "*" matches databases by prefix, e.g. "gossip-*" matches the epoch databases.
+	Backends map[string]string `toml:",omitempty"`
+}
+
+// DefaultDBsConfig returns the default backends config.
+func DefaultDBsConfig() DBsConfig {
+	return DBsConfig{
+		Backend: LevelDBBackend,
+	}
+}
+
+// Validate checks the backends names.
+func (c DBsConfig) Validate() error {
+	switch c.Backend {
+	case LevelDBBackend, PebbleBackend, MemoryBackend:
+	default:
+		return fmt.Errorf("unknown DB backend %q", c.Backend)
+	}
+	for name, backend := range c.Backends {
+		// databases can't be partially in memory
+		if backend != LevelDBBackend && backend != PebbleBackend {
+			return fmt.Errorf("unknown DB backend %q of %s DB", backend, name)
+		}
+	}
+	return nil
+}
+
+// BackendOf returns the configured backend of the database.
+func (c DBsConfig) BackendOf(name string) string {
+	if backend, ok := c.Backends[name]; ok {
+		return backend
+	}
+	// the longest matching prefix wins
+	backend, matched := c.Backend, -1
+	for pattern, b := range c.Backends {
+		prefix := strings.TrimSuffix(pattern, "*")
+		if prefix != pattern && strings.HasPrefix(name, prefix) && len(prefix) > matched {
+			backend, matched = b, len(prefix)
+		}
+	}
+	return backend
+}
+
+// DetectBackend returns the backend of the existing database, or empty string if there's no database in the directory.
+func DetectBackend(dir string) string {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	isLevelDB := false
+	for _, f := range files {
+		// only Pebble writes the options file
+		if strings.HasPrefix(f.Name(), "OPTIONS-") {
+			return PebbleBackend
+		}
+		if f.Name() == "CURRENT" {
+			isLevelDB = true
+		}
+	}
+	if isLevelDB {
+		return LevelDBBackend
+	}
+	return ""
+}
+
+// backendsProducer opens each database with its own backend
+type backendsProducer struct {
+	datadir   string
+	cfg       DBsConfig
+	producers map[string]kvdb.IterableDBProducer
+}
+
+func newBackendsProducer(datadir string, scale cachescale.Func, cfg DBsConfig) *backendsProducer {
+	getCache := func(name string) int {
+		return dbCacheSize(name, scale.I)
+	}
+	return &backendsProducer{
+		datadir: datadir,
+		cfg:     cfg,
+		producers: map[string]kvdb.IterableDBProducer{
+			LevelDBBackend: leveldb.NewProducer(datadir, getCache),
+			PebbleBackend:  pebbledb.NewProducer(datadir, getCache),
+		},
+	}
+}
+
+// Names of existing databases. Directories without a database aren't listed.
+func (p *backendsProducer) Names() []string {
+	var names []string
+
+	files, err := ioutil.ReadDir(p.datadir)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		if DetectBackend(filepath.Join(p.datadir, f.Name())) == "" {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	return names
+}
+
+// OpenDB or create db with name.
+func (p *backendsProducer) OpenDB(name string) (kvdb.DropableStore, error) {
+	backend := p.cfg.BackendOf(name)
+	if existing := DetectBackend(filepath.Join(p.datadir, name)); existing != "" {
+		if existing != backend {
+			log.Warn("DB backend differs from the configured one, use 'db convert' command to convert it", "name", name, "backend", existing, "configured", backend)
+		}
+		backend = existing
+	}
+	return p.producers[backend].OpenDB(name)
+}
+
+// DBProducer makes a producer of the databases in the directory, with the configured backends.
+func DBProducer(chaindataDir string, scale cachescale.Func, cfg DBsConfig) kvdb.IterableDBProducer {
+	if chaindataDir == "inmemory" || chaindataDir == "" || cfg.Backend == MemoryBackend {
+		return memorydb.NewProducer("")
+	}
+
+	return newBackendsProducer(chaindataDir, scale, cfg)
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skyhighblockchain/push-base/utils/cachescale"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBsConfig_BackendOf(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DBsConfig{
+		Backend: LevelDBBackend,
+		Backends: map[string]string{
+			"gossip":   PebbleBackend,
+			"gossip-*": PebbleBackend,
+			"push-1*":  LevelDBBackend,
+			"push-*":   PebbleBackend,
+		},
+	}
+	require.NoError(cfg.Validate())
+	require.Equal(PebbleBackend, cfg.BackendOf("gossip"))
+	require.Equal(PebbleBackend, cfg.BackendOf("gossip-5"))
+	require.Equal(LevelDBBackend, cfg.BackendOf("push"))
+	require.Equal(PebbleBackend, cfg.BackendOf("push-5"))
+	require.Equal(LevelDBBackend, cfg.BackendOf("push-15"))
+	require.Equal(LevelDBBackend, cfg.BackendOf("genesis"))
+
+	cfg.Backends["genesis"] = MemoryBackend
+	require.Error(cfg.Validate())
+	require.Error(DBsConfig{Backend: "rocksdb"}.Validate())
+}
+
+func TestConvertDBs(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	cfg := DBsConfig{
+		Backend:  LevelDBBackend,
+		Backends: map[string]string{"b": PebbleBackend},
+	}
+	producer := DBProducer(dir, cachescale.Identity, cfg)
+	for _, name := range []string{"a", "b"} {
+		db, err := producer.OpenDB(name)
+		require.NoError(err)
+		for i := byte(0); i < 100; i++ {
+			require.NoError(db.Put([]byte{i}, []byte(name)))
+		}
+		require.NoError(db.Close())
+	}
+	// not a DB
+	require.NoError(os.MkdirAll(filepath.Join(dir, "ancient"), 0700))
+
+	require.Equal(LevelDBBackend, DetectBackend(filepath.Join(dir, "a")))
+	require.Equal(PebbleBackend, DetectBackend(filepath.Join(dir, "b")))
+	require.Equal("", DetectBackend(filepath.Join(dir, "ancient")))
+	require.Equal([]string{"a", "b"}, producer.Names())
+
+	require.NoError(ConvertDBs(dir, cachescale.Identity, PebbleBackend))
+	require.Equal(PebbleBackend, DetectBackend(filepath.Join(dir, "a")))
+	require.Equal(PebbleBackend, DetectBackend(filepath.Join(dir, "b")))
+
+	// existing DBs are opened with their backend, regardless of the config
+	producer = DBProducer(dir, cachescale.Identity, DefaultDBsConfig())
+	require.Equal([]string{"a", "b"}, producer.Names())
+	for _, name := range []string{"a", "b"} {
+		db, err := producer.OpenDB(name)
+		require.NoError(err)
+		digest, err := CalcDBDigest(db)
+		require.NoError(err)
+		require.Equal(uint64(100), digest.Keys)
+		val, err := db.Get([]byte{5})
+		require.NoError(err)
+		require.Equal([]byte(name), val)
+		require.NoError(db.Close())
+	}
+
+	// temporary directories are removed
+	_, err := os.Stat(dir + ".convert")
+	require.True(os.IsNotExist(err))
+	_, err = os.Stat(dir + ".backup")
+	require.True(os.IsNotExist(err))
+}
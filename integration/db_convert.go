@@ -0,0 +1,182 @@
+package integration
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/skyhighblockchain/push-base/kvdb"
+	"github.com/skyhighblockchain/push-base/utils/cachescale"
+)
+
+// DBDigest is a number of key-value pairs in a database and a hash of all of them
+type DBDigest struct {
+	Keys uint64
+	Hash common.Hash
+}
+
+type digester struct {
+	keys uint64
+	h    crypto.KeccakState
+}
+
+func newDigester() *digester {
+	return &digester{
+		h: crypto.NewKeccakState(),
+	}
+}
+
+func (d *digester) Add(key, value []byte) {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(key)))
+	_, _ = d.h.Write(size[:])
+	_, _ = d.h.Write(key)
+	binary.BigEndian.PutUint32(size[:], uint32(len(value)))
+	_, _ = d.h.Write(size[:])
+	_, _ = d.h.Write(value)
+	d.keys++
+}
+
+func (d *digester) Digest() DBDigest {
+	var res DBDigest
+	res.Keys = d.keys
+	_, _ = d.h.Read(res.Hash[:])
+	return res
+}
+
+// CalcDBDigest calculates digest of all the database key-value pairs.
+func CalcDBDigest(db kvdb.Store) (DBDigest, error) {
+	d := newDigester()
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		d.Add(it.Key(), it.Value())
+	}
+	return d.Digest(), it.Error()
+}
+
+// CopyDB copies all the key-value pairs of the database, and returns the digest of the copied pairs.
+func CopyDB(dst, src kvdb.Store) (DBDigest, error) {
+	d := newDigester()
+	batch := dst.NewBatch()
+	it := src.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		d.Add(it.Key(), it.Value())
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return DBDigest{}, err
+		}
+		if batch.ValueSize() >= kvdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return DBDigest{}, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return DBDigest{}, err
+	}
+	return d.Digest(), batch.Write()
+}
+
+// convertDB copies the database into the destination producer, and verifies the copy.
+func convertDB(name string, src, dst kvdb.DBProducer) (DBDigest, error) {
+	srcDB, err := src.OpenDB(name)
+	if err != nil {
+		return DBDigest{}, err
+	}
+	defer srcDB.Close()
+	dstDB, err := dst.OpenDB(name)
+	if err != nil {
+		return DBDigest{}, err
+	}
+	copied, err := CopyDB(dstDB, srcDB)
+	if err != nil {
+		_ = dstDB.Close()
+		return DBDigest{}, err
+	}
+	if err := dstDB.Close(); err != nil {
+		return DBDigest{}, err
+	}
+
+	// verify the copy after reopening, i.e. what was actually written on disk
+	dstDB, err = dst.OpenDB(name)
+	if err != nil {
+		return DBDigest{}, err
+	}
+	defer dstDB.Close()
+	written, err := CalcDBDigest(dstDB)
+	if err != nil {
+		return DBDigest{}, err
+	}
+	if written != copied {
+		return DBDigest{}, fmt.Errorf("%s DB copy mismatch: %d keys with hash %s copied, %d keys with hash %s written",
+			name, copied.Keys, copied.Hash.String(), written.Keys, written.Hash.String())
+	}
+	return written, nil
+}
+
+// ConvertDBs converts the databases in the directory into the backend.
+// Databases are converted into a temporary directory, and replace the original ones only after all of them are verified.
+func ConvertDBs(chaindataDir string, scale cachescale.Func, backend string) error {
+	if backend != LevelDBBackend && backend != PebbleBackend {
+		return fmt.Errorf("unknown DB backend %q", backend)
+	}
+	tmpDir := filepath.Clean(chaindataDir) + ".convert"
+	backupDir := filepath.Clean(chaindataDir) + ".backup"
+	if _, err := os.Stat(backupDir); err == nil {
+		return fmt.Errorf("%s is left by an interrupted conversion, restore the databases from it manually", backupDir)
+	}
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tmpDir, 0700); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// databases which need the conversion are of another backend
+	srcBackend := LevelDBBackend
+	if backend == LevelDBBackend {
+		srcBackend = PebbleBackend
+	}
+	src := DBProducer(chaindataDir, scale, DBsConfig{Backend: srcBackend})
+	dst := DBProducer(tmpDir, scale, DBsConfig{Backend: backend})
+
+	var converted []string
+	for _, name := range src.Names() {
+		if DetectBackend(filepath.Join(chaindataDir, name)) == backend {
+			log.Info("DB is already converted", "name", name, "backend", backend)
+			continue
+		}
+		start := time.Now()
+		digest, err := convertDB(name, src, dst)
+		if err != nil {
+			return err
+		}
+		log.Info("Converted DB", "name", name, "keys", digest.Keys, "hash", digest.Hash.String(), "elapsed", common.PrettyDuration(time.Since(start)))
+		converted = append(converted, name)
+	}
+	if len(converted) == 0 {
+		return nil
+	}
+
+	// replace the original databases
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return err
+	}
+	for _, name := range converted {
+		if err := os.Rename(filepath.Join(chaindataDir, name), filepath.Join(backupDir, name)); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(tmpDir, name), filepath.Join(chaindataDir, name)); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(backupDir)
+}
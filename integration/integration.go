@@ -31,7 +31,7 @@ func NewIntegration(ctx *adapters.ServiceContext, genesis InputGenesis, stack *n
 		VectorClock:  vecmt.DefaultConfig(cachescale.Identity),
 	}
 
-	engine, dagIndex, gdb, _, _, blockProc := MakeEngine(DBProducer(ctx.Config.DataDir, cachescale.Identity), genesis, cfg)
+	engine, dagIndex, gdb, _, _, blockProc := MakeEngine(DBProducer(ctx.Config.DataDir, cachescale.Identity, DefaultDBsConfig()), genesis, cfg)
 	_ = genesis.Close()
 
 	valKeystore := valkeystore.NewDefaultMemKeystore()
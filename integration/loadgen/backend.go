@@ -0,0 +1,51 @@
+package loadgen
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Backend is a node, which accepts the transactions and includes them into blocks
+type Backend interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	BlockNumber(ctx context.Context) (uint64, error)
+	// BlockTxs returns hashes of the block transactions, or ethereum.NotFound if the block doesn't exist
+	BlockTxs(ctx context.Context, number uint64) ([]common.Hash, error)
+}
+
+type rpcBackend struct {
+	*ethclient.Client
+	rpc *rpc.Client
+}
+
+// NewRPCBackend makes a backend, which sends the transactions over RPC with eth_sendRawTransaction.
+func NewRPCBackend(client *rpc.Client) Backend {
+	return &rpcBackend{
+		Client: ethclient.NewClient(client),
+		rpc:    client,
+	}
+}
+
+func (b *rpcBackend) BlockTxs(ctx context.Context, number uint64) ([]common.Hash, error) {
+	var block *struct {
+		Transactions []common.Hash `json:"transactions"`
+	}
+	err := b.rpc.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	return block.Transactions, nil
+}
@@ -0,0 +1,50 @@
+package loadgen
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The contracts are hand-written in EVM assembly, to not depend on a solidity compiler.
+var (
+	// tokenCode deploys a minimal ERC20-like token, which assigns 2^255 tokens to the deployer.
+	// Balances are stored at slots equal to the holders addresses. The runtime implements
+	// transfer(address,uint256) with the Transfer event, and balanceOf(address).
+	tokenCode = hexutil.MustDecode("0x" +
+		// constructor: sstore(caller, 1 << 255), return the runtime
+		"600160ff1b3355607d8060126000396000f3" +
+		// dispatch by the selector, revert on unknown
+		"60003560e01c8063a9059cbb14601f57806370a08231146070575b600080fd" +
+		// transfer: check and update balances, emit Transfer, return true
+		"5b3354602435808210601a578082033355600435805482019055600052600435337f" +
+		"ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" +
+		"60206000a3600160005260206000f3" +
+		// balanceOf: return sload(holder)
+		"5b6004355460005260206000f3")
+
+	// storageCode deploys a contract, which stores the second calldata word at the slot of the first word
+	storageCode = hexutil.MustDecode("0x600880600b6000396000f3" + "6020356000355500")
+
+	transferSelector  = hexutil.MustDecode("0xa9059cbb")
+	balanceOfSelector = hexutil.MustDecode("0x70a08231")
+)
+
+func word(b []byte) []byte {
+	return common.LeftPadBytes(b, 32)
+}
+
+func tokenTransferData(to common.Address, amount *big.Int) []byte {
+	data := append([]byte{}, transferSelector...)
+	data = append(data, word(to.Bytes())...)
+	return append(data, word(amount.Bytes())...)
+}
+
+func tokenBalanceOfData(holder common.Address) []byte {
+	return append(append([]byte{}, balanceOfSelector...), word(holder.Bytes())...)
+}
+
+func storageWriteData(key, value common.Hash) []byte {
+	return append(append([]byte{}, key.Bytes()...), value.Bytes()...)
+}
@@ -0,0 +1,455 @@
+package loadgen
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/skyhighblockchain/skyhigh/logger"
+)
+
+const (
+	// sendWorkers is a number of concurrent senders, transactions of an account are sent by the same worker
+	sendWorkers  = 16
+	sendTick     = 10 * time.Millisecond
+	pollInterval = 100 * time.Millisecond
+	reportPeriod = 8 * time.Second
+)
+
+var errNotConfirmed = errors.New("setup transactions aren't confirmed in time")
+
+// Config is a configuration of the load
+type Config struct {
+	// TPS is a target rate of the transactions submission
+	TPS float64
+	// Duration of the load, zero means until the context is canceled
+	Duration time.Duration
+	Mix      Mix
+	// GasPrice of the transactions, the suggested gas price is used if nil
+	GasPrice *big.Int
+	// ConfirmationTimeout is how long to wait for the submitted transactions after the load is stopped
+	ConfirmationTimeout time.Duration
+	// Seed of the random recipients, storage slots and transaction kinds
+	Seed int64
+}
+
+// DefaultConfig returns the default load, which is 10 transfers per second during a minute.
+func DefaultConfig() Config {
+	return Config{
+		TPS:                 10,
+		Duration:            time.Minute,
+		Mix:                 Mix{Transfer: 1},
+		ConfirmationTimeout: 30 * time.Second,
+		Seed:                1,
+	}
+}
+
+// Report is a result of the load. Setup transactions aren't counted.
+type Report struct {
+	// Duration is a time of the transactions submission
+	Duration  time.Duration
+	Submitted int
+	Failed    int
+	Confirmed int
+
+	SubmittedTPS float64
+	// ConfirmedTPS is measured from the load start till the last confirmation
+	ConfirmedTPS float64
+
+	// percentiles of the time from a transaction submission till its block is observed
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+	LatencyMax time.Duration
+}
+
+type account struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	nonce   uint64
+	token   common.Address
+	// resync is set if a transaction of the account isn't sent, so the nonce has to be read again
+	resync uint32
+}
+
+type sendTask struct {
+	acc *account
+	tx  *types.Transaction
+	// setup transactions aren't counted in the report
+	setup bool
+}
+
+// Generator submits a mix of transactions at a target rate, and measures their confirmation.
+type Generator struct {
+	backend  Backend
+	cfg      Config
+	accounts []*account
+
+	signer   types.Signer
+	gasPrice *big.Int
+	rand     *rand.Rand
+	storage  common.Address
+
+	mu            sync.Mutex
+	pending       map[common.Hash]time.Time
+	setupPending  map[common.Hash]bool
+	setupErr      error
+	latencies     []time.Duration
+	lastConfirmed time.Time
+	submitted     int
+	failed        int
+
+	logger.Periodic
+}
+
+// New makes a generator of transactions, which are sent from the accounts of the keys.
+func New(backend Backend, keys []*ecdsa.PrivateKey, cfg Config) *Generator {
+	g := &Generator{
+		backend:      backend,
+		cfg:          cfg,
+		rand:         rand.New(rand.NewSource(cfg.Seed)),
+		pending:      make(map[common.Hash]time.Time),
+		setupPending: make(map[common.Hash]bool),
+		Periodic:     logger.Periodic{Instance: logger.MakeInstance()},
+	}
+	for _, key := range keys {
+		g.accounts = append(g.accounts, &account{
+			key:     key,
+			address: crypto.PubkeyToAddress(key.PublicKey),
+		})
+	}
+	return g
+}
+
+// Run deploys the contracts needed by the mix, then submits transactions till the load duration is passed
+// or the context is canceled, and waits for the confirmations.
+func (g *Generator) Run(ctx context.Context) (*Report, error) {
+	if len(g.accounts) == 0 {
+		return nil, errors.New("no accounts to send transactions from")
+	}
+	if g.cfg.TPS <= 0 {
+		return nil, errors.New("TPS must be positive")
+	}
+	if err := g.prepare(ctx); err != nil {
+		return nil, err
+	}
+	head, err := g.backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := make([]chan sendTask, sendWorkers)
+	wg := sync.WaitGroup{}
+	for i := range workers {
+		workers[i] = make(chan sendTask, 64)
+		wg.Add(1)
+		go func(tasks chan sendTask) {
+			defer wg.Done()
+			for task := range tasks {
+				// queued transactions are sent even if the load is interrupted
+				g.send(context.Background(), task)
+			}
+		}(workers[i])
+	}
+	dispatch := func(i int, tx *types.Transaction, setup bool) {
+		if setup {
+			g.mu.Lock()
+			g.setupPending[tx.Hash()] = true
+			g.mu.Unlock()
+		}
+		workers[i%len(workers)] <- sendTask{g.accounts[i], tx, setup}
+	}
+
+	trackerCtx, stopTracker := context.WithCancel(context.Background())
+	trackerDone := make(chan struct{})
+	go func() {
+		defer close(trackerDone)
+		g.track(trackerCtx, head)
+	}()
+	stop := func() {
+		for _, tasks := range workers {
+			close(tasks)
+		}
+		wg.Wait()
+		stopTracker()
+		<-trackerDone
+	}
+
+	// deploy the contracts
+	for i, acc := range g.accounts {
+		if g.cfg.Mix.Has(TokenTransfer) {
+			acc.token = crypto.CreateAddress(acc.address, acc.nonce)
+			dispatch(i, g.deployTx(acc, tokenCode), true)
+		}
+	}
+	if g.cfg.Mix.Has(StorageWrite) {
+		acc := g.accounts[0]
+		g.storage = crypto.CreateAddress(acc.address, acc.nonce)
+		dispatch(0, g.deployTx(acc, storageCode), true)
+	}
+	if err := g.waitSetup(ctx); err != nil {
+		stop()
+		return nil, err
+	}
+
+	start := time.Now()
+	g.Log.Info("Generating load", "tps", g.cfg.TPS, "mix", g.cfg.Mix, "accounts", len(g.accounts), "duration", g.cfg.Duration)
+	ticker := time.NewTicker(sendTick)
+	sent := 0
+	reported := start
+loop:
+	for next := 0; ; {
+		select {
+		case <-ctx.Done():
+			break loop
+		case now := <-ticker.C:
+			elapsed := now.Sub(start)
+			if g.cfg.Duration != 0 && elapsed >= g.cfg.Duration {
+				break loop
+			}
+			for due := int(elapsed.Seconds() * g.cfg.TPS); sent < due; sent++ {
+				i := next % len(g.accounts)
+				next++
+				g.resyncNonce(ctx, g.accounts[i])
+				dispatch(i, g.randomTx(g.accounts[i]), false)
+			}
+			if now.Sub(reported) >= reportPeriod {
+				reported = now
+				r := g.report(start, now)
+				g.Log.Info("Load progress", "submitted", r.Submitted, "confirmed", r.Confirmed, "failed", r.Failed,
+					"tps", r.SubmittedTPS, "confirmed_tps", r.ConfirmedTPS, "latency", r.LatencyP50)
+			}
+		}
+	}
+	ticker.Stop()
+	end := time.Now()
+	g.waitConfirmations()
+	stop()
+
+	r := g.report(start, end)
+	return &r, nil
+}
+
+func (g *Generator) prepare(ctx context.Context) error {
+	chainID, err := g.backend.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	g.signer = types.NewEIP2930Signer(chainID)
+	g.gasPrice = g.cfg.GasPrice
+	if g.gasPrice == nil {
+		g.gasPrice, err = g.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	for _, acc := range g.accounts {
+		acc.nonce, err = g.backend.PendingNonceAt(ctx, acc.address)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Generator) resyncNonce(ctx context.Context, acc *account) {
+	if atomic.LoadUint32(&acc.resync) == 0 {
+		return
+	}
+	nonce, err := g.backend.PendingNonceAt(ctx, acc.address)
+	if err != nil {
+		return
+	}
+	atomic.StoreUint32(&acc.resync, 0)
+	acc.nonce = nonce
+}
+
+func (g *Generator) signTx(acc *account, to *common.Address, gas uint64, value *big.Int, data []byte) *types.Transaction {
+	var inner types.TxData
+	if to == nil {
+		inner = &types.LegacyTx{Nonce: acc.nonce, GasPrice: g.gasPrice, Gas: gas, Value: value, Data: data}
+	} else {
+		inner = &types.LegacyTx{Nonce: acc.nonce, GasPrice: g.gasPrice, Gas: gas, To: to, Value: value, Data: data}
+	}
+	tx, err := types.SignNewTx(acc.key, g.signer, inner)
+	if err != nil {
+		// the key and the signer are valid
+		panic(err)
+	}
+	acc.nonce++
+	return tx
+}
+
+func (g *Generator) deployTx(acc *account, code []byte) *types.Transaction {
+	return g.signTx(acc, nil, deployGas, new(big.Int), code)
+}
+
+func (g *Generator) randomTx(acc *account) *types.Transaction {
+	kind := g.cfg.Mix.pick(g.rand)
+	switch kind {
+	case TokenTransfer:
+		return g.signTx(acc, &acc.token, txKindGas[kind], new(big.Int), tokenTransferData(g.randomAddress(), big.NewInt(1)))
+	case StorageWrite:
+		return g.signTx(acc, &g.storage, txKindGas[kind], new(big.Int), storageWriteData(g.randomHash(), g.randomHash()))
+	default:
+		to := g.randomAddress()
+		return g.signTx(acc, &to, txKindGas[kind], big.NewInt(1), nil)
+	}
+}
+
+func (g *Generator) randomHash() common.Hash {
+	var h common.Hash
+	g.rand.Read(h[:])
+	return h
+}
+
+func (g *Generator) randomAddress() common.Address {
+	return common.BytesToAddress(g.randomHash().Bytes())
+}
+
+func (g *Generator) send(ctx context.Context, task sendTask) {
+	if !task.setup {
+		g.mu.Lock()
+		g.pending[task.tx.Hash()] = time.Now()
+		g.submitted++
+		g.mu.Unlock()
+	}
+
+	err := g.backend.SendTransaction(ctx, task.tx)
+	if err == nil {
+		return
+	}
+	g.Periodic.Warn(time.Second, "Failed to send transaction", "from", task.acc.address, "nonce", task.tx.Nonce(), "err", err)
+	atomic.StoreUint32(&task.acc.resync, 1)
+	g.mu.Lock()
+	if task.setup {
+		g.setupErr = err
+	} else {
+		delete(g.pending, task.tx.Hash())
+		g.failed++
+	}
+	g.mu.Unlock()
+}
+
+// track observes the new blocks and records the confirmation latency of the submitted transactions
+func (g *Generator) track(ctx context.Context, head uint64) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		latest, err := g.backend.BlockNumber(ctx)
+		if err != nil {
+			g.Periodic.Warn(time.Second, "Failed to get latest block", "err", err)
+			continue
+		}
+		for ; head < latest; head++ {
+			txs, err := g.backend.BlockTxs(ctx, head+1)
+			if err != nil {
+				g.Periodic.Warn(time.Second, "Failed to get block", "block", head+1, "err", err)
+				break
+			}
+			g.confirm(txs)
+		}
+	}
+}
+
+func (g *Generator) confirm(txs []common.Hash) {
+	now := time.Now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, h := range txs {
+		if g.setupPending[h] {
+			delete(g.setupPending, h)
+			continue
+		}
+		submitted, ok := g.pending[h]
+		if !ok {
+			continue
+		}
+		delete(g.pending, h)
+		g.latencies = append(g.latencies, now.Sub(submitted))
+		g.lastConfirmed = now
+	}
+}
+
+// waitSetup waits until the setup transactions are confirmed
+func (g *Generator) waitSetup(ctx context.Context) error {
+	deadline := time.Now().Add(g.cfg.ConfirmationTimeout)
+	for {
+		g.mu.Lock()
+		left, err := len(g.setupPending), g.setupErr
+		g.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		if left == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errNotConfirmed
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (g *Generator) waitConfirmations() {
+	deadline := time.Now().Add(g.cfg.ConfirmationTimeout)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		left := len(g.pending)
+		g.mu.Unlock()
+		if left == 0 {
+			return
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func (g *Generator) report(start, end time.Time) Report {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	r := Report{
+		Submitted: g.submitted,
+		Failed:    g.failed,
+		Confirmed: len(g.latencies),
+	}
+	r.Duration = end.Sub(start)
+	if r.Duration > 0 {
+		r.SubmittedTPS = float64(r.Submitted) / r.Duration.Seconds()
+	}
+	if g.lastConfirmed.After(start) {
+		r.ConfirmedTPS = float64(r.Confirmed) / g.lastConfirmed.Sub(start).Seconds()
+	}
+	if len(g.latencies) != 0 {
+		sorted := append([]time.Duration{}, g.latencies...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i] < sorted[j]
+		})
+		percentile := func(p int) time.Duration {
+			return sorted[(len(sorted)-1)*p/100]
+		}
+		r.LatencyP50 = percentile(50)
+		r.LatencyP90 = percentile(90)
+		r.LatencyP99 = percentile(99)
+		r.LatencyMax = sorted[len(sorted)-1]
+	}
+	return r
+}
@@ -0,0 +1,125 @@
+package loadgen
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackend produces a block every time the latest block is requested
+type testBackend struct {
+	*backends.SimulatedBackend
+}
+
+func (b testBackend) ChainID(context.Context) (*big.Int, error) {
+	return b.Blockchain().Config().ChainID, nil
+}
+
+func (b testBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	b.Commit()
+	header, err := b.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+func (b testBackend) BlockTxs(ctx context.Context, number uint64) ([]common.Hash, error) {
+	block, err := b.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	hashes := make([]common.Hash, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes, nil
+}
+
+func TestGenerator(t *testing.T) {
+	require := require.New(t)
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	alloc := core.GenesisAlloc{}
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		alloc[crypto.PubkeyToAddress(keys[i].PublicKey)] = core.GenesisAccount{Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)}
+	}
+	backend := testBackend{backends.NewSimulatedBackend(alloc, 100000000)}
+	defer backend.Close()
+
+	cfg := DefaultConfig()
+	cfg.TPS = 100
+	cfg.Duration = time.Second
+	cfg.Mix = Mix{Transfer: 1, TokenTransfer: 1, StorageWrite: 1}
+	r, err := New(backend, keys, cfg).Run(context.Background())
+	require.NoError(err)
+	require.InDelta(100, r.Submitted, 20)
+	require.Equal(r.Submitted, r.Confirmed)
+	require.Zero(r.Failed)
+	require.NotZero(r.LatencyP50)
+	require.True(r.LatencyP50 <= r.LatencyP99 && r.LatencyP99 <= r.LatencyMax)
+
+	// all the transactions are successful
+	ctx := context.Background()
+	deployer := crypto.PubkeyToAddress(keys[0].PublicKey)
+	token := crypto.CreateAddress(deployer, 0)
+	head, err := backend.BlockNumber(ctx)
+	require.NoError(err)
+	txs := 0
+	tokenTransfers := uint64(0)
+	for n := uint64(1); n <= head; n++ {
+		block, err := backend.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+		require.NoError(err)
+		for _, tx := range block.Transactions() {
+			receipt, err := backend.TransactionReceipt(ctx, tx.Hash())
+			require.NoError(err)
+			require.Equal(types.ReceiptStatusSuccessful, receipt.Status, "tx %s", tx.Hash().String())
+			if tx.To() != nil && *tx.To() == token {
+				tokenTransfers++
+			}
+			txs++
+		}
+	}
+	require.NotZero(tokenTransfers)
+	// 3 tokens and a storage contract are deployed
+	require.Equal(r.Submitted+len(keys)+1, txs)
+
+	// token balances are updated
+	res, err := backend.CallContract(ctx, ethereum.CallMsg{To: &token, Data: tokenBalanceOfData(deployer)}, nil)
+	require.NoError(err)
+	supply := new(big.Int).Lsh(big.NewInt(1), 255)
+	require.Equal(new(big.Int).Sub(supply, new(big.Int).SetUint64(tokenTransfers)), new(big.Int).SetBytes(res))
+}
+
+func TestGenerator_Interrupted(t *testing.T) {
+	require := require.New(t)
+
+	key, _ := crypto.GenerateKey()
+	alloc := core.GenesisAlloc{crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(1e18)}}
+	backend := testBackend{backends.NewSimulatedBackend(alloc, 100000000)}
+	defer backend.Close()
+
+	cfg := DefaultConfig()
+	cfg.TPS = 50
+	cfg.Duration = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	r, err := New(backend, []*ecdsa.PrivateKey{key}, cfg).Run(ctx)
+	require.NoError(err)
+	require.NotZero(r.Submitted)
+	require.Equal(r.Submitted, r.Confirmed)
+}
@@ -0,0 +1,120 @@
+package loadgen
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// TxKind is a kind of the generated transactions
+type TxKind int
+
+const (
+	// Transfer is a native token transfer to a random address
+	Transfer TxKind = iota
+	// TokenTransfer is an ERC20 transfer to a random address, every sender deploys its own token
+	TokenTransfer
+	// StorageWrite is a contract call, which writes a random storage slot
+	StorageWrite
+
+	txKinds
+)
+
+var txKindNames = [txKinds]string{
+	Transfer:      "transfer",
+	TokenTransfer: "erc20",
+	StorageWrite:  "storage",
+}
+
+// gas limits of the transactions, with a margin
+var txKindGas = [txKinds]uint64{
+	Transfer:      21000,
+	TokenTransfer: 100000,
+	StorageWrite:  60000,
+}
+
+const deployGas = 200000
+
+func (k TxKind) String() string {
+	if k < 0 || k >= txKinds {
+		return "unknown"
+	}
+	return txKindNames[k]
+}
+
+// Mix is a relative weight of every transaction kind
+type Mix [txKinds]uint
+
+// ParseMix parses comma separated weights, e.g. "transfer=5,erc20=3,storage=2".
+// Unspecified kinds have zero weight.
+func ParseMix(s string) (Mix, error) {
+	var mix Mix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return mix, fmt.Errorf("invalid mix entry %q, use kind=weight format", part)
+		}
+		kind, ok := parseTxKind(strings.TrimSpace(kv[0]))
+		if !ok {
+			return mix, fmt.Errorf("unknown transaction kind %q, expected one of %s", kv[0], strings.Join(txKindNames[:], ", "))
+		}
+		weight, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 32)
+		if err != nil {
+			return mix, fmt.Errorf("invalid weight of %s: %v", kind, err)
+		}
+		mix[kind] = uint(weight)
+	}
+	if mix.total() == 0 {
+		return mix, fmt.Errorf("transaction mix is empty")
+	}
+	return mix, nil
+}
+
+func parseTxKind(s string) (TxKind, bool) {
+	for kind, name := range txKindNames {
+		if s == name {
+			return TxKind(kind), true
+		}
+	}
+	return 0, false
+}
+
+func (m Mix) String() string {
+	parts := make([]string, 0, len(m))
+	for kind, weight := range m {
+		if weight != 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", TxKind(kind), weight))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Has returns true if the kind has non-zero weight
+func (m Mix) Has(kind TxKind) bool {
+	return m[kind] != 0
+}
+
+func (m Mix) total() uint {
+	total := uint(0)
+	for _, weight := range m {
+		total += weight
+	}
+	return total
+}
+
+// pick chooses a random kind according to the weights
+func (m Mix) pick(r *rand.Rand) TxKind {
+	n := uint(r.Int63n(int64(m.total())))
+	for kind, weight := range m {
+		if n < weight {
+			return TxKind(kind)
+		}
+		n -= weight
+	}
+	return Transfer
+}
@@ -0,0 +1,39 @@
+package loadgen
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	require := require.New(t)
+
+	mix, err := ParseMix("transfer=5, erc20=3,storage=2")
+	require.NoError(err)
+	require.Equal(Mix{Transfer: 5, TokenTransfer: 3, StorageWrite: 2}, mix)
+	require.Equal("transfer=5,erc20=3,storage=2", mix.String())
+
+	mix, err = ParseMix("storage=1")
+	require.NoError(err)
+	require.False(mix.Has(Transfer))
+	require.True(mix.Has(StorageWrite))
+
+	for _, invalid := range []string{"", "transfer=0", "transfer", "nft=1", "transfer=-1"} {
+		_, err = ParseMix(invalid)
+		require.Error(err, invalid)
+	}
+}
+
+func TestMix_Pick(t *testing.T) {
+	mix := Mix{Transfer: 3, StorageWrite: 1}
+	r := rand.New(rand.NewSource(1))
+	picked := map[TxKind]int{}
+	for i := 0; i < 4000; i++ {
+		picked[mix.pick(r)]++
+	}
+	require.Zero(t, picked[TokenTransfer])
+	require.InDelta(t, 3000, picked[Transfer], 150)
+	require.InDelta(t, 1000, picked[StorageWrite], 150)
+}
@@ -88,6 +88,13 @@ func FakeGenesisStore(num int, balance, stake *big.Int) *genesisstore.Store {
 		Root:        hash.Hash{},
 		Receipts:    []*types.ReceiptForStorage{},
 	})
+	SetPredeployedContracts(genStore)
+
+	return genStore
+}
+
+// SetPredeployedContracts sets the code of the system contracts, which are deployed in genesis
+func SetPredeployedContracts(genStore *genesisstore.Store) {
 	// pre deploy NetworkInitializer
 	genStore.SetEvmAccount(netinit.ContractAddress, genesis.Account{
 		Code:    netinit.GetContractBin(),
@@ -118,8 +125,6 @@ func FakeGenesisStore(num int, balance, stake *big.Int) *genesisstore.Store {
 		Balance: new(big.Int),
 		Nonce:   0,
 	})
-
-	return genStore
 }
 
 func GetFakeValidators(num int) gpos.Validators {
@@ -0,0 +1,358 @@
+package makegenesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/naoina/toml"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/inter/validatorpk"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/driver"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/driverauth"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/evmwriter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/gpos"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/netinit"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesis/sfc"
+	"github.com/skyhighblockchain/skyhigh/skyhigh/genesisstore"
+)
+
+// Spec is a declarative description of a genesis.
+// Times are in unix seconds, durations are in seconds.
+type Spec struct {
+	// BaseRules is a name of the rules to start from: "main", "test" or "fake" (default)
+	BaseRules string
+	// Rules overrides fields of the base rules, the rest of fields are kept
+	Rules skyhigh.Rules
+
+	// Time of the genesis
+	Time uint64
+	// FirstEpoch is 2 by default
+	FirstEpoch idx.Epoch
+	ExtraData  string
+	// DriverOwner is an address of the first validator by default
+	DriverOwner common.Address
+
+	Accounts    []AccountSpec
+	Validators  []ValidatorSpec
+	Delegations []DelegationSpec
+}
+
+// AccountSpec is an EVM account in genesis
+type AccountSpec struct {
+	Address common.Address
+	Balance *big.Int
+	Code    hexutil.Bytes
+	Nonce   uint64
+	Storage map[common.Hash]common.Hash
+}
+
+// ValidatorSpec is a genesis validator and its self-stake
+type ValidatorSpec struct {
+	ID      idx.ValidatorID
+	Address common.Address
+	PubKey  validatorpk.PubKey
+	Stake   *big.Int
+}
+
+// DelegationSpec is a genesis delegation, which may be locked up
+type DelegationSpec struct {
+	Address     common.Address
+	ValidatorID idx.ValidatorID
+	Stake       *big.Int
+
+	LockedStake     *big.Int
+	LockupFromEpoch idx.Epoch
+	LockupDuration  uint64
+	// LockupEndTime is Time + LockupDuration by default
+	LockupEndTime uint64
+}
+
+// These settings ensure that TOML keys use the same names as Go struct fields.
+var specTomlSettings = toml.Config{
+	NormFieldName: func(rt reflect.Type, key string) string {
+		return key
+	},
+	FieldToKey: func(rt reflect.Type, field string) string {
+		return field
+	},
+	MissingField: func(rt reflect.Type, field string) error {
+		return fmt.Errorf("field '%s' is not defined in %s", field, rt.String())
+	},
+}
+
+func baseRules(name string) (skyhigh.Rules, error) {
+	switch name {
+	case "main":
+		return skyhigh.MainNetRules(), nil
+	case "test":
+		return skyhigh.TestNetRules(), nil
+	case "", "fake":
+		return skyhigh.FakeNetRules(), nil
+	}
+	return skyhigh.Rules{}, fmt.Errorf("unknown base rules %q, expected main, test or fake", name)
+}
+
+// DecodeSpec parses a JSON or TOML genesis spec.
+func DecodeSpec(data []byte, isTOML bool) (*Spec, error) {
+	decode := func(v interface{}, strict bool) error {
+		if isTOML {
+			cfg := specTomlSettings
+			if !strict {
+				cfg.MissingField = func(rt reflect.Type, field string) error {
+					return nil
+				}
+			}
+			return cfg.Unmarshal(data, v)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if strict {
+			dec.DisallowUnknownFields()
+		}
+		return dec.Decode(v)
+	}
+
+	// the base rules have to be known before the overrides are decoded
+	var base struct {
+		BaseRules string
+	}
+	if err := decode(&base, false); err != nil {
+		return nil, err
+	}
+	rules, err := baseRules(base.BaseRules)
+	if err != nil {
+		return nil, err
+	}
+	spec := &Spec{
+		Rules: rules,
+	}
+	if err := decode(spec, true); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// LoadSpec reads a genesis spec from a .json or .toml file.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return DecodeSpec(data, false)
+	case ".toml":
+		return DecodeSpec(data, true)
+	}
+	return nil, fmt.Errorf("unknown spec format of %s, expected .json or .toml file", path)
+}
+
+func isPredeployed(addr common.Address) bool {
+	switch addr {
+	case netinit.ContractAddress, driver.ContractAddress, driverauth.ContractAddress, sfc.ContractAddress, evmwriter.ContractAddress:
+		return true
+	}
+	return false
+}
+
+func isPositive(v *big.Int) bool {
+	return v != nil && v.Sign() > 0
+}
+
+// Validate checks the spec consistency.
+func (s *Spec) Validate() error {
+	if s.Rules.NetworkID == skyhigh.MainNetworkID || s.Rules.NetworkID == skyhigh.TestNetworkID {
+		return fmt.Errorf("network ID %d is reserved", s.Rules.NetworkID)
+	}
+	if s.Rules.Economy.MinGasPrice == nil {
+		return errors.New("min gas price isn't specified")
+	}
+	if s.Time == 0 {
+		return errors.New("genesis time isn't specified")
+	}
+	if len(s.Validators) == 0 {
+		return errors.New("genesis validators shouldn't be empty")
+	}
+
+	accounts := make(map[common.Address]bool, len(s.Accounts))
+	for _, acc := range s.Accounts {
+		if accounts[acc.Address] {
+			return fmt.Errorf("duplicated account %s", acc.Address.String())
+		}
+		accounts[acc.Address] = true
+		if isPredeployed(acc.Address) {
+			return fmt.Errorf("account %s is a system contract", acc.Address.String())
+		}
+		if acc.Balance != nil && acc.Balance.Sign() < 0 {
+			return fmt.Errorf("negative balance of account %s", acc.Address.String())
+		}
+	}
+
+	validators := make(map[idx.ValidatorID]bool, len(s.Validators))
+	for _, v := range s.Validators {
+		if v.ID == 0 {
+			return errors.New("validator ID 0 is reserved")
+		}
+		if validators[v.ID] {
+			return fmt.Errorf("duplicated validator %d", v.ID)
+		}
+		validators[v.ID] = true
+		if v.Address == (common.Address{}) {
+			return fmt.Errorf("address of validator %d isn't specified", v.ID)
+		}
+		if v.PubKey.Empty() {
+			return fmt.Errorf("pubkey of validator %d isn't specified", v.ID)
+		}
+		if !isPositive(v.Stake) {
+			return fmt.Errorf("stake of validator %d isn't specified", v.ID)
+		}
+	}
+
+	type delegationID struct {
+		addr common.Address
+		to   idx.ValidatorID
+	}
+	delegations := make(map[delegationID]bool, len(s.Validators)+len(s.Delegations))
+	for _, v := range s.Validators {
+		delegations[delegationID{v.Address, v.ID}] = true
+	}
+	for _, d := range s.Delegations {
+		if !validators[d.ValidatorID] {
+			return fmt.Errorf("delegation of %s to unknown validator %d", d.Address.String(), d.ValidatorID)
+		}
+		id := delegationID{d.Address, d.ValidatorID}
+		if delegations[id] {
+			return fmt.Errorf("duplicated delegation of %s to validator %d", d.Address.String(), d.ValidatorID)
+		}
+		delegations[id] = true
+		if !isPositive(d.Stake) {
+			return fmt.Errorf("stake of %s to validator %d isn't specified", d.Address.String(), d.ValidatorID)
+		}
+		if isPositive(d.LockedStake) {
+			if d.LockedStake.Cmp(d.Stake) > 0 {
+				return fmt.Errorf("locked stake of %s to validator %d exceeds the stake", d.Address.String(), d.ValidatorID)
+			}
+			if d.LockupDuration == 0 {
+				return fmt.Errorf("lockup duration of %s to validator %d isn't specified", d.Address.String(), d.ValidatorID)
+			}
+		}
+	}
+	return nil
+}
+
+func secondsToTimestamp(s uint64) inter.Timestamp {
+	return inter.Timestamp(s) * inter.Timestamp(time.Second)
+}
+
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(v)
+}
+
+// BuildGenesisStore makes a genesis from the spec.
+func BuildGenesisStore(spec *Spec) (*genesisstore.Store, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	genesisTime := secondsToTimestamp(spec.Time)
+	firstEpoch := spec.FirstEpoch
+	if firstEpoch == 0 {
+		firstEpoch = 2
+	}
+	owner := spec.DriverOwner
+	if owner == (common.Address{}) {
+		owner = spec.Validators[0].Address
+	}
+
+	genStore := genesisstore.NewMemStore()
+	genStore.SetRules(spec.Rules.Copy())
+
+	totalSupply := new(big.Int)
+	for _, acc := range spec.Accounts {
+		code := []byte(acc.Code)
+		if code == nil {
+			code = []byte{}
+		}
+		genStore.SetEvmAccount(acc.Address, genesis.Account{
+			Code:    code,
+			Balance: bigOrZero(acc.Balance),
+			Nonce:   acc.Nonce,
+		})
+		for key, value := range acc.Storage {
+			genStore.SetEvmState(acc.Address, key, value)
+		}
+		totalSupply.Add(totalSupply, bigOrZero(acc.Balance))
+	}
+
+	validators := make(gpos.Validators, 0, len(spec.Validators))
+	for _, v := range spec.Validators {
+		validators = append(validators, gpos.Validator{
+			ID:           v.ID,
+			Address:      v.Address,
+			PubKey:       v.PubKey,
+			CreationTime: genesisTime,
+		})
+		genStore.SetDelegation(v.Address, v.ID, genesis.Delegation{
+			Stake:              new(big.Int).Set(v.Stake),
+			Rewards:            new(big.Int),
+			LockedStake:        new(big.Int),
+			EarlyUnlockPenalty: new(big.Int),
+		})
+	}
+	for _, d := range spec.Delegations {
+		delegation := genesis.Delegation{
+			Stake:              new(big.Int).Set(d.Stake),
+			Rewards:            new(big.Int),
+			LockedStake:        bigOrZero(d.LockedStake),
+			EarlyUnlockPenalty: new(big.Int),
+		}
+		if delegation.LockedStake.Sign() > 0 {
+			endTime := d.LockupEndTime
+			if endTime == 0 {
+				endTime = spec.Time + d.LockupDuration
+			}
+			delegation.LockupFromEpoch = d.LockupFromEpoch
+			delegation.LockupDuration = secondsToTimestamp(d.LockupDuration)
+			delegation.LockupEndTime = secondsToTimestamp(endTime)
+		}
+		genStore.SetDelegation(d.Address, d.ValidatorID, delegation)
+	}
+
+	genStore.SetMetadata(genesisstore.Metadata{
+		Validators:    validators,
+		FirstEpoch:    firstEpoch,
+		Time:          genesisTime,
+		PrevEpochTime: genesisTime - inter.Timestamp(time.Hour),
+		ExtraData:     []byte(spec.ExtraData),
+		DriverOwner:   owner,
+		TotalSupply:   totalSupply,
+	})
+	genStore.SetBlock(0, genesis.Block{
+		Time:        genesisTime - inter.Timestamp(time.Minute),
+		Atropos:     hash.Event{},
+		Txs:         types.Transactions{},
+		InternalTxs: types.Transactions{},
+		Root:        hash.Hash{},
+		Receipts:    []*types.ReceiptForStorage{},
+	})
+	SetPredeployedContracts(genStore)
+
+	return genStore, nil
+}
@@ -0,0 +1,157 @@
+package makegenesis
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/skyhigh"
+)
+
+const testSpecJSON = `{
+	"BaseRules": "main",
+	"Rules": {"Name": "private", "NetworkID": 4000, "Blocks": {"MaxBlockGas": 1000}},
+	"Time": 1608600000,
+	"Accounts": [
+		{"Address": "0x1000000000000000000000000000000000000001", "Balance": 1000000000000000000000,
+		 "Code": "0x6001", "Storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000002"}},
+		{"Address": "0x1000000000000000000000000000000000000002", "Balance": 5}
+	],
+	"Validators": [
+		{"ID": 1, "Address": "0x1000000000000000000000000000000000000001", "PubKey": "%s", "Stake": 3000}
+	],
+	"Delegations": [
+		{"Address": "0x1000000000000000000000000000000000000002", "ValidatorID": 1, "Stake": 100, "LockedStake": 50, "LockupDuration": 86400}
+	]
+}`
+
+const testSpecTOML = `
+BaseRules = "fake"
+Time = 1608600000
+ExtraData = "private"
+
+[Rules]
+NetworkID = 4001
+
+[Rules.Economy]
+MinGasPrice = 7
+
+[[Accounts]]
+Address = "0x1000000000000000000000000000000000000001"
+Balance = 1000
+
+[[Validators]]
+ID = 2
+Address = "0x1000000000000000000000000000000000000001"
+PubKey = "%s"
+Stake = 3000
+`
+
+var testValidatorKey = FakeKey(1)
+
+func testPubKey() string {
+	return "0xc0" + common.Bytes2Hex(crypto.FromECDSAPub(&testValidatorKey.PublicKey))
+}
+
+func TestBuildGenesisStore_JSON(t *testing.T) {
+	require := require.New(t)
+
+	spec, err := DecodeSpec([]byte(fmt.Sprintf(testSpecJSON, testPubKey())), false)
+	require.NoError(err)
+
+	genStore, err := BuildGenesisStore(spec)
+	require.NoError(err)
+
+	// rules are overridden on top of the base rules
+	rules := genStore.GetRules()
+	require.Equal("private", rules.Name)
+	require.Equal(uint64(4000), rules.NetworkID)
+	require.Equal(uint64(1000), rules.Blocks.MaxBlockGas)
+	require.Equal(skyhigh.MainNetRules().Blocks.MaxEmptyBlockSkipPeriod, rules.Blocks.MaxEmptyBlockSkipPeriod)
+	require.Equal(skyhigh.MainNetRules().Economy.MinGasPrice, rules.Economy.MinGasPrice)
+
+	addr1 := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	addr2 := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	acc := genStore.GetEvmAccount(addr1)
+	require.Equal([]byte{0x60, 0x01}, acc.Code)
+	require.Equal(common.BigToHash(big.NewInt(2)), genStore.GetEvmState(addr1, common.BigToHash(big.NewInt(1))))
+
+	metadata := genStore.GetMetadata()
+	require.Equal(idx.Epoch(2), metadata.FirstEpoch)
+	require.Equal(addr1, metadata.DriverOwner)
+	require.Equal(inter.Timestamp(1608600000*time.Second), metadata.Time)
+	require.Equal(new(big.Int).Add(acc.Balance, big.NewInt(5)), metadata.TotalSupply)
+	require.Len(metadata.Validators, 1)
+	require.Equal(testPubKey(), metadata.Validators[0].PubKey.String())
+
+	require.Equal(big.NewInt(3000), genStore.GetDelegation(addr1, 1).Stake)
+	delegation := genStore.GetDelegation(addr2, 1)
+	require.Equal(big.NewInt(100), delegation.Stake)
+	require.Equal(big.NewInt(50), delegation.LockedStake)
+	require.Equal(inter.Timestamp(24*time.Hour), delegation.LockupDuration)
+	require.Equal(inter.Timestamp((1608600000+86400)*time.Second), delegation.LockupEndTime)
+
+	// the hash is deterministic
+	again, err := BuildGenesisStore(spec)
+	require.NoError(err)
+	require.Equal(genStore.Hash(), again.Hash())
+}
+
+func TestBuildGenesisStore_TOML(t *testing.T) {
+	require := require.New(t)
+
+	spec, err := DecodeSpec([]byte(fmt.Sprintf(testSpecTOML, testPubKey())), true)
+	require.NoError(err)
+
+	genStore, err := BuildGenesisStore(spec)
+	require.NoError(err)
+
+	rules := genStore.GetRules()
+	require.Equal("fake", rules.Name)
+	require.Equal(uint64(4001), rules.NetworkID)
+	require.Equal(big.NewInt(7), rules.Economy.MinGasPrice)
+	require.Equal(skyhigh.FakeNetRules().Epochs, rules.Epochs)
+	require.Equal([]byte("private"), genStore.GetMetadata().ExtraData)
+	require.Equal(big.NewInt(3000), genStore.GetDelegation(common.HexToAddress("0x1000000000000000000000000000000000000001"), 2).Stake)
+}
+
+func TestSpec_Validate(t *testing.T) {
+	valid := func() *Spec {
+		spec, err := DecodeSpec([]byte(fmt.Sprintf(testSpecJSON, testPubKey())), false)
+		require.NoError(t, err)
+		return spec
+	}
+	require.NoError(t, valid().Validate())
+
+	for name, corrupt := range map[string]func(*Spec){
+		"reserved network ID": func(s *Spec) { s.Rules.NetworkID = skyhigh.MainNetworkID },
+		"no time":             func(s *Spec) { s.Time = 0 },
+		"no validators":       func(s *Spec) { s.Validators = nil },
+		"duplicated validator": func(s *Spec) {
+			s.Validators = append(s.Validators, s.Validators[0])
+		},
+		"no stake":           func(s *Spec) { s.Validators[0].Stake = nil },
+		"unknown validator":  func(s *Spec) { s.Delegations[0].ValidatorID = 5 },
+		"excess locked":      func(s *Spec) { s.Delegations[0].LockedStake = big.NewInt(101) },
+		"no lockup duration": func(s *Spec) { s.Delegations[0].LockupDuration = 0 },
+		"system contract": func(s *Spec) {
+			s.Accounts[1].Address = common.HexToAddress("0xd100a01e00000000000000000000000000000000")
+		},
+	} {
+		spec := valid()
+		corrupt(spec)
+		require.Error(t, spec.Validate(), name)
+	}
+
+	_, err := DecodeSpec([]byte(`{"Unknown": 1}`), false)
+	require.Error(t, err)
+	_, err = DecodeSpec([]byte(`{"BaseRules": "other"}`), false)
+	require.Error(t, err)
+}
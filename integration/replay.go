@@ -0,0 +1,262 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/skyhighblockchain/push-base/abft"
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/dag"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/skyhighblockchain/push-base/push"
+
+	"github.com/skyhighblockchain/skyhigh/gossip"
+	"github.com/skyhighblockchain/skyhigh/inter"
+	"github.com/skyhighblockchain/skyhigh/utils/adapters/vecmt2dagidx"
+	"github.com/skyhighblockchain/skyhigh/vecmt"
+)
+
+// ReplayValidator is an epoch validator.
+type ReplayValidator struct {
+	ID     idx.ValidatorID `json:"id"`
+	Weight pos.Weight      `json:"weight"`
+}
+
+// ReplayFrame contains roots of a frame, re-derived during a replay.
+type ReplayFrame struct {
+	Frame idx.Frame     `json:"frame"`
+	Roots []common.Hash `json:"roots"`
+}
+
+// ReplayDecision is an Atropos decision, re-derived during a replay, compared against the stored block.
+type ReplayDecision struct {
+	Frame    idx.Frame         `json:"frame"`
+	Atropos  common.Hash       `json:"atropos"`
+	Cheaters []idx.ValidatorID `json:"cheaters"`
+	// Events are confirmed non-empty events, ordered by Lamport time
+	Events  []common.Hash `json:"events"`
+	Sealing bool          `json:"sealing"`
+	// Block is an index of the stored block with the same Atropos, nil if there's no such block (i.e. block was skipped)
+	Block *idx.Block `json:"block"`
+	Match bool       `json:"match"`
+	Diff  string     `json:"diff,omitempty"`
+}
+
+// ReplayEventDiff is a mismatch between a stored event and its re-derived consensus fields.
+type ReplayEventDiff struct {
+	Event common.Hash `json:"event"`
+	Diff  string      `json:"diff"`
+}
+
+// EpochReplay is a trace of a replayed epoch.
+type EpochReplay struct {
+	Epoch      idx.Epoch         `json:"epoch"`
+	Validators []ReplayValidator `json:"validators"`
+	Events     int               `json:"events"`
+	Frames     []ReplayFrame     `json:"frames"`
+	Decisions  []ReplayDecision  `json:"decisions"`
+	EventDiffs []ReplayEventDiff `json:"eventDiffs"`
+	// MissingBlocks are stored blocks of the epoch which weren't re-derived
+	MissingBlocks []idx.Block `json:"missingBlocks"`
+	// Error is an error which interrupted the replay
+	Error string `json:"error,omitempty"`
+	Match bool   `json:"match"`
+}
+
+// compareBlockEvents checks that stored block events are equal to the re-derived ones.
+// Stored block may contain fewer events, because first events are spilled if they exceed MaxBlockGas.
+func compareBlockEvents(stored hash.Events, derived hash.Events) string {
+	if len(stored) > len(derived) {
+		return fmt.Sprintf("stored block has %d events, re-derived %d", len(stored), len(derived))
+	}
+	offset := len(derived) - len(stored)
+	for i, id := range stored {
+		if derived[offset+i] != id {
+			return fmt.Sprintf("event #%d mismatch: stored %s, re-derived %s", i, id.String(), derived[offset+i].String())
+		}
+	}
+	return ""
+}
+
+// ReplayEpoch re-runs the consensus on stored events of the epoch in a fresh in-memory engine,
+// and compares the re-derived frames, Atroposes and blocks against the stored ones.
+func ReplayEpoch(gdb *gossip.Store, epoch idx.Epoch, cfg Configs) (res *EpochReplay, err error) {
+	es := gdb.GetHistoryEpochState(epoch)
+	if es == nil {
+		return nil, fmt.Errorf("state of epoch %d isn't available", epoch)
+	}
+
+	res = &EpochReplay{
+		Epoch:         epoch,
+		Validators:    make([]ReplayValidator, 0, es.Validators.Len()),
+		Frames:        make([]ReplayFrame, 0, 100),
+		Decisions:     make([]ReplayDecision, 0, 100),
+		EventDiffs:    make([]ReplayEventDiff, 0),
+		MissingBlocks: make([]idx.Block, 0),
+	}
+	for _, id := range es.Validators.SortedIDs() {
+		res.Validators = append(res.Validators, ReplayValidator{id, es.Validators.Get(id)})
+	}
+
+	// engine errors are critical, they interrupt the replay
+	crit := func(err error) {
+		panic(err)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			res.Error = err.Error()
+			res.Match = false
+		}
+	}()
+
+	cdb := abft.NewMemStore()
+	err = cdb.ApplyGenesis(&abft.Genesis{
+		Epoch:      epoch,
+		Validators: es.Validators,
+	})
+	if err != nil {
+		return nil, err
+	}
+	dagIndexer := vecmt.NewIndex(crit, cfg.VectorClock)
+	dagIndexer.Reset(es.Validators, memorydb.New(), func(id hash.Event) dag.Event {
+		return gdb.GetEvent(id)
+	})
+	engine := abft.NewPush(cdb, &GossipStoreAdapter{gdb}, vecmt2dagidx.Wrap(dagIndexer), crit, cfg.Push)
+
+	// stored blocks of the epoch
+	storedBlocks := make(map[idx.Block]bool)
+	var sealingAtropos *hash.Event
+	if first, last, ok := gdb.GetEpochBlocks(epoch); ok {
+		for n := first; n <= last; n++ {
+			storedBlocks[n] = true
+		}
+		if epoch < gdb.GetEpoch() {
+			// the last block of a sealed epoch is the sealing block
+			atropos := gdb.GetBlock(last).Atropos
+			sealingAtropos = &atropos
+		}
+	}
+
+	var (
+		decidedFrame idx.Frame
+		sealed       bool
+	)
+	ordererCallbacks := engine.OrdererCallbacks()
+	err = engine.BootstrapWithOrderer(push.ConsensusCallbacks{
+		BeginBlock: func(cBlock *push.Block) push.BlockCallbacks {
+			decision := ReplayDecision{
+				Frame:    decidedFrame,
+				Atropos:  common.Hash(cBlock.Atropos),
+				Cheaters: cBlock.Cheaters,
+				Match:    true,
+			}
+			confirmedEvents := make(hash.OrderedEvents, 0, 3*es.Validators.Len())
+			return push.BlockCallbacks{
+				ApplyEvent: func(_e dag.Event) {
+					e := _e.(inter.EventI)
+					if !e.NoTxs() {
+						confirmedEvents = append(confirmedEvents, e.ID())
+					}
+				},
+				EndBlock: func() (newValidators *pos.Validators) {
+					sort.Sort(confirmedEvents)
+					decision.Events = make([]common.Hash, len(confirmedEvents))
+					for i, id := range confirmedEvents {
+						decision.Events[i] = common.Hash(id)
+					}
+					if n := gdb.GetBlockIndex(cBlock.Atropos); n != nil {
+						decision.Block = n
+						delete(storedBlocks, *n)
+						decision.Diff = compareBlockEvents(gdb.GetBlock(*n).Events, hash.Events(confirmedEvents))
+						decision.Match = decision.Diff == ""
+					}
+					if sealingAtropos != nil && *sealingAtropos == cBlock.Atropos {
+						decision.Sealing = true
+						sealed = true
+						newValidators = es.Validators
+					}
+					res.Decisions = append(res.Decisions, decision)
+					return newValidators
+				},
+			}
+		},
+	}, abft.OrdererCallbacks{
+		ApplyAtropos: func(frame idx.Frame, atropos hash.Event) *pos.Validators {
+			decidedFrame = frame
+			return ordererCallbacks.ApplyAtropos(frame, atropos)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make(map[idx.Frame][]common.Hash)
+	maxFrame := idx.Frame(0)
+	gdb.ForEachEpochEvent(epoch, func(e *inter.EventPayload) bool {
+		if sealed {
+			return false
+		}
+		res.Events++
+
+		err := dagIndexer.Add(e)
+		if err != nil {
+			res.Error = err.Error()
+			return false
+		}
+		if medianTime := dagIndexer.MedianTime(e.ID(), es.EpochStart); medianTime != e.MedianTime() {
+			res.EventDiffs = append(res.EventDiffs, ReplayEventDiff{
+				Event: common.Hash(e.ID()),
+				Diff:  fmt.Sprintf("median time mismatch: stored %d, re-derived %d", e.MedianTime(), medianTime),
+			})
+		}
+
+		err = engine.Process(e)
+		if errors.Is(err, abft.ErrWrongFrame) {
+			res.EventDiffs = append(res.EventDiffs, ReplayEventDiff{
+				Event: common.Hash(e.ID()),
+				Diff:  fmt.Sprintf("frame mismatch: stored %d", e.Frame()),
+			})
+		}
+		if err != nil {
+			res.Error = err.Error()
+			return false
+		}
+		dagIndexer.Flush()
+
+		selfParentFrame := idx.Frame(0)
+		if e.SelfParent() != nil {
+			selfParentFrame = gdb.GetEvent(*e.SelfParent()).Frame()
+		}
+		for f := selfParentFrame + 1; f <= e.Frame(); f++ {
+			roots[f] = append(roots[f], common.Hash(e.ID()))
+		}
+		if e.Frame() > maxFrame {
+			maxFrame = e.Frame()
+		}
+		return true
+	})
+
+	for f := idx.Frame(1); f <= maxFrame; f++ {
+		res.Frames = append(res.Frames, ReplayFrame{f, roots[f]})
+	}
+	for n := range storedBlocks {
+		res.MissingBlocks = append(res.MissingBlocks, n)
+	}
+	sort.Slice(res.MissingBlocks, func(i, j int) bool {
+		return res.MissingBlocks[i] < res.MissingBlocks[j]
+	})
+
+	res.Match = res.Error == "" && len(res.EventDiffs) == 0 && len(res.MissingBlocks) == 0
+	for _, d := range res.Decisions {
+		res.Match = res.Match && d.Match
+	}
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package dagexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/stretchr/testify/require"
+)
+
+// testGraph returns a subgraph of 2 validators:
+// a1 and b1 are roots confirmed by block 7, a1 is the Atropos,
+// a2 isn't confirmed and has a self-parent a1 and an other-parent b1,
+// b2 has a self-parent b1 and an other-parent outside of the subgraph.
+func testGraph() (g *Graph, a1, b1, a2, b2, outside hash.Event) {
+	ids := hash.FakeEvents(5)
+	a1, b1, a2, b2, outside = ids[0], ids[1], ids[2], ids[3], ids[4]
+	block := idx.Block(7)
+	g = &Graph{
+		Epoch: 3,
+		Nodes: []Node{
+			{ID: a1, Creator: 1, Seq: 1, Lamport: 1, Frame: 1, Root: true, Atropos: true, Block: &block},
+			{ID: b1, Creator: 2, Seq: 1, Lamport: 1, Frame: 1, Root: true, Block: &block},
+			{ID: a2, Creator: 1, Seq: 2, Lamport: 2, Frame: 1, Parents: hash.Events{a1, b1}},
+			{ID: b2, Creator: 2, Seq: 2, Lamport: 2, Frame: 1, Parents: hash.Events{b1, outside}},
+		},
+	}
+	return
+}
+
+func TestWriteDOT(t *testing.T) {
+	require := require.New(t)
+	g, a1, b1, a2, b2, outside := testGraph()
+
+	buf := &bytes.Buffer{}
+	require.NoError(WriteDOT(buf, g))
+	out := buf.String()
+
+	require.Contains(out, "digraph \"epoch 3\" {\n")
+	require.Contains(out, "\tsubgraph \"cluster_1\" {\n\t\tlabel=\"validator 1\";\n")
+	require.Contains(out, "\tsubgraph \"cluster_2\" {\n\t\tlabel=\"validator 2\";\n")
+
+	// nodes
+	require.Contains(out, fmt.Sprintf("\t\t\"%s\" [label=\"1-1\\nframe 1\\nlamport 1\\nblock 7\", shape=box, style=filled, fillcolor=lightcoral];\n", a1))
+	require.Contains(out, fmt.Sprintf("\t\t\"%s\" [label=\"2-1\\nframe 1\\nlamport 1\\nblock 7\", shape=box];\n", b1))
+	require.Contains(out, fmt.Sprintf("\t\t\"%s\" [label=\"1-2\\nframe 1\\nlamport 2\", color=gray];\n", a2))
+	require.Contains(out, fmt.Sprintf("\t\t\"%s\" [label=\"2-2\\nframe 1\\nlamport 2\", color=gray];\n", b2))
+
+	// edges
+	require.Contains(out, fmt.Sprintf("\t\"%s\" -> \"%s\";\n", a2, a1))
+	require.Contains(out, fmt.Sprintf("\t\"%s\" -> \"%s\" [style=dashed];\n", a2, b1))
+	require.Contains(out, fmt.Sprintf("\t\"%s\" -> \"%s\";\n", b2, b1))
+	require.NotContains(out, outside.String())
+	require.Equal(3, bytes.Count(buf.Bytes(), []byte("->")))
+}
+
+func TestWriteJSON(t *testing.T) {
+	require := require.New(t)
+	g, a1, b1, a2, b2, outside := testGraph()
+
+	buf := &bytes.Buffer{}
+	require.NoError(WriteJSON(buf, g))
+	var res JSONGraph
+	require.NoError(json.Unmarshal(buf.Bytes(), &res))
+
+	require.True(res.Graph.Directed)
+	require.Equal("epoch 3", res.Graph.Label)
+	require.Equal(idx.Epoch(3), res.Graph.Metadata.Epoch)
+
+	// nodes
+	require.Len(res.Graph.Nodes, 4)
+	block := idx.Block(7)
+	require.Equal(JSONNode{
+		Label:    a1.String(),
+		Metadata: JSONNodeMetadata{Creator: 1, Seq: 1, Lamport: 1, Frame: 1, Root: true, Atropos: true, Block: &block},
+	}, res.Graph.Nodes[a1.Hex()])
+	require.Equal(JSONNode{
+		Label:    b1.String(),
+		Metadata: JSONNodeMetadata{Creator: 2, Seq: 1, Lamport: 1, Frame: 1, Root: true, Block: &block},
+	}, res.Graph.Nodes[b1.Hex()])
+	require.Equal(JSONNode{
+		Label:    a2.String(),
+		Metadata: JSONNodeMetadata{Creator: 1, Seq: 2, Lamport: 2, Frame: 1},
+	}, res.Graph.Nodes[a2.Hex()])
+	require.Nil(res.Graph.Nodes[b2.Hex()].Metadata.Block)
+	require.NotContains(res.Graph.Nodes, outside.Hex())
+
+	// edges
+	require.Equal([]JSONEdge{
+		{Source: a2.Hex(), Target: a1.Hex(), Relation: "self-parent"},
+		{Source: a2.Hex(), Target: b1.Hex(), Relation: "parent"},
+		{Source: b2.Hex(), Target: b1.Hex(), Relation: "self-parent"},
+	}, res.Graph.Edges)
+}
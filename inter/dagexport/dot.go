@@ -0,0 +1,71 @@
+package dagexport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/skyhighblockchain/push-base/inter/idx"
+)
+
+// WriteDOT writes the subgraph in Graphviz DOT format.
+// Events of each creator are grouped into a cluster, parents are above children.
+// Roots are boxes, Atroposes are filled, edges to other-parents are dashed.
+// Edges to parents outside of the subgraph are omitted.
+func WriteDOT(w io.Writer, g *Graph) error {
+	ids := g.ids()
+	byCreator := make(map[idx.ValidatorID][]*Node)
+	creators := make([]idx.ValidatorID, 0)
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		if _, ok := byCreator[n.Creator]; !ok {
+			creators = append(creators, n.Creator)
+		}
+		byCreator[n.Creator] = append(byCreator[n.Creator], n)
+	}
+	sort.Slice(creators, func(i, j int) bool {
+		return creators[i] < creators[j]
+	})
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph \"epoch %d\" {\n", g.Epoch)
+	fmt.Fprintf(bw, "\trankdir=BT;\n")
+	fmt.Fprintf(bw, "\tnode [shape=ellipse, fontsize=10];\n")
+	for _, creator := range creators {
+		fmt.Fprintf(bw, "\tsubgraph \"cluster_%d\" {\n", creator)
+		fmt.Fprintf(bw, "\t\tlabel=\"validator %d\";\n", creator)
+		for _, n := range byCreator[creator] {
+			label := fmt.Sprintf("%d-%d\\nframe %d\\nlamport %d", n.Creator, n.Seq, n.Frame, n.Lamport)
+			if n.Block != nil {
+				label += fmt.Sprintf("\\nblock %d", *n.Block)
+			}
+			attrs := ""
+			if n.Root {
+				attrs += ", shape=box"
+			}
+			if n.Atropos {
+				attrs += ", style=filled, fillcolor=lightcoral"
+			} else if n.Block == nil {
+				attrs += ", color=gray"
+			}
+			fmt.Fprintf(bw, "\t\t\"%s\" [label=\"%s\"%s];\n", n.ID.String(), label, attrs)
+		}
+		fmt.Fprintf(bw, "\t}\n")
+	}
+	for _, n := range g.Nodes {
+		for i, p := range n.Parents {
+			if !ids[p] {
+				continue
+			}
+			attrs := ""
+			if i != 0 || n.Seq <= 1 {
+				// self-parent is always the first parent, if exists
+				attrs = " [style=dashed]"
+			}
+			fmt.Fprintf(bw, "\t\"%s\" -> \"%s\"%s;\n", n.ID.String(), p.String(), attrs)
+		}
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
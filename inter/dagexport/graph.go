@@ -0,0 +1,56 @@
+package dagexport
+
+import (
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// Node is a DAG event with its consensus flags.
+type Node struct {
+	ID           hash.Event
+	Creator      idx.ValidatorID
+	Seq          idx.Event
+	Lamport      idx.Lamport
+	Frame        idx.Frame
+	CreationTime inter.Timestamp
+	MedianTime   inter.Timestamp
+	Parents      hash.Events
+	// Root is true if event is the first event of the creator in its frame
+	Root bool
+	// Atropos is true if event is an Atropos of a block
+	Atropos bool
+	// Block is an index of the block which confirmed the event, nil if event isn't confirmed yet
+	Block *idx.Block
+}
+
+// Graph is a subgraph of an epoch DAG.
+// Nodes are ordered by Lamport time, parents of the nodes may be outside of the subgraph.
+type Graph struct {
+	Epoch idx.Epoch
+	Nodes []Node
+}
+
+// NewNode makes a node from the event header. Consensus flags are left blank.
+func NewNode(e inter.EventI) Node {
+	return Node{
+		ID:           e.ID(),
+		Creator:      e.Creator(),
+		Seq:          e.Seq(),
+		Lamport:      e.Lamport(),
+		Frame:        e.Frame(),
+		CreationTime: e.CreationTime(),
+		MedianTime:   e.MedianTime(),
+		Parents:      e.Parents(),
+	}
+}
+
+// ids returns a set of the subgraph nodes.
+func (g *Graph) ids() map[hash.Event]bool {
+	set := make(map[hash.Event]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		set[n.ID] = true
+	}
+	return set
+}
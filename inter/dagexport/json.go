@@ -0,0 +1,103 @@
+package dagexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/skyhighblockchain/push-base/inter/idx"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+// JSONGraph is a subgraph in JSON Graph Format (https://jsongraphformat.info).
+type JSONGraph struct {
+	Graph struct {
+		Directed bool                `json:"directed"`
+		Label    string              `json:"label"`
+		Metadata JSONGraphMetadata   `json:"metadata"`
+		Nodes    map[string]JSONNode `json:"nodes"`
+		Edges    []JSONEdge          `json:"edges"`
+	} `json:"graph"`
+}
+
+// JSONGraphMetadata is a metadata of JSONGraph.
+type JSONGraphMetadata struct {
+	Epoch idx.Epoch `json:"epoch"`
+}
+
+// JSONNode is a node of JSONGraph.
+type JSONNode struct {
+	Label    string           `json:"label"`
+	Metadata JSONNodeMetadata `json:"metadata"`
+}
+
+// JSONNodeMetadata is a metadata of JSONNode.
+type JSONNodeMetadata struct {
+	Creator      idx.ValidatorID `json:"creator"`
+	Seq          idx.Event       `json:"seq"`
+	Lamport      idx.Lamport     `json:"lamport"`
+	Frame        idx.Frame       `json:"frame"`
+	CreationTime inter.Timestamp `json:"creationTime"`
+	MedianTime   inter.Timestamp `json:"medianTime"`
+	Root         bool            `json:"root"`
+	Atropos      bool            `json:"atropos"`
+	Block        *idx.Block      `json:"block"`
+}
+
+// JSONEdge is an edge of JSONGraph, from a child to its parent.
+type JSONEdge struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Relation string `json:"relation"`
+}
+
+// ToJSON converts the subgraph into JSON Graph Format.
+// Edges to parents outside of the subgraph are omitted.
+func ToJSON(g *Graph) *JSONGraph {
+	ids := g.ids()
+	res := &JSONGraph{}
+	res.Graph.Directed = true
+	res.Graph.Label = fmt.Sprintf("epoch %d", g.Epoch)
+	res.Graph.Metadata.Epoch = g.Epoch
+	res.Graph.Nodes = make(map[string]JSONNode, len(g.Nodes))
+	res.Graph.Edges = make([]JSONEdge, 0, len(g.Nodes)*2)
+	for _, n := range g.Nodes {
+		res.Graph.Nodes[n.ID.Hex()] = JSONNode{
+			Label: n.ID.String(),
+			Metadata: JSONNodeMetadata{
+				Creator:      n.Creator,
+				Seq:          n.Seq,
+				Lamport:      n.Lamport,
+				Frame:        n.Frame,
+				CreationTime: n.CreationTime,
+				MedianTime:   n.MedianTime,
+				Root:         n.Root,
+				Atropos:      n.Atropos,
+				Block:        n.Block,
+			},
+		}
+		for i, p := range n.Parents {
+			if !ids[p] {
+				continue
+			}
+			relation := "parent"
+			if i == 0 && n.Seq > 1 {
+				relation = "self-parent"
+			}
+			res.Graph.Edges = append(res.Graph.Edges, JSONEdge{
+				Source:   n.ID.Hex(),
+				Target:   p.Hex(),
+				Relation: relation,
+			})
+		}
+	}
+	return res
+}
+
+// WriteJSON writes the subgraph in JSON Graph Format.
+func WriteJSON(w io.Writer, g *Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ToJSON(g))
+}
@@ -0,0 +1,22 @@
+package inter
+
+import (
+	"github.com/skyhighblockchain/push-base/inter/idx"
+)
+
+// DoubleSign is a proof of a fork: two distinct events signed by the same creator
+// within the same epoch and with the same sequence number.
+type DoubleSign struct {
+	First  *EventPayload
+	Second *EventPayload
+}
+
+// Creator returns the validator which created both events.
+func (ds DoubleSign) Creator() idx.ValidatorID {
+	return ds.First.Creator()
+}
+
+// Seq returns the sequence number of both events.
+func (ds DoubleSign) Seq() idx.Event {
+	return ds.First.Seq()
+}
@@ -0,0 +1,261 @@
+package topicsdb
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/skyhighblockchain/push-base/inter/idx"
+)
+
+/*
+	Bloom bits are stored like in geth's bloombits: for each section of blocks and for each of the bloom bits
+	there's a bit vector, where bit N is set if the bloom of block section*BloomSectionSize+N has the bloom bit set.
+	Unlike the block bloom, values are mixed with their pattern position.
+	Bloom bits aren't deleted along with the log records, so they may contain false positives.
+*/
+
+const (
+	// BloomSectionSize is a number of blocks in the bloom bits section
+	BloomSectionSize = 4096
+
+	bloomBitsCount   = types.BloomBitLength
+	bloomVectorSize  = BloomSectionSize / 8
+	bloomBitKeySize  = uint64Size + 2
+	bloomValuesCount = 3
+	// max number of non-candidate blocks between candidate blocks, which are searched at once
+	bloomMaxGap = 64
+)
+
+var (
+	bloomStartKey   = []byte("s")
+	bloomHighestKey = []byte("h")
+)
+
+// bloomBits returns the bloom bits of the value at the pattern position.
+func bloomBits(value common.Hash, pos uint8) (bits [bloomValuesCount]uint16) {
+	h := crypto.Keccak256(value.Bytes(), posToBytes(pos))
+	for i := range bits {
+		bits[i] = (uint16(h[2*i])<<8 | uint16(h[2*i+1])) % bloomBitsCount
+	}
+	return
+}
+
+func bloomBitKey(section uint64, bit uint16) []byte {
+	key := make([]byte, 0, bloomBitKeySize)
+	key = append(key, uintToBytes(section)...)
+	key = append(key, byte(bit>>8), byte(bit))
+	return key
+}
+
+// loadBloomRange reads the range of blocks, which are covered by the bloom bits.
+func (tt *Index) loadBloomRange() {
+	if buf, err := tt.table.Meta.Get(bloomStartKey); err == nil && buf != nil {
+		atomic.StoreUint64(&tt.bloomStart, bytesToUint(buf)+1)
+	}
+	if buf, err := tt.table.Meta.Get(bloomHighestKey); err == nil && buf != nil {
+		atomic.StoreUint64(&tt.bloomHighest, bytesToUint(buf))
+	}
+}
+
+// getBloomStart returns the first block, which log records are all covered by the bloom bits.
+// Log records, which were pushed before the bloom bits were introduced, aren't covered.
+func (tt *Index) getBloomStart() (uint64, bool) {
+	start := atomic.LoadUint64(&tt.bloomStart)
+	if start == 0 {
+		return 0, false
+	}
+	return start - 1, true
+}
+
+// pushBloomBits sets the bloom bits of the log records.
+func (tt *Index) pushBloomBits(recs []*types.Log) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	if _, ok := tt.getBloomStart(); !ok {
+		start := recs[0].BlockNumber
+		if err := tt.table.Meta.Put(bloomStartKey, uintToBytes(start)); err != nil {
+			return err
+		}
+		atomic.StoreUint64(&tt.bloomStart, start+1)
+	}
+
+	vectors := make(map[string][]byte)
+	setBit := func(block uint64, bit uint16) error {
+		key := bloomBitKey(block/BloomSectionSize, bit)
+		vector, ok := vectors[string(key)]
+		if !ok {
+			buf, err := tt.table.Bloom.Get(key)
+			if err != nil {
+				return err
+			}
+			vector = make([]byte, bloomVectorSize)
+			copy(vector, buf)
+			vectors[string(key)] = vector
+		}
+		n := block % BloomSectionSize
+		vector[n/8] |= 1 << (7 - n%8)
+		return nil
+	}
+	setValue := func(block uint64, value common.Hash, pos uint8) error {
+		for _, bit := range bloomBits(value, pos) {
+			if err := setBit(block, bit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	highest := atomic.LoadUint64(&tt.bloomHighest)
+	for _, rec := range recs {
+		if err := setValue(rec.BlockNumber, rec.Address.Hash(), 0); err != nil {
+			return err
+		}
+		for j, topic := range rec.Topics {
+			if j >= MaxTopicsCount {
+				break
+			}
+			if err := setValue(rec.BlockNumber, topic, uint8(j+1)); err != nil {
+				return err
+			}
+		}
+		if rec.BlockNumber > highest {
+			highest = rec.BlockNumber
+		}
+	}
+
+	for key, vector := range vectors {
+		if err := tt.table.Bloom.Put([]byte(key), vector); err != nil {
+			return err
+		}
+	}
+	if highest > atomic.LoadUint64(&tt.bloomHighest) {
+		if err := tt.table.Meta.Put(bloomHighestKey, uintToBytes(highest)); err != nil {
+			return err
+		}
+		atomic.StoreUint64(&tt.bloomHighest, highest)
+	}
+	return nil
+}
+
+// sectionCandidates returns the bit vector of the section blocks, which may contain log records matching the pattern.
+func (tt *Index) sectionCandidates(section uint64, pattern [][]common.Hash) ([]byte, error) {
+	res := make([]byte, bloomVectorSize)
+	for i := range res {
+		res[i] = 0xff
+	}
+	for pos, variants := range pattern {
+		if len(variants) == 0 {
+			continue
+		}
+		union := make([]byte, bloomVectorSize)
+		for _, variant := range variants {
+			match := make([]byte, bloomVectorSize)
+			for i := range match {
+				match[i] = 0xff
+			}
+			for _, bit := range bloomBits(variant, uint8(pos)) {
+				vector, err := tt.table.Bloom.Get(bloomBitKey(section, bit))
+				if err != nil {
+					return nil, err
+				}
+				if vector == nil {
+					vector = make([]byte, bloomVectorSize)
+				}
+				for i := range match {
+					match[i] &= vector[i]
+				}
+			}
+			for i := range union {
+				union[i] |= match[i]
+			}
+		}
+		for i := range res {
+			res[i] &= union[i]
+		}
+	}
+	return res, nil
+}
+
+// searchBlocks matches log records of block range by pattern, which IDs are greater or equal to after (if not nil).
+// Blocks, which are covered by the bloom bits, are searched only if their bloom bits match the pattern.
+func (tt *Index) searchBlocks(ctx context.Context, pattern [][]common.Hash, from, to idx.Block, after *ID, onMatched logHandler) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stopped := false
+	handler := func(rec *logrec) (gonext bool, err error) {
+		gonext, err = onMatched(rec)
+		stopped = !gonext
+		return
+	}
+	search := func(from, to uint64) error {
+		start := uintToBytes(from)
+		if after != nil && after.BlockNumber() >= from {
+			if after.BlockNumber() > to {
+				return nil
+			}
+			start = after.Bytes()
+		}
+		return tt.searchLazy(ctx, pattern, start, to, handler)
+	}
+
+	begin, end := uint64(from), uint64(to)
+	bloomStart, ok := tt.getBloomStart()
+	if !ok || bloomStart > end {
+		return search(begin, end)
+	}
+	if begin < bloomStart {
+		if err := search(begin, bloomStart-1); err != nil || stopped {
+			return err
+		}
+		begin = bloomStart
+	}
+	// there're no log records after the highest block
+	if highest := atomic.LoadUint64(&tt.bloomHighest); end > highest {
+		end = highest
+	}
+
+	for section := begin / BloomSectionSize; section <= end/BloomSectionSize; section++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		candidates, err := tt.sectionCandidates(section, pattern)
+		if err != nil {
+			return err
+		}
+		isCandidate := func(block uint64) bool {
+			n := block % BloomSectionSize
+			return candidates[n/8]&(1<<(7-n%8)) != 0
+		}
+
+		first := section * BloomSectionSize
+		last := first + BloomSectionSize - 1
+		if first < begin {
+			first = begin
+		}
+		if last > end {
+			last = end
+		}
+		for block := first; block <= last; block++ {
+			if !isCandidate(block) {
+				continue
+			}
+			// search the sequence of candidate blocks at once, as a short gap is cheaper to scan than to open a new iterator
+			runEnd := block
+			for next := block + 1; next <= last && next-runEnd <= bloomMaxGap; next++ {
+				if isCandidate(next) {
+					runEnd = next
+				}
+			}
+			if err := search(block, runEnd); err != nil || stopped {
+				return err
+			}
+			block = runEnd
+		}
+	}
+	return nil
+}
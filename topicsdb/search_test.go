@@ -2,12 +2,17 @@ package topicsdb
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/skyhighblockchain/push-base/hash"
 	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb/flushable"
+	"github.com/skyhighblockchain/push-base/kvdb/leveldb"
 	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
 	"github.com/stretchr/testify/require"
 )
@@ -52,3 +57,79 @@ func BenchmarkSearch(b *testing.B) {
 		})
 	}
 }
+
+// genRealisticData generates log records with skewed distributions of contracts and event signatures,
+// where transfers of a few popular tokens dominate.
+func genRealisticData(blocks int) (signatures []common.Hash, contracts []common.Address, users []common.Hash, recs []*types.Log) {
+	r := rand.New(rand.NewSource(1))
+	signatures = make([]common.Hash, 10)
+	for i := range signatures {
+		signatures[i] = hash.FakeHash(int64(i))
+	}
+	contracts = make([]common.Address, 1000)
+	for i := range contracts {
+		contracts[i] = common.BytesToAddress(hash.FakeHash(int64(1000 + i)).Bytes())
+	}
+	users = make([]common.Hash, 10000)
+	for i := range users {
+		users[i] = hash.FakeHash(int64(10000 + i))
+	}
+	contractsZipf := rand.NewZipf(r, 1.2, 1, uint64(len(contracts)-1))
+	signaturesZipf := rand.NewZipf(r, 2, 1, uint64(len(signatures)-1))
+
+	for n := 0; n < blocks; n++ {
+		for i := 0; i < 3; i++ {
+			recs = append(recs, &types.Log{
+				BlockNumber: uint64(n),
+				TxHash:      hash.FakeHash(int64(n*3 + i)),
+				Index:       uint(i),
+				Address:     contracts[contractsZipf.Uint64()],
+				Topics: []common.Hash{
+					signatures[signaturesZipf.Uint64()],
+					users[r.Intn(len(users))],
+					users[r.Intn(len(users))],
+				},
+			})
+		}
+	}
+	return
+}
+
+func BenchmarkSearchBloom(b *testing.B) {
+	const blocks = 2000
+	signatures, contracts, users, recs := genRealisticData(blocks)
+
+	queries := map[string][][]common.Hash{
+		"popular contract":    {{contracts[0].Hash()}},
+		"rare contract":       {{contracts[len(contracts)-1].Hash()}},
+		"transfers from user": {{}, {signatures[0]}, {users[0]}},
+		"contract transfers":  {{contracts[1].Hash()}, {signatures[0]}},
+		"rare topic":          {{}, {}, {}, {users[1]}},
+	}
+
+	for _, bloom := range []bool{false, true} {
+		// memorydb isn't suitable for realistic iterations, as it copies all the keys into every iterator
+		ldb, err := leveldb.New(b.TempDir(), 16, 0, nil, nil)
+		require.NoError(b, err)
+		db := flushable.Wrap(ldb)
+		defer db.Close()
+		index := New(db)
+		for n := 0; n < blocks; n++ {
+			require.NoError(b, index.Push(recs[n*3:n*3+3]...))
+		}
+		require.NoError(b, db.Flush())
+		if !bloom {
+			// emulate log records, which are indexed before the bloom bits were introduced
+			index.bloomStart = 0
+		}
+
+		for dsc, pattern := range queries {
+			b.Run(fmt.Sprintf("%s/bloom=%v", dsc, bloom), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, err := index.FindInBlocks(nil, 0, blocks, pattern)
+					require.NoError(b, err)
+				}
+			})
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package topicsdb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/skyhighblockchain/push-base/inter/idx"
 	"github.com/skyhighblockchain/push-base/kvdb"
@@ -21,12 +23,20 @@ var (
 
 // Index is a specialized indexes for log records storing and fetching.
 type Index struct {
+	// first block covered by the bloom bits plus 1 (0 if none), and the highest indexed block
+	bloomStart   uint64
+	bloomHighest uint64
+
 	db    kvdb.Store
 	table struct {
 		// topic+topicN+(blockN+TxHash+logIndex) -> topic_count (where topicN=0 is for address)
 		Topic kvdb.Store `table:"t"`
 		// (blockN+TxHash+logIndex) -> ordered topic_count topics, blockHash, address, data
 		Logrec kvdb.Store `table:"r"`
+		// section+bloomBit -> bit vector of section blocks
+		Bloom kvdb.Store `table:"b"`
+		// bloom bits range
+		Meta kvdb.Store `table:"m"`
 	}
 }
 
@@ -37,6 +47,7 @@ func New(db kvdb.Store) *Index {
 	}
 
 	table.MigrateTables(&tt.table, tt.db)
+	tt.loadBloomRange()
 
 	return tt
 }
@@ -56,6 +67,73 @@ func (tt *Index) FindInBlocks(ctx context.Context, from, to idx.Block, pattern [
 	return
 }
 
+// FindInBlocksPage returns up to limit log records of block range by pattern, which IDs are greater than after (if not nil).
+// Records are ordered by ID, i.e. by block number, tx hash and log index. 1st pattern element is an address.
+// Returns true if there're more matching records after the returned ones.
+// Unlike FindInBlocks, only limit+1 records are kept in memory.
+func (tt *Index) FindInBlocksPage(ctx context.Context, from, to idx.Block, after *ID, pattern [][]common.Hash, limit int) (logs []*types.Log, more bool, err error) {
+	if from > to || limit <= 0 {
+		return nil, false, nil
+	}
+	pattern, err = limitPattern(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// records of a single variant are iterated in the ID order, but different variants aren't merged,
+	// so every variant of the first non-empty position is iterated separately
+	first := 0
+	for len(pattern[first]) == 0 {
+		first++
+	}
+	variants := pattern[first]
+
+	page := make([]*logrec, 0, limit+1)
+	onMatched := func(rec *logrec) (gonext bool, err error) {
+		if after != nil && bytes.Compare(rec.ID.Bytes(), after.Bytes()) <= 0 {
+			return true, nil
+		}
+		pos := sort.Search(len(page), func(i int) bool {
+			return bytes.Compare(page[i].ID.Bytes(), rec.ID.Bytes()) >= 0
+		})
+		if pos < len(page) && page[pos].ID == rec.ID {
+			return true, nil
+		}
+		if pos > limit {
+			// the rest records of the variant are greater
+			return false, nil
+		}
+		if len(page) <= limit {
+			page = append(page, nil)
+		}
+		copy(page[pos+1:], page[pos:])
+		page[pos] = rec
+		return true, nil
+	}
+
+	for _, variant := range variants {
+		pattern[first] = []common.Hash{variant}
+		err = tt.searchBlocks(ctx, pattern, from, to, after, onMatched)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if len(page) > limit {
+		page = page[:limit]
+		more = true
+	}
+	logs = make([]*types.Log, len(page))
+	for i, rec := range page {
+		rec.fetch(tt.table.Logrec)
+		if rec.err != nil {
+			return nil, false, rec.err
+		}
+		logs[i] = rec.result
+	}
+	return logs, more, nil
+}
+
 // ForEach matches log records by pattern. 1st pattern element is an address.
 func (tt *Index) ForEach(ctx context.Context, pattern [][]common.Hash, onLog func(*types.Log) (gonext bool)) error {
 	pattern, err := limitPattern(pattern)
@@ -97,7 +175,7 @@ func (tt *Index) ForEachInBlocks(ctx context.Context, from, to idx.Block, patter
 		return
 	}
 
-	return tt.searchLazy(ctx, pattern, uintToBytes(uint64(from)), uint64(to), onMatched)
+	return tt.searchBlocks(ctx, pattern, from, to, nil, onMatched)
 }
 
 func limitPattern(pattern [][]common.Hash) (limited [][]common.Hash, err error) {
@@ -169,5 +247,32 @@ func (tt *Index) Push(recs ...*types.Log) error {
 		}
 	}
 
+	return tt.pushBloomBits(recs)
+}
+
+// Delete log records and their topics index from database.
+// Bloom bits are kept, as they may be shared with other records.
+// Records must have the same BlockNumber, TxHash, Index, Address and Topics as pushed.
+func (tt *Index) Delete(recs ...*types.Log) error {
+	for _, rec := range recs {
+		id := NewID(rec.BlockNumber, rec.TxHash, rec.Index)
+
+		if err := tt.table.Topic.Delete(topicKey(rec.Address.Hash(), 0, id)); err != nil {
+			return err
+		}
+		for j, topic := range rec.Topics {
+			if j >= MaxTopicsCount {
+				break
+			}
+			if err := tt.table.Topic.Delete(topicKey(topic, uint8(j+1), id)); err != nil {
+				return err
+			}
+		}
+
+		if err := tt.table.Logrec.Delete(id.Bytes()); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
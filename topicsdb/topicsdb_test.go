@@ -1,9 +1,11 @@
 package topicsdb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"testing"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/skyhighblockchain/push-base/hash"
 	"github.com/skyhighblockchain/push-base/inter/idx"
+	"github.com/skyhighblockchain/push-base/kvdb"
 	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
 	"github.com/stretchr/testify/require"
 
@@ -348,6 +351,134 @@ func TestMaxTopicsCount(t *testing.T) {
 	require.Equal(t, MaxTopicsCount+1, len(pattern[0]))
 }
 
+func TestIndexDelete(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	index := New(memorydb.New())
+	topics, recs, _ := genTestData(20)
+	require.NoError(index.Push(recs...))
+
+	// delete records of the first 2 blocks
+	require.NoError(index.Delete(recs[:10]...))
+	got, err := index.FindInBlocks(nil, 0, 0xffffffff, [][]common.Hash{{}, topics[:1]})
+	require.NoError(err)
+	for _, rec := range got {
+		require.True(rec.BlockNumber >= 2)
+	}
+	expected := 0
+	for _, rec := range recs[10:] {
+		if rec.Topics[0] == topics[0] {
+			expected++
+		}
+	}
+	require.Len(got, expected)
+
+	// nothing is left after all the records are deleted, except the bloom bits
+	require.NoError(index.Delete(recs[10:]...))
+	for _, table := range []kvdb.Store{index.table.Topic, index.table.Logrec} {
+		it := table.NewIterator(nil, nil)
+		require.False(it.Next())
+		it.Release()
+	}
+}
+
+func TestIndexFindInBlocksPage(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	index := New(memorydb.New())
+	topics, recs, _ := genTestData(100)
+	require.NoError(index.Push(recs...))
+
+	for _, pattern := range [][][]common.Hash{
+		{{}, topics[:3]},
+		{{}, {}, topics[1:2]},
+		{{recs[0].Address.Hash(), recs[1].Address.Hash(), recs[2].Address.Hash()}},
+	} {
+		expected, err := index.FindInBlocks(nil, 2, 15, pattern)
+		require.NoError(err)
+		sort.Slice(expected, func(i, j int) bool {
+			a := NewID(expected[i].BlockNumber, expected[i].TxHash, expected[i].Index)
+			b := NewID(expected[j].BlockNumber, expected[j].TxHash, expected[j].Index)
+			return bytes.Compare(a.Bytes(), b.Bytes()) < 0
+		})
+
+		var (
+			got   []*types.Log
+			after *ID
+		)
+		for {
+			page, more, err := index.FindInBlocksPage(nil, 2, 15, after, pattern, 7)
+			require.NoError(err)
+			require.True(len(page) <= 7)
+			got = append(got, page...)
+			if !more {
+				break
+			}
+			last := page[len(page)-1]
+			id := NewID(last.BlockNumber, last.TxHash, last.Index)
+			after = &id
+		}
+		require.Equal(expected, got)
+	}
+}
+
+func TestIndexBloomBits(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	signatures, contracts, users, recs := genRealisticData(100)
+	for i, rec := range recs {
+		// spread the records over several sections
+		rec.BlockNumber = uint64(i/3) * 137
+	}
+	db := memorydb.New()
+	legacy := 90
+
+	// emulate the records, which are indexed before the bloom bits were introduced
+	index := New(db)
+	require.NoError(index.Push(recs[:legacy]...))
+	for _, table := range []kvdb.Store{index.table.Bloom, index.table.Meta} {
+		it := table.NewIterator(nil, nil)
+		for it.Next() {
+			require.NoError(table.Delete(it.Key()))
+		}
+		it.Release()
+	}
+
+	index = New(db)
+	for i := legacy; i < len(recs); i += 3 {
+		require.NoError(index.Push(recs[i : i+3]...))
+	}
+	start, ok := index.getBloomStart()
+	require.True(ok)
+	require.Equal(recs[legacy].BlockNumber, start)
+
+	for _, pattern := range [][][]common.Hash{
+		{{contracts[0].Hash()}},
+		{{contracts[1].Hash(), contracts[2].Hash()}, {signatures[0]}},
+		{{}, {signatures[1]}},
+		{{}, {}, {recs[0].Topics[1], recs[len(recs)-1].Topics[1]}},
+		{{}, {}, {}, {users[0]}},
+	} {
+		for _, blocks := range [][2]idx.Block{{0, 0xffffffff}, {1000, 9000}, {5000, 5000}} {
+			from, to := blocks[0], blocks[1]
+			var expected []*types.Log
+			require.NoError(index.ForEach(nil, pattern, func(l *types.Log) bool {
+				if idx.Block(l.BlockNumber) >= from && idx.Block(l.BlockNumber) <= to {
+					expected = append(expected, l)
+				}
+				return true
+			}))
+
+			got, err := index.FindInBlocks(nil, from, to, pattern)
+			require.NoError(err)
+			require.ElementsMatch(expected, got)
+		}
+	}
+}
+
 func genTestData(count int) (
 	topics []common.Hash,
 	recs []*types.Log,
@@ -0,0 +1,67 @@
+package ancient
+
+import (
+	"fmt"
+	"os"
+)
+
+// Store is a set of append-only tables, stored in a directory
+type Store struct {
+	dir    string
+	tables map[string]*Table
+}
+
+// Open opens or creates the tables in the directory
+func Open(dir string, names ...string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	s := &Store{
+		dir:    dir,
+		tables: make(map[string]*Table, len(names)),
+	}
+	for _, name := range names {
+		t, err := OpenTable(dir, name)
+		if err != nil {
+			_ = s.Close()
+			return nil, err
+		}
+		s.tables[name] = t
+	}
+	return s, nil
+}
+
+// Dir returns the directory of the store
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Table returns the table by name. Panics if the table wasn't opened.
+func (s *Store) Table(name string) *Table {
+	t, ok := s.tables[name]
+	if !ok {
+		panic(fmt.Sprintf("ancient table %s isn't opened", name))
+	}
+	return t
+}
+
+// Sync flushes all the tables to disk
+func (s *Store) Sync() error {
+	for _, t := range s.tables {
+		if err := t.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the tables
+func (s *Store) Close() error {
+	var err error
+	for _, t := range s.tables {
+		if e := t.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,262 @@
+package ancient
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	indexEntrySize  = 8
+	indexHeaderSize = 8
+)
+
+var (
+	// ErrOutOfBounds is returned if an item isn't in the table
+	ErrOutOfBounds = errors.New("out of bounds")
+	// ErrClosed is returned if the table is closed
+	ErrClosed = errors.New("closed")
+)
+
+// Table is an append-only table of blobs, stored in flat files.
+// Items are numbered sequentially, starting from the number of the first appended item.
+//
+// The index file consists of a header with the first item number,
+// and of the end offsets of each item in the data file.
+type Table struct {
+	index *os.File
+	data  *os.File
+
+	tail  uint64 // number of the first item
+	items uint64 // number of items
+	size  uint64 // size of the data file
+
+	mu sync.RWMutex
+}
+
+// OpenTable opens or creates a table. A partially written item is dropped.
+func OpenTable(dir, name string) (*Table, error) {
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.OpenFile(filepath.Join(dir, name+".dat"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		_ = index.Close()
+		return nil, err
+	}
+	t := &Table{
+		index: index,
+		data:  data,
+	}
+	if err := t.repair(); err != nil {
+		_ = t.Close()
+		return nil, fmt.Errorf("failed to open table %s: %v", name, err)
+	}
+	return t, nil
+}
+
+// repair makes index and data files consistent after an interrupted append
+func (t *Table) repair() error {
+	indexStat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	dataStat, err := t.data.Stat()
+	if err != nil {
+		return err
+	}
+	indexSize := uint64(indexStat.Size())
+	if indexSize < indexHeaderSize {
+		// the header is written along with the first item
+		return t.truncate(0, 0, 0)
+	}
+	var header [indexHeaderSize]byte
+	if _, err := t.index.ReadAt(header[:], 0); err != nil {
+		return err
+	}
+	t.tail = binary.BigEndian.Uint64(header[:])
+
+	items := (indexSize - indexHeaderSize) / indexEntrySize
+	dataSize := uint64(dataStat.Size())
+	// drop items which aren't written into the data file completely
+	for ; items > 0; items-- {
+		end, err := t.readOffset(items - 1)
+		if err != nil {
+			return err
+		}
+		if end <= dataSize {
+			return t.truncate(t.tail, items, end)
+		}
+	}
+	return t.truncate(t.tail, 0, 0)
+}
+
+func (t *Table) truncate(tail, items, size uint64) error {
+	indexSize := int64(indexHeaderSize + items*indexEntrySize)
+	if items == 0 {
+		indexSize = 0
+	}
+	if err := t.index.Truncate(indexSize); err != nil {
+		return err
+	}
+	if err := t.data.Truncate(int64(size)); err != nil {
+		return err
+	}
+	t.tail, t.items, t.size = tail, items, size
+	return nil
+}
+
+func (t *Table) readOffset(i uint64) (uint64, error) {
+	var buf [indexEntrySize]byte
+	if _, err := t.index.ReadAt(buf[:], int64(indexHeaderSize+i*indexEntrySize)); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+// Tail returns the number of the first item
+func (t *Table) Tail() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tail
+}
+
+// Head returns the number of the next item to append
+func (t *Table) Head() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tail + t.items
+}
+
+// Items returns the number of items
+func (t *Table) Items() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.items
+}
+
+// Has returns true if item is in the table
+func (t *Table) Has(item uint64) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return item >= t.tail && item < t.tail+t.items
+}
+
+// Append appends an item. The item number has to be equal to Head, unless the table is empty.
+func (t *Table) Append(item uint64, blob []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return ErrClosed
+	}
+	if t.items == 0 {
+		var header [indexHeaderSize]byte
+		binary.BigEndian.PutUint64(header[:], item)
+		if _, err := t.index.WriteAt(header[:], 0); err != nil {
+			return err
+		}
+		t.tail = item
+	} else if item != t.tail+t.items {
+		return fmt.Errorf("unexpected item %d, expected %d", item, t.tail+t.items)
+	}
+
+	if _, err := t.data.WriteAt(blob, int64(t.size)); err != nil {
+		return err
+	}
+	var entry [indexEntrySize]byte
+	binary.BigEndian.PutUint64(entry[:], t.size+uint64(len(blob)))
+	if _, err := t.index.WriteAt(entry[:], int64(indexHeaderSize+t.items*indexEntrySize)); err != nil {
+		return err
+	}
+	t.size += uint64(len(blob))
+	t.items++
+	return nil
+}
+
+// TruncateHead drops the items starting from the given item number, so head becomes the new Head
+func (t *Table) TruncateHead(head uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return ErrClosed
+	}
+	if head >= t.tail+t.items {
+		return nil
+	}
+	if head <= t.tail {
+		return t.truncate(t.tail, 0, 0)
+	}
+	items := head - t.tail
+	size, err := t.readOffset(items - 1)
+	if err != nil {
+		return err
+	}
+	return t.truncate(t.tail, items, size)
+}
+
+// Retrieve returns the item blob
+func (t *Table) Retrieve(item uint64) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.index == nil {
+		return nil, ErrClosed
+	}
+	if item < t.tail || item >= t.tail+t.items {
+		return nil, ErrOutOfBounds
+	}
+	i := item - t.tail
+	var start uint64
+	if i > 0 {
+		var err error
+		start, err = t.readOffset(i - 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	end, err := t.readOffset(i)
+	if err != nil {
+		return nil, err
+	}
+	if end < start || end > t.size {
+		return nil, fmt.Errorf("corrupted index of item %d", item)
+	}
+	blob := make([]byte, end-start)
+	if _, err := t.data.ReadAt(blob, int64(start)); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// Sync flushes the table files to disk. The data file is flushed first,
+// so the index never points to the data which isn't flushed.
+func (t *Table) Sync() error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.index == nil {
+		return ErrClosed
+	}
+	if err := t.data.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the table files
+func (t *Table) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.index == nil {
+		return nil
+	}
+	err1 := t.index.Close()
+	err2 := t.data.Close()
+	t.index, t.data = nil, nil
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
@@ -0,0 +1,107 @@
+package ancient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTable(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	table, err := OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(0), table.Items())
+
+	// the first item number defines the tail
+	require.NoError(table.Append(10, []byte("a")))
+	require.NoError(table.Append(11, []byte{}))
+	require.NoError(table.Append(12, []byte("ccc")))
+	require.Error(table.Append(14, []byte("e")))
+	require.Equal(uint64(10), table.Tail())
+	require.Equal(uint64(13), table.Head())
+	require.False(table.Has(9))
+	require.True(table.Has(11))
+	require.False(table.Has(13))
+
+	check := func(table *Table) {
+		blob, err := table.Retrieve(10)
+		require.NoError(err)
+		require.Equal([]byte("a"), blob)
+		blob, err = table.Retrieve(11)
+		require.NoError(err)
+		require.Empty(blob)
+		blob, err = table.Retrieve(12)
+		require.NoError(err)
+		require.Equal([]byte("ccc"), blob)
+		_, err = table.Retrieve(13)
+		require.Equal(ErrOutOfBounds, err)
+		_, err = table.Retrieve(9)
+		require.Equal(ErrOutOfBounds, err)
+	}
+	check(table)
+	require.NoError(table.Sync())
+	require.NoError(table.Close())
+	_, err = table.Retrieve(10)
+	require.Equal(ErrClosed, err)
+
+	// reopen
+	table, err = OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(13), table.Head())
+	check(table)
+	require.NoError(table.Append(13, []byte("dd")))
+	require.NoError(table.Close())
+
+	// simulate an interrupted append: the index entry is written, but the data is partially lost
+	dat := filepath.Join(dir, "test.dat")
+	stat, err := os.Stat(dat)
+	require.NoError(err)
+	require.NoError(os.Truncate(dat, stat.Size()-1))
+
+	table, err = OpenTable(dir, "test")
+	require.NoError(err)
+	require.Equal(uint64(13), table.Head())
+	check(table)
+	require.NoError(table.Append(13, []byte("dd")))
+	blob, err := table.Retrieve(13)
+	require.NoError(err)
+	require.Equal([]byte("dd"), blob)
+
+	// drop the last items
+	require.NoError(table.TruncateHead(15))
+	require.Equal(uint64(14), table.Head())
+	require.NoError(table.TruncateHead(13))
+	require.Equal(uint64(13), table.Head())
+	check(table)
+	require.NoError(table.Append(13, []byte("d")))
+	blob, err = table.Retrieve(13)
+	require.NoError(err)
+	require.Equal([]byte("d"), blob)
+	require.NoError(table.TruncateHead(0))
+	require.Equal(uint64(0), table.Items())
+	require.NoError(table.Close())
+}
+
+func TestStore(t *testing.T) {
+	require := require.New(t)
+	dir := filepath.Join(t.TempDir(), "ancient")
+
+	s, err := Open(dir, "a", "b")
+	require.NoError(err)
+	require.NoError(s.Table("a").Append(0, []byte("x")))
+	require.Panics(func() {
+		s.Table("c")
+	})
+	require.NoError(s.Sync())
+	require.NoError(s.Close())
+
+	s, err = Open(dir, "a", "b")
+	require.NoError(err)
+	require.Equal(uint64(1), s.Table("a").Head())
+	require.Equal(uint64(0), s.Table("b").Items())
+	require.NoError(s.Close())
+}
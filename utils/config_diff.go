@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"math/big"
+	"reflect"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// ConfigDiff returns dot-separated paths of the fields, which differ in two configs of the same type.
+// Nested structs are compared field by field, other values are compared as a whole.
+func ConfigDiff(a, b interface{}) []string {
+	return configDiff("", reflect.ValueOf(a), reflect.ValueOf(b), nil)
+}
+
+func configDiff(path string, a, b reflect.Value, diff []string) []string {
+	if a.Kind() == reflect.Struct {
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// unexported
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			diff = configDiff(fieldPath, a.Field(i), b.Field(i), diff)
+		}
+		return diff
+	}
+	if a.Type() == bigIntType {
+		x, y := a.Interface().(*big.Int), b.Interface().(*big.Int)
+		if (x == nil) != (y == nil) || (x != nil && x.Cmp(y) != 0) {
+			diff = append(diff, path)
+		}
+		return diff
+	}
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		diff = append(diff, path)
+	}
+	return diff
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDiff(t *testing.T) {
+	require := require.New(t)
+
+	type inner struct {
+		Period time.Duration
+		Price  *big.Int
+	}
+	type config struct {
+		Name   string
+		Limits []uint64
+		Inner  inner
+		hidden int
+	}
+	a := config{
+		Name:   "a",
+		Limits: []uint64{1, 2},
+		Inner:  inner{time.Second, big.NewInt(1)},
+		hidden: 1,
+	}
+
+	b := a
+	b.hidden = 2
+	b.Inner.Price = new(big.Int).SetBytes([]byte{1})
+	require.Empty(ConfigDiff(a, b))
+
+	b.Name = "b"
+	b.Limits = []uint64{1, 3}
+	b.Inner.Price = nil
+	require.Equal([]string{"Name", "Limits", "Inner.Price"}, ConfigDiff(a, b))
+
+	b = a
+	b.Inner.Period = time.Minute
+	require.Equal([]string{"Inner.Period"}, ConfigDiff(a, b))
+}
@@ -7,7 +7,8 @@ type IDStore interface {
 }
 
 type inmemIDStore struct {
-	lastID string
+	lastID      string
+	checkpoints map[string][]byte
 }
 
 func (p *inmemIDStore) GetID() string {
@@ -17,3 +18,18 @@ func (p *inmemIDStore) GetID() string {
 func (p *inmemIDStore) SetID(id string) {
 	p.lastID = id
 }
+
+func (p *inmemIDStore) GetCheckpoint(id string) []byte {
+	return p.checkpoints[id]
+}
+
+func (p *inmemIDStore) SetCheckpoint(id string, checkpoint []byte) {
+	if checkpoint == nil {
+		delete(p.checkpoints, id)
+		return
+	}
+	if p.checkpoints == nil {
+		p.checkpoints = make(map[string][]byte)
+	}
+	p.checkpoints[id] = checkpoint
+}
@@ -5,12 +5,15 @@ import (
 	"github.com/skyhighblockchain/push-base/kvdb"
 )
 
-// KvdbIDStore stores id
+// KvdbIDStore stores id and checkpoints of interrupted migrations
 type KvdbIDStore struct {
 	table kvdb.Store
 	key   []byte
 }
 
+// checkpointPrefix is a key prefix of the checkpoints, it doesn't collide with the id key
+const checkpointPrefix = "c/"
+
 // NewKvdbIDStore constructor
 func NewKvdbIDStore(table kvdb.Store) *KvdbIDStore {
 	return &KvdbIDStore{
@@ -39,3 +42,25 @@ func (p *KvdbIDStore) SetID(id string) {
 		log.Crit("Failed to put key-value", "err", err)
 	}
 }
+
+// GetCheckpoint returns the checkpoint of the migration
+func (p *KvdbIDStore) GetCheckpoint(id string) []byte {
+	checkpoint, err := p.table.Get([]byte(checkpointPrefix + id))
+	if err != nil {
+		log.Crit("Failed to get key-value", "err", err)
+	}
+	return checkpoint
+}
+
+// SetCheckpoint stores the checkpoint of the migration, nil checkpoint deletes it
+func (p *KvdbIDStore) SetCheckpoint(id string, checkpoint []byte) {
+	var err error
+	if checkpoint == nil {
+		err = p.table.Delete([]byte(checkpointPrefix + id))
+	} else {
+		err = p.table.Put([]byte(checkpointPrefix+id), checkpoint)
+	}
+	if err != nil {
+		log.Crit("Failed to put key-value", "err", err)
+	}
+}
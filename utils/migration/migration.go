@@ -4,17 +4,29 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 )
 
 // Migration is a migration step.
 type Migration struct {
 	name string
-	exec func() error
+	step Step
 	prev *Migration
 }
 
+// Step is a migration step, which may report its progress and be resumed from a checkpoint after an interruption.
+type Step struct {
+	// Exec applies the step. An interrupted step is resumed from p.Checkpoint(), if the step sets checkpoints.
+	Exec func(p *Progress) error
+	// Estimate returns an estimated number of the work units of the step, optional
+	Estimate func() uint64
+	// Tables are names of the tables, which are modified by the step, optional
+	Tables []string
+}
+
 // Begin with empty unique migration step.
 func Begin(appName string) *Migration {
 	return &Migration{
@@ -24,21 +36,61 @@ func Begin(appName string) *Migration {
 
 // Next creates next migration.
 func (m *Migration) Next(name string, exec func() error) *Migration {
+	if exec == nil {
+		panic("empty exec")
+	}
+
+	return m.NextStep(name, Step{
+		Exec: func(*Progress) error {
+			return exec()
+		},
+	})
+}
+
+// NextStep creates next migration, which may report its progress and be resumed from a checkpoint.
+func (m *Migration) NextStep(name string, step Step) *Migration {
 	if name == "" {
 		panic("empty name")
 	}
 
-	if exec == nil {
+	if step.Exec == nil {
 		panic("empty exec")
 	}
 
 	return &Migration{
 		name: name,
-		exec: exec,
+		step: step,
 		prev: m,
 	}
 }
 
+// Name of the migration.
+func (m *Migration) Name() string {
+	return m.name
+}
+
+// Estimate returns an estimated number of the work units of the migration, if the migration is able to estimate it.
+func (m *Migration) Estimate() (uint64, bool) {
+	if m.step.Estimate == nil {
+		return 0, false
+	}
+	return m.step.Estimate(), true
+}
+
+// Tables returns names of the tables, which are modified by the migration.
+func (m *Migration) Tables() []string {
+	return m.step.Tables
+}
+
+// Checkpoint returns the checkpoint of an interrupted migration, or nil.
+func (m *Migration) Checkpoint(curr IDStore) []byte {
+	checkpoints, ok := curr.(CheckpointStore)
+	if !ok {
+		return nil
+	}
+	return checkpoints.GetCheckpoint(m.ID())
+}
+
 // ID is an uniq migration's id.
 func (m *Migration) ID() string {
 	digest := sha256.New()
@@ -69,20 +121,51 @@ func (m *Migration) Exec(curr IDStore, flush func() error) error {
 		return err
 	}
 
-	log.Warn("Applying migration", "name", m.name)
-	err = m.exec()
+	p := newProgress(m, curr, flush)
+	if p.Checkpoint() != nil {
+		log.Warn("Resuming migration", "name", m.name, "work", p.total)
+	} else {
+		log.Warn("Applying migration", "name", m.name, "work", p.total)
+	}
+	err = m.step.Exec(p)
 	if err != nil {
 		log.Error("'"+m.name+"' migration failed", "err", err)
 		return err
 	}
+	if p.checkpoints != nil {
+		p.checkpoints.SetCheckpoint(myID, nil)
+	}
 
 	curr.SetID(myID)
+	log.Info("Applied migration", "name", m.name, "elapsed", common.PrettyDuration(time.Since(p.start)))
 
 	return flush()
 }
 
+// Pending returns the migrations of the chain, which aren't applied yet, in the order of execution.
+func (m *Migration) Pending(curr IDStore) ([]*Migration, error) {
+	currID := curr.GetID()
+
+	if m.veryFirst() {
+		if currID != "" && currID != m.ID() {
+			return nil, errors.New("unknown version: " + currID)
+		}
+		return nil, nil
+	}
+
+	if currID == m.ID() {
+		return nil, nil
+	}
+
+	pending, err := m.prev.Pending(curr)
+	if err != nil {
+		return nil, err
+	}
+	return append(pending, m), nil
+}
+
 func (m *Migration) veryFirst() bool {
-	return m.exec == nil
+	return m.step.Exec == nil
 }
 
 // IDs return list of migrations ids in chain
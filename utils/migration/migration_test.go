@@ -99,6 +99,77 @@ func TestMigrations(t *testing.T) {
 	})
 }
 
+func TestMigrationsPending(t *testing.T) {
+	require := require.New(t)
+
+	curVer := &inmemIDStore{}
+	first := Begin("pending").Next("01", func() error { return nil })
+	last := first.NextStep("02", Step{
+		Exec:     func(*Progress) error { return nil },
+		Estimate: func() uint64 { return 10 },
+		Tables:   []string{"table"},
+	})
+
+	pending, err := last.Pending(curVer)
+	require.NoError(err)
+	require.Equal([]*Migration{first, last}, pending)
+	estimate, ok := last.Estimate()
+	require.True(ok)
+	require.Equal(uint64(10), estimate)
+	_, ok = first.Estimate()
+	require.False(ok)
+	require.Equal([]string{"table"}, last.Tables())
+
+	require.NoError(first.Exec(curVer, flush))
+	pending, err = last.Pending(curVer)
+	require.NoError(err)
+	require.Equal([]*Migration{last}, pending)
+
+	require.NoError(last.Exec(curVer, flush))
+	pending, err = last.Pending(curVer)
+	require.NoError(err)
+	require.Empty(pending)
+
+	_, err = Begin("other").Next("01", func() error { return nil }).Pending(curVer)
+	require.Error(err)
+}
+
+func TestMigrationsCheckpoint(t *testing.T) {
+	require := require.New(t)
+
+	curVer := &inmemIDStore{}
+	var applied []int
+	step := func(fail int) *Migration {
+		return Begin("checkpoint").NextStep("01", Step{
+			Exec: func(p *Progress) error {
+				from := 0
+				if c := p.Checkpoint(); c != nil {
+					from = int(c[0])
+				}
+				for i := from; i < 10; i++ {
+					if i == fail {
+						return errors.New("interrupted")
+					}
+					applied = append(applied, i)
+					p.Report(uint64(i + 1))
+					require.NoError(p.SetCheckpoint([]byte{byte(i + 1)}))
+				}
+				return nil
+			},
+		})
+	}
+
+	interrupted := step(5)
+	require.Error(interrupted.Exec(curVer, flush))
+	require.Equal([]byte{5}, interrupted.Checkpoint(curVer))
+
+	resumed := step(-1)
+	require.NoError(resumed.Exec(curVer, flush))
+	require.Equal([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, applied)
+	require.Nil(resumed.Checkpoint(curVer))
+	require.Equal(resumed.ID(), curVer.GetID())
+}
+
 func flush() error {
 	return nil
 }
@@ -0,0 +1,98 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// progressReportPeriod is a period of logging the progress of a long migration
+const progressReportPeriod = 8 * time.Second
+
+var (
+	progressDoneGauge  = metrics.NewRegisteredGauge("migration/done", nil)
+	progressTotalGauge = metrics.NewRegisteredGauge("migration/total", nil)
+)
+
+// CheckpointStore keeps checkpoints of interrupted migrations.
+type CheckpointStore interface {
+	GetCheckpoint(id string) []byte
+	// SetCheckpoint stores the checkpoint, nil checkpoint deletes it
+	SetCheckpoint(id string, checkpoint []byte)
+}
+
+// Progress of a migration, which is being applied.
+type Progress struct {
+	name  string
+	id    string
+	done  uint64
+	total uint64
+	// resumed is the first reported number of done work units, which may be non-zero if the migration is resumed
+	resumed *uint64
+
+	checkpoints CheckpointStore
+	flush       func() error
+
+	start    time.Time
+	reported time.Time
+}
+
+func newProgress(m *Migration, curr IDStore, flush func() error) *Progress {
+	p := &Progress{
+		name:     m.name,
+		id:       m.ID(),
+		flush:    flush,
+		start:    time.Now(),
+		reported: time.Now(),
+	}
+	p.total, _ = m.Estimate()
+	p.checkpoints, _ = curr.(CheckpointStore)
+	progressDoneGauge.Update(0)
+	progressTotalGauge.Update(int64(p.total))
+	return p
+}
+
+// Checkpoint returns the checkpoint of an interrupted migration, or nil if the migration is started from scratch.
+func (p *Progress) Checkpoint() []byte {
+	if p.checkpoints == nil {
+		return nil
+	}
+	return p.checkpoints.GetCheckpoint(p.id)
+}
+
+// SetCheckpoint flushes the changes, and stores the checkpoint to resume the migration from if it's interrupted.
+// The checkpoint is flushed together with the changes, so it's consistent with them.
+func (p *Progress) SetCheckpoint(checkpoint []byte) error {
+	if p.checkpoints == nil {
+		return nil
+	}
+	p.checkpoints.SetCheckpoint(p.id, checkpoint)
+	return p.flush()
+}
+
+// Report updates the number of the done work units, and logs the progress periodically.
+func (p *Progress) Report(done uint64) {
+	p.done = done
+	if p.resumed == nil {
+		p.resumed = &done
+	}
+	progressDoneGauge.Update(int64(done))
+	if time.Since(p.reported) < progressReportPeriod {
+		return
+	}
+	p.reported = time.Now()
+
+	elapsed := time.Since(p.start)
+	ctx := []interface{}{"name", p.name, "done", p.done}
+	if p.total != 0 {
+		ctx = append(ctx, "total", p.total)
+		if p.done > *p.resumed && p.done < p.total {
+			eta := time.Duration(float64(elapsed) / float64(p.done-*p.resumed) * float64(p.total-p.done))
+			ctx = append(ctx, "eta", common.PrettyDuration(eta))
+		}
+	}
+	ctx = append(ctx, "elapsed", common.PrettyDuration(elapsed))
+	log.Info("Migration progress", ctx...)
+}
@@ -0,0 +1,309 @@
+// Package pebbledb implements the key-value database layer based on Pebble.
+package pebbledb
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/skyhighblockchain/push-base/kvdb"
+)
+
+const (
+	// minCache is the minimum amount of memory in bytes to allocate to pebble
+	// read and write caching. Memtables can't be too small.
+	minCache = 16 * 1024 * 1024
+
+	// minHandles is the minimum number of files handles to allocate to the open
+	// database files.
+	minHandles = 16
+)
+
+// Database is a persistent key-value store. Apart from basic data storage
+// functionality it also supports batch writes and iterating over the keyspace in
+// binary-alphabetical order.
+type Database struct {
+	fn string     // filename for reporting
+	db *pebble.DB // Pebble instance
+
+	quitLock sync.Mutex // Mutex protecting the quit channel access
+
+	onClose func() error
+	onDrop  func()
+}
+
+// New returns a wrapped Pebble object.
+func New(path string, cache int, handles int, close func() error, drop func()) (*Database, error) {
+	// Ensure we have some minimal caching and file guarantees
+	if cache < minCache {
+		cache = minCache
+	}
+	if handles < minHandles {
+		handles = minHandles
+	}
+
+	c := pebble.NewCache(int64(cache / 2))
+	defer c.Unref()
+	db, err := pebble.Open(path, &pebble.Options{
+		Cache:        c,
+		MaxOpenFiles: handles,
+		// two memtables are used at most, so the write buffers take the other half of the cache
+		MemTableSize: cache / 4,
+		Levels: []pebble.LevelOptions{
+			{FilterPolicy: bloom.FilterPolicy(10)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		fn:      path,
+		db:      db,
+		onClose: close,
+		onDrop:  drop,
+	}, nil
+}
+
+// Close flushes any pending data to disk and closes all io accesses to the underlying key-value store.
+func (db *Database) Close() error {
+	db.quitLock.Lock()
+	defer db.quitLock.Unlock()
+
+	if db.db == nil {
+		panic("already closed")
+	}
+
+	pdb := db.db
+	db.db = nil
+
+	if db.onClose != nil {
+		if err := db.onClose(); err != nil {
+			return err
+		}
+		db.onClose = nil
+	}
+	return pdb.Close()
+}
+
+// Drop whole database.
+func (db *Database) Drop() {
+	if db.db != nil {
+		panic("Close database first!")
+	}
+	if db.onDrop != nil {
+		db.onDrop()
+	}
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (db *Database) Has(key []byte) (bool, error) {
+	_, closer, err := db.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, closer.Close()
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (db *Database) Get(key []byte) ([]byte, error) {
+	dat, closer, err := db.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := copyBytes(dat)
+	return res, closer.Close()
+}
+
+// Put inserts the given value into the key-value store.
+func (db *Database) Put(key []byte, value []byte) error {
+	return db.db.Set(key, value, pebble.NoSync)
+}
+
+// Delete removes the key from the key-value store.
+func (db *Database) Delete(key []byte) error {
+	return db.db.Delete(key, pebble.NoSync)
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (db *Database) NewBatch() kvdb.Batch {
+	return &batch{
+		b: db.db.NewBatch(),
+	}
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset
+// of database content with a particular key prefix, starting at a particular
+// initial key (or after, if it does not exist).
+func (db *Database) NewIterator(prefix []byte, start []byte) kvdb.Iterator {
+	return &iterator{
+		it: db.db.NewIter(bytesPrefixRange(prefix, start)),
+	}
+}
+
+// Stat returns the internal metrics of Pebble, regardless of the property.
+func (db *Database) Stat(property string) (string, error) {
+	return db.db.Metrics().String(), nil
+}
+
+// Compact flattens the underlying data store for the given key range. In essence,
+// deleted and overwritten versions are discarded, and the data is rearranged to
+// reduce the cost of operations needed to access them.
+//
+// A nil start is treated as a key before all keys in the data store; a nil limit
+// is treated as a key after all keys in the data store. If both is nil then it
+// will compact entire data store.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	// Pebble has no special value for the end of keys range, so use a key which is greater than any real key
+	if limit == nil {
+		limit = bytes.Repeat([]byte{0xff}, 64)
+	}
+	if start == nil {
+		start = []byte{}
+	}
+	return db.db.Compact(start, limit)
+}
+
+// Path returns the path to the database directory.
+func (db *Database) Path() string {
+	return db.fn
+}
+
+// bytesPrefixRange returns key range that satisfy
+// - the given prefix, and
+// - the given seek position
+func bytesPrefixRange(prefix, start []byte) *pebble.IterOptions {
+	opts := &pebble.IterOptions{
+		LowerBound: append(copyBytes(prefix), start...),
+	}
+	// the upper bound is the prefix incremented as a number
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			limit := copyBytes(prefix[:i+1])
+			limit[i]++
+			opts.UpperBound = limit
+			break
+		}
+	}
+	return opts
+}
+
+func copyBytes(b []byte) []byte {
+	res := make([]byte, len(b))
+	copy(res, b)
+	return res
+}
+
+// batch is a write-only batch that commits changes to its host database
+// when Write is called. A batch cannot be used concurrently.
+type batch struct {
+	b    *pebble.Batch
+	size int
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(key) + len(value)
+	return b.b.Set(key, value, nil)
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *batch) Delete(key []byte) error {
+	b.size += len(key)
+	return b.b.Delete(key, nil)
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	return b.b.Commit(pebble.NoSync)
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.b.Reset()
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w kvdb.Writer) error {
+	reader := b.b.Reader()
+	for {
+		kind, k, v, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		var err error
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			err = w.Put(k, v)
+		case pebble.InternalKeyKindDelete:
+			err = w.Delete(k)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// iterator adapts Pebble iterator to the kvdb.Iterator interface.
+type iterator struct {
+	it      *pebble.Iterator
+	started bool
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *iterator) Next() bool {
+	if !it.started {
+		it.started = true
+		return it.it.First()
+	}
+	return it.it.Next()
+}
+
+// Error returns any accumulated error. Exhausting all the key/value pairs
+// is not considered to be an error.
+func (it *iterator) Error() error {
+	return it.it.Error()
+}
+
+// Key returns the key of the current key/value pair, or nil if done. The caller
+// should not modify the contents of the returned slice, and its contents may
+// change on the next call to Next.
+func (it *iterator) Key() []byte {
+	if !it.it.Valid() {
+		return nil
+	}
+	return it.it.Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done. The
+// caller should not modify the contents of the returned slice, and its contents
+// may change on the next call to Next.
+func (it *iterator) Value() []byte {
+	if !it.it.Valid() {
+		return nil
+	}
+	return it.it.Value()
+}
+
+// Release releases associated resources. Release should always succeed and can
+// be called multiple times without causing error.
+func (it *iterator) Release() {
+	if it.it != nil {
+		_ = it.it.Close()
+		it.it = nil
+	}
+}
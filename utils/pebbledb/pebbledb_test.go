@@ -0,0 +1,80 @@
+package pebbledb
+
+import (
+	"testing"
+
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabase(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	db, err := New(dir, 0, 0, nil, nil)
+	require.NoError(err)
+
+	// get/put/delete
+	val, err := db.Get([]byte("a"))
+	require.NoError(err)
+	require.Nil(val)
+	require.NoError(db.Put([]byte("a"), []byte("1")))
+	val, err = db.Get([]byte("a"))
+	require.NoError(err)
+	require.Equal([]byte("1"), val)
+	has, err := db.Has([]byte("a"))
+	require.NoError(err)
+	require.True(has)
+	require.NoError(db.Delete([]byte("a")))
+	has, err = db.Has([]byte("a"))
+	require.NoError(err)
+	require.False(has)
+
+	// batch
+	batch := db.NewBatch()
+	for _, key := range []string{"a1", "a2", "a3", "b1", "\xff\xff"} {
+		require.NoError(batch.Put([]byte(key), []byte(key)))
+	}
+	require.NoError(batch.Delete([]byte("a3")))
+	require.NotZero(batch.ValueSize())
+	replayed := memorydb.New()
+	require.NoError(batch.Replay(replayed))
+	require.NoError(batch.Write())
+	batch.Reset()
+	require.Zero(batch.ValueSize())
+
+	collect := func(prefix, start []byte) []string {
+		var keys []string
+		it := db.NewIterator(prefix, start)
+		defer it.Release()
+		for it.Next() {
+			require.Equal(it.Key(), it.Value())
+			keys = append(keys, string(it.Key()))
+		}
+		require.NoError(it.Error())
+		return keys
+	}
+	require.Equal([]string{"a1", "a2", "b1", "\xff\xff"}, collect(nil, nil))
+	require.Equal([]string{"a1", "a2"}, collect([]byte("a"), nil))
+	require.Equal([]string{"a2"}, collect([]byte("a"), []byte("2")))
+	require.Equal([]string{"\xff\xff"}, collect([]byte("\xff"), nil))
+	require.Empty(collect([]byte("c"), nil))
+
+	val, err = replayed.Get([]byte("a2"))
+	require.NoError(err)
+	require.Equal([]byte("a2"), val)
+	has, err = replayed.Has([]byte("a3"))
+	require.NoError(err)
+	require.False(has)
+
+	require.NoError(db.Compact(nil, nil))
+	_, err = db.Stat("")
+	require.NoError(err)
+
+	// data persists after reopening
+	require.NoError(db.Close())
+	db, err = New(dir, 0, 0, nil, nil)
+	require.NoError(err)
+	require.Equal([]string{"a1", "a2", "b1", "\xff\xff"}, collect(nil, nil))
+	require.NoError(db.Close())
+}
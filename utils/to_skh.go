@@ -1,8 +1,47 @@
 package utils
 
-import "math/big"
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
 
 // ToSkh number of SKH to Wei
 func ToSkh(skh uint64) *big.Int {
 	return new(big.Int).Mul(new(big.Int).SetUint64(skh), big.NewInt(1e18))
 }
+
+// ParseSkh parses a decimal number of SKH, e.g. "1.5", into Wei
+func ParseSkh(s string) (*big.Int, error) {
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if len(fracPart) > 18 {
+		return nil, errors.New("too many decimals in " + s)
+	}
+	digits := intPart + fracPart
+	if len(digits) == 0 || strings.Trim(digits, "0123456789") != "" {
+		return nil, errors.New("invalid amount " + s)
+	}
+	wei, _ := new(big.Int).SetString(digits+strings.Repeat("0", 18-len(fracPart)), 10)
+	return wei, nil
+}
+
+// FormatSkh formats Wei as a decimal number of SKH without trailing zeros
+func FormatSkh(wei *big.Int) string {
+	if wei == nil {
+		return "0"
+	}
+	abs := new(big.Int).Abs(wei)
+	intPart, fracPart := new(big.Int).QuoRem(abs, big.NewInt(1e18), new(big.Int))
+	s := intPart.String()
+	if fracPart.Sign() != 0 {
+		frac := fracPart.String()
+		s += "." + strings.TrimRight(strings.Repeat("0", 18-len(frac))+frac, "0")
+	}
+	if wei.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
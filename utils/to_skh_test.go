@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSkh(t *testing.T) {
+	require := require.New(t)
+
+	for s, exp := range map[string]*big.Int{
+		"0":                    big.NewInt(0),
+		"1":                    ToSkh(1),
+		"1.5":                  big.NewInt(1.5e18),
+		".25":                  big.NewInt(0.25e18),
+		"10.":                  ToSkh(10),
+		"0.000000000000000001": big.NewInt(1),
+	} {
+		got, err := ParseSkh(s)
+		require.NoError(err, s)
+		require.Equal(exp.String(), got.String(), s)
+	}
+	for _, s := range []string{"", "-1", "+1", "1.2.3", "abc", "0.0000000000000000001"} {
+		_, err := ParseSkh(s)
+		require.Error(err, s)
+	}
+}
+
+func TestFormatSkh(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("0", FormatSkh(nil))
+	require.Equal("0", FormatSkh(big.NewInt(0)))
+	require.Equal("1", FormatSkh(ToSkh(1)))
+	require.Equal("1.5", FormatSkh(big.NewInt(1.5e18)))
+	require.Equal("0.000000000000000001", FormatSkh(big.NewInt(1)))
+	require.Equal("-2.25", FormatSkh(big.NewInt(-2.25e18)))
+}
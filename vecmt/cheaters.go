@@ -0,0 +1,23 @@
+package vecmt
+
+import (
+	"github.com/skyhighblockchain/push-base/inter/idx"
+)
+
+// Cheaters returns validators whose forks are observed by the index in the current epoch.
+// Unlike NoCheaters, the result doesn't depend on a point of view of a specific event.
+func (vi *Index) Cheaters() []idx.ValidatorID {
+	vi.InitBranchesInfo()
+
+	if !vi.Engine.AtLeastOneFork() {
+		return nil
+	}
+
+	cheaters := make([]idx.ValidatorID, 0, 4)
+	for creatorIdx, branches := range vi.Engine.BranchesInfo().BranchIDByCreators {
+		if len(branches) > 1 {
+			cheaters = append(cheaters, vi.validators.GetID(idx.Validator(creatorIdx)))
+		}
+	}
+	return cheaters
+}
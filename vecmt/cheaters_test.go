@@ -0,0 +1,36 @@
+package vecmt
+
+import (
+	"testing"
+
+	"github.com/skyhighblockchain/push-base/hash"
+	"github.com/skyhighblockchain/push-base/inter/dag"
+	"github.com/skyhighblockchain/push-base/inter/dag/tdag"
+	"github.com/skyhighblockchain/push-base/inter/pos"
+	"github.com/skyhighblockchain/push-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/skyhighblockchain/skyhigh/inter"
+)
+
+func TestIndex_Cheaters(t *testing.T) {
+	nodes := tdag.GenNodes(5)
+	cheaters := nodes[:2]
+	validators := pos.EqualWeightValidators(nodes, 1)
+
+	events := make(map[hash.Event]dag.Event)
+	vi := NewIndex(func(err error) { panic(err) }, LiteConfig())
+	vi.Reset(validators, memorydb.New(), func(id hash.Event) dag.Event {
+		return events[id]
+	})
+	require.Empty(t, vi.Cheaters())
+
+	tdag.ForEachRandFork(nodes, cheaters, 30, 3, 5, nil, tdag.ForEachEvent{
+		Process: func(e dag.Event, name string) {
+			events[e.ID()] = e
+			require.NoError(t, vi.Add(&eventWithCreationTime{e, inter.Timestamp(e.Seq())}))
+		},
+	})
+
+	require.ElementsMatch(t, cheaters, vi.Cheaters())
+}